@@ -7,6 +7,7 @@ import (
 
 	"mole-wails/backend/analyze"
 	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
 	"mole-wails/backend/services"
 	"mole-wails/backend/status"
 )
@@ -22,6 +23,11 @@ type App struct {
 	Analyze   *analyze.Service
 	Status    *status.Service
 	TouchID   *services.TouchIDService
+	Orphans   *services.OrphanService
+	Startup   *services.StartupService
+	Check     *services.CheckService
+	Prefs     *services.PreferenceService
+	Broker    *privileged.Broker
 }
 
 // NewApp creates a new App application struct
@@ -29,13 +35,21 @@ func NewApp() *App {
 	// Determine scripts path
 	scriptsPath := getScriptsPath()
 
+	uninstall := services.NewUninstallService(scriptsPath)
+	broker := privileged.NewBroker()
+
 	return &App{
-		Clean:     services.NewCleanService(scriptsPath),
-		Uninstall: services.NewUninstallService(scriptsPath),
-		Optimize:  services.NewOptimizeService(scriptsPath),
+		Clean:     services.NewCleanService(scriptsPath, broker),
+		Uninstall: uninstall,
+		Optimize:  services.NewOptimizeService(scriptsPath, broker),
 		Analyze:   analyze.NewService(),
 		Status:    status.NewService(),
-		TouchID:   services.NewTouchIDService(scriptsPath),
+		TouchID:   services.NewTouchIDService(broker),
+		Orphans:   services.NewOrphanService(uninstall),
+		Startup:   services.NewStartupService(uninstall, broker),
+		Check:     services.NewCheckService(broker),
+		Prefs:     services.NewPreferenceService(uninstall),
+		Broker:    broker,
 	}
 }
 
@@ -50,12 +64,17 @@ func (a *App) startup(ctx context.Context) {
 	a.Analyze.SetContext(ctx)
 	a.Status.SetContext(ctx)
 	a.TouchID.SetContext(ctx)
+	a.Orphans.SetContext(ctx)
+	a.Startup.SetContext(ctx)
+	a.Check.SetContext(ctx)
+	a.Prefs.SetContext(ctx)
 }
 
 // shutdown is called when the app shuts down
 func (a *App) shutdown(ctx context.Context) {
 	// Cleanup
 	a.Status.StopMonitoring()
+	a.Broker.Close()
 }
 
 // Helper function to determine scripts path
@@ -106,6 +125,22 @@ func (a *App) UninstallGetRelatedFiles(bundleID string) ([]string, error) {
 	return a.Uninstall.GetRelatedFiles(bundleID)
 }
 
+func (a *App) UninstallGetUnusedApps(minMonths int) ([]models.UnusedApp, error) {
+	return a.Uninstall.GetUnusedApps(minMonths)
+}
+
+func (a *App) UninstallGetDuplicateApps() ([]models.DuplicateAppGroup, error) {
+	return a.Uninstall.GetDuplicateApps()
+}
+
+func (a *App) UninstallExportInventory(format string, destination string) (string, error) {
+	return a.Uninstall.ExportInventory(format, destination)
+}
+
+func (a *App) UninstallGenerateBrewfile(destination string) (string, error) {
+	return a.Uninstall.GenerateBrewfile(destination)
+}
+
 // ===========================
 // Optimize Service Methods
 // ===========================
@@ -118,6 +153,10 @@ func (a *App) OptimizeExecute(taskIDs []string) error {
 	return a.Optimize.ExecuteOptimizations(taskIDs)
 }
 
+func (a *App) OptimizeGetHistory() ([]models.OptimizeHistoryEntry, error) {
+	return a.Optimize.GetHistory()
+}
+
 func (a *App) OptimizeGetWhitelist() ([]string, error) {
 	return a.Optimize.GetWhitelist()
 }
@@ -134,6 +173,10 @@ func (a *App) AnalyzeScanDirectory(path string) (*models.ScanResult, error) {
 	return a.Analyze.ScanDirectory(path)
 }
 
+func (a *App) AnalyzeScanDirectoryWithOptions(path string, options models.ScanOptions) (*models.ScanResult, error) {
+	return a.Analyze.ScanDirectoryWithOptions(path, options)
+}
+
 func (a *App) AnalyzeGetLargeFiles(path string, limit int) ([]models.FileEntry, error) {
 	return a.Analyze.GetLargeFiles(path, limit)
 }
@@ -146,6 +189,62 @@ func (a *App) AnalyzeOpenInFinder(path string) error {
 	return a.Analyze.OpenInFinder(path)
 }
 
+func (a *App) AnalyzeCancelScan() {
+	a.Analyze.CancelScan()
+}
+
+func (a *App) AnalyzeGetScanTree(path string, depth int, options models.ScanOptions) (*models.ScanNode, error) {
+	return a.Analyze.GetScanTree(path, depth, options)
+}
+
+func (a *App) AnalyzeSetNodeBudget(budget int) {
+	a.Analyze.SetNodeBudget(budget)
+}
+
+func (a *App) AnalyzeTreemap(path string, width, height float64, depth int, minPixels float64, options models.ScanOptions) ([]models.TreemapRect, error) {
+	return a.Analyze.Treemap(path, width, height, depth, minPixels, options)
+}
+
+func (a *App) AnalyzeSunburst(path string, radius float64, depth int, minPixels float64, options models.ScanOptions) ([]models.SunburstArc, error) {
+	return a.Analyze.Sunburst(path, radius, depth, minPixels, options)
+}
+
+func (a *App) AnalyzeSaveSnapshot(path, name string, options models.ScanOptions) (models.ScanSnapshot, error) {
+	return a.Analyze.SaveSnapshot(path, name, options)
+}
+
+func (a *App) AnalyzeListSnapshots(path string) ([]models.ScanSnapshot, error) {
+	return a.Analyze.ListSnapshots(path)
+}
+
+func (a *App) AnalyzeDeleteSnapshot(id string) error {
+	return a.Analyze.DeleteSnapshot(id)
+}
+
+func (a *App) AnalyzeDiffSnapshots(fromID, toID, subPath string, limit int) (*models.ScanDiff, error) {
+	return a.Analyze.DiffSnapshots(fromID, toID, subPath, limit)
+}
+
+func (a *App) AnalyzeGetSizeTrends(days int) ([]models.SizeTrend, error) {
+	return a.Analyze.GetSizeTrends(days)
+}
+
+func (a *App) AnalyzeForecastVolumeFull(days int) (*models.VolumeForecast, error) {
+	return a.Analyze.ForecastVolumeFull(days)
+}
+
+func (a *App) AnalyzeWatchCaches(enabled bool) error {
+	return a.Analyze.WatchCaches(enabled)
+}
+
+func (a *App) AnalyzeGetCacheInfo() (*models.ScanCacheInfo, error) {
+	return a.Analyze.GetCacheInfo()
+}
+
+func (a *App) AnalyzeClearCache() error {
+	return a.Analyze.ClearCache()
+}
+
 // ===========================
 // Status Service Methods
 // ===========================
@@ -177,3 +276,79 @@ func (a *App) TouchIDEnable() error {
 func (a *App) TouchIDDisable() error {
 	return a.TouchID.Disable()
 }
+
+// ===========================
+// Orphan Service Methods
+// ===========================
+
+func (a *App) OrphansScan() ([]models.OrphanVendorGroup, error) {
+	return a.Orphans.ScanOrphans()
+}
+
+// ===========================
+// Startup Service Methods
+// ===========================
+
+func (a *App) StartupListItems() ([]models.StartupItem, error) {
+	return a.Startup.ListItems()
+}
+
+func (a *App) StartupDisableItem(id string) error {
+	return a.Startup.DisableItem(id)
+}
+
+func (a *App) StartupEnableItem(id string) error {
+	return a.Startup.EnableItem(id)
+}
+
+func (a *App) StartupRemoveItem(id string) error {
+	return a.Startup.RemoveItem(id)
+}
+
+// ===========================
+// Check Service Methods
+// ===========================
+
+func (a *App) CheckRunAll() ([]models.CheckResult, error) {
+	return a.Check.RunAll()
+}
+
+func (a *App) CheckRun(id string) (models.CheckResult, error) {
+	return a.Check.RunCheck(id)
+}
+
+func (a *App) CheckApplyFix(id string) (models.CheckFixResult, error) {
+	return a.Check.ApplyFix(id)
+}
+
+// ===========================
+// Preference Service Methods
+// ===========================
+
+func (a *App) PreferencesScanBroken() ([]models.BrokenPreference, error) {
+	return a.Prefs.ScanBroken()
+}
+
+func (a *App) PreferencesRepair(paths []string) ([]models.PreferenceRepair, error) {
+	return a.Prefs.Repair(paths)
+}
+
+func (a *App) PreferencesGetRepairLog() ([]models.PreferenceRepair, error) {
+	return a.Prefs.GetRepairLog()
+}
+
+func (a *App) PreferencesRestore(id string) error {
+	return a.Prefs.Restore(id)
+}
+
+// ===========================
+// Privileged Helper Methods
+// ===========================
+
+func (a *App) PrivilegedAuthenticate() error {
+	return a.Broker.Start(a.ctx)
+}
+
+func (a *App) PrivilegedIsAuthenticated() bool {
+	return a.Broker.Running()
+}
@@ -0,0 +1,17 @@
+//go:build linux
+
+package analyze
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(stat.Atim.Sec, stat.Atim.Nsec)
+}
@@ -1,6 +1,7 @@
 package analyze
 
 import (
+	"bytes"
 	"context"
 	"encoding/gob"
 	"encoding/json"
@@ -9,8 +10,6 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
-
-	"github.com/cespare/xxhash/v2"
 )
 
 type overviewSizeSnapshot struct {
@@ -168,68 +167,48 @@ func getCacheDir() (string, error) {
 	return cacheDir, nil
 }
 
-func getCachePath(path string) (string, error) {
-	cacheDir, err := getCacheDir()
-	if err != nil {
-		return "", err
-	}
-	hash := xxhash.Sum64String(path)
-	filename := fmt.Sprintf("%x.cache", hash)
-	return filepath.Join(cacheDir, filename), nil
-}
-
+// loadCacheFromDisk loads the default scan of path for callers that only need
+// its size. Without a revalidation walk it is only trusted while the root
+// directory is untouched.
 func loadCacheFromDisk(path string) (*cacheEntry, error) {
-	cachePath, err := getCachePath(path)
+	entry, err := loadCacheEntry(path, path)
 	if err != nil {
 		return nil, err
 	}
 
-	file, err := os.Open(cachePath)
+	info, err := os.Stat(path)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	var entry cacheEntry
-	decoder := gob.NewDecoder(file)
-	if err := decoder.Decode(&entry); err != nil {
-		return nil, err
-	}
-
-	// Validate cache integrity - check if LargeFiles have valid sizes
-	// This catches corrupted cache files from older versions
-	for _, f := range entry.LargeFiles {
-		if f.Size == 0 && f.Path != "" {
-			// Invalid cache - file has path but no size
-			return nil, fmt.Errorf("cache corrupted: invalid file sizes")
-		}
+	if info.ModTime().After(entry.ModTime) {
+		return nil, fmt.Errorf("cache expired: directory modified")
 	}
+	return entry, nil
+}
 
-	info, err := os.Stat(path)
+// loadCacheEntry loads the scan of path stored under key (see scanOptions.cacheKey).
+// It is not checked against the disk; scanDirectoryInternal revalidates it.
+func loadCacheEntry(path, key string) (*cacheEntry, error) {
+	payload, err := storeGet(key)
 	if err != nil {
 		return nil, err
 	}
 
-	if info.ModTime().After(entry.ModTime) {
-		// Only expire cache if the directory has been newer for longer than the grace window.
-		if cacheModTimeGrace <= 0 || info.ModTime().Sub(entry.ModTime) > cacheModTimeGrace {
-			return nil, fmt.Errorf("cache expired: directory modified")
-		}
+	var entry cacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&entry); err != nil {
+		storeDelete(key)
+		return nil, fmt.Errorf("failed to decode cache entry: %w", err)
 	}
 
-	if time.Since(entry.ScanTime) > 7*24*time.Hour {
+	// Revalidation cannot see files that change in place, so old entries are rescanned
+	if time.Since(entry.ScanTime) > cacheMaxAge {
 		return nil, fmt.Errorf("cache expired: too old")
 	}
 
 	return &entry, nil
 }
 
-func saveCacheToDisk(path string, result scanResult) error {
-	cachePath, err := getCachePath(path)
-	if err != nil {
-		return err
-	}
-
+func saveCacheToDisk(path, key string, result scanResult) error {
 	info, err := os.Stat(path)
 	if err != nil {
 		return err
@@ -238,29 +217,36 @@ func saveCacheToDisk(path string, result scanResult) error {
 	entry := cacheEntry{
 		Entries:    result.Entries,
 		LargeFiles: result.LargeFiles,
+		Types:      result.Types,
 		TotalSize:  result.TotalSize,
+		Tree:       result.Tree,
+		Mounts:     result.Mounts,
+		Hidden:     result.Hidden,
+		Indexed:    result.Indexed,
 		ModTime:    info.ModTime(),
-		ScanTime:   time.Now(),
+		ScanTime:   result.Scanned,
 	}
-
-	file, err := os.Create(cachePath)
-	if err != nil {
-		return err
+	if entry.ScanTime.IsZero() {
+		entry.ScanTime = time.Now()
 	}
-	defer file.Close()
 
-	encoder := gob.NewEncoder(file)
-	return encoder.Encode(entry)
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(entry); err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+	return storePut(key, path, payload.Bytes())
 }
 
 func invalidateCache(path string) {
-	cachePath, err := getCachePath(path)
-	if err == nil {
-		_ = os.Remove(cachePath)
-	}
+	removeCacheEntry(path)
 	removeOverviewSnapshot(path)
 }
 
+// removeCacheEntry deletes the disk cache stored under key
+func removeCacheEntry(key string) {
+	storeDelete(key)
+}
+
 func removeOverviewSnapshot(path string) {
 	if path == "" {
 		return
@@ -308,7 +294,7 @@ func prefetchOverviewCache(ctx context.Context) {
 		default:
 		}
 
-		size, err := measureOverviewSize(path)
+		size, err := measureOverviewSize(ctx, path)
 		if err == nil && size > 0 {
 			_ = storeOverviewSize(path, size)
 		}
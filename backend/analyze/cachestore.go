@@ -0,0 +1,338 @@
+package analyze
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"mole-wails/backend/models"
+)
+
+// Scan cache store
+//
+// Cached scans live in ~/.cache/mole/analyze. index.json lists every entry
+// with its size, checksum and last use; each entry is a <hash>.scan file that
+// starts with a header (magic, format version, payload length, xxhash of the
+// payload) followed by the gob payload. Files that fail any of these checks are
+// dropped instead of decoded. The store stays under cacheStoreBudget bytes by
+// evicting the least recently used entries. Reads only note the time of use in
+// memory; it reaches the index with the next write, so a cache hit costs no
+// index rewrite.
+
+const (
+	cacheStoreDir    = "analyze"
+	cacheIndexFile   = "index.json"
+	cacheIndexVer    = 1
+	cacheBlobExt     = ".scan"
+	cacheBlobMagic   = "MOLESCAN"
+	cacheHeaderSize  = len(cacheBlobMagic) + 4 + 8 + 8
+	cacheStoreBudget = 512 << 20 // 512 MB
+)
+
+type cacheIndex struct {
+	Version int                         `json:"version"`
+	Entries map[string]*cacheIndexEntry `json:"entries"` // By cache key
+}
+
+type cacheIndexEntry struct {
+	File     string    `json:"file"`
+	Path     string    `json:"path"`
+	Bytes    int64     `json:"bytes"`
+	Checksum uint64    `json:"checksum"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+var (
+	cacheStoreMu sync.Mutex
+	cacheLastUse = make(map[string]time.Time) // Uses not yet in the index, by cache key
+)
+
+// storeGet returns the payload stored under key
+func storeGet(key string) ([]byte, error) {
+	cacheStoreMu.Lock()
+	defer cacheStoreMu.Unlock()
+
+	dir, index, err := loadCacheIndexLocked()
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := index.Entries[key]
+	if !ok {
+		return nil, fmt.Errorf("cache miss: %s", key)
+	}
+
+	payload, err := readCacheBlob(filepath.Join(dir, entry.File), entry.Checksum)
+	if err != nil {
+		_ = os.Remove(filepath.Join(dir, entry.File))
+		delete(index.Entries, key)
+		_ = saveCacheIndexLocked(dir, index)
+		return nil, err
+	}
+
+	cacheLastUse[key] = time.Now()
+	return payload, nil
+}
+
+// storePut stores payload under key, evicting least recently used entries
+// to stay within the budget
+func storePut(key, path string, payload []byte) error {
+	if int64(cacheHeaderSize+len(payload)) > cacheStoreBudget {
+		return fmt.Errorf("cache entry too large: %d bytes", len(payload))
+	}
+
+	cacheStoreMu.Lock()
+	defer cacheStoreMu.Unlock()
+
+	dir, index, err := loadCacheIndexLocked()
+	if err != nil {
+		return err
+	}
+
+	file := fmt.Sprintf("%x%s", xxhash.Sum64String(key), cacheBlobExt)
+	checksum := xxhash.Sum64(payload)
+	if err := writeFileAtomic(filepath.Join(dir, file), func(f *os.File) error {
+		return writeCacheBlob(f, payload, checksum)
+	}); err != nil {
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	now := time.Now()
+	index.Entries[key] = &cacheIndexEntry{
+		File:     file,
+		Path:     path,
+		Bytes:    int64(cacheHeaderSize + len(payload)),
+		Checksum: checksum,
+		Created:  now,
+		LastUsed: now,
+	}
+	delete(cacheLastUse, key)
+	evictCacheEntriesLocked(dir, index, key)
+	return saveCacheIndexLocked(dir, index)
+}
+
+// storeDelete removes the entry stored under key
+func storeDelete(key string) {
+	cacheStoreMu.Lock()
+	defer cacheStoreMu.Unlock()
+
+	dir, index, err := loadCacheIndexLocked()
+	if err != nil {
+		return
+	}
+	if entry, ok := index.Entries[key]; ok {
+		_ = os.Remove(filepath.Join(dir, entry.File))
+		delete(index.Entries, key)
+		_ = saveCacheIndexLocked(dir, index)
+	}
+}
+
+// GetCacheInfo reports the size of the analyze disk cache, most recently used first
+func (s *Service) GetCacheInfo() (*models.ScanCacheInfo, error) {
+	cacheStoreMu.Lock()
+	defer cacheStoreMu.Unlock()
+
+	dir, index, err := loadCacheIndexLocked()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scan cache: %w", err)
+	}
+
+	info := &models.ScanCacheInfo{
+		Dir:     dir,
+		Budget:  cacheStoreBudget,
+		Entries: make([]models.ScanCacheEntry, 0, len(index.Entries)),
+	}
+	applyLastUseLocked(index)
+	for _, entry := range index.Entries {
+		info.Bytes += entry.Bytes
+		info.Entries = append(info.Entries, models.ScanCacheEntry{
+			Path:     entry.Path,
+			Bytes:    entry.Bytes,
+			Created:  entry.Created,
+			LastUsed: entry.LastUsed,
+		})
+	}
+	sort.Slice(info.Entries, func(i, j int) bool {
+		return info.Entries[i].LastUsed.After(info.Entries[j].LastUsed)
+	})
+	return info, nil
+}
+
+// ClearCache drops every cached scan, on disk and in memory
+func (s *Service) ClearCache() error {
+	s.cache.clear()
+
+	cacheStoreMu.Lock()
+	defer cacheStoreMu.Unlock()
+
+	dir, err := getCacheStoreDir()
+	if err != nil {
+		return fmt.Errorf("failed to locate scan cache: %w", err)
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clear scan cache: %w", err)
+	}
+	clear(cacheLastUse)
+	return nil
+}
+
+// applyLastUseLocked moves the uses noted by storeGet into index
+func applyLastUseLocked(index *cacheIndex) {
+	for key, used := range cacheLastUse {
+		if entry, ok := index.Entries[key]; ok && used.After(entry.LastUsed) {
+			entry.LastUsed = used
+		}
+	}
+}
+
+// evictCacheEntriesLocked removes least recently used entries other than keep
+// until the store fits the budget
+func evictCacheEntriesLocked(dir string, index *cacheIndex, keep string) {
+	applyLastUseLocked(index)
+
+	var total int64
+	keys := make([]string, 0, len(index.Entries))
+	for key, entry := range index.Entries {
+		total += entry.Bytes
+		if key != keep {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return index.Entries[keys[i]].LastUsed.Before(index.Entries[keys[j]].LastUsed)
+	})
+
+	for _, key := range keys {
+		if total <= cacheStoreBudget {
+			return
+		}
+		entry := index.Entries[key]
+		_ = os.Remove(filepath.Join(dir, entry.File))
+		total -= entry.Bytes
+		delete(index.Entries, key)
+	}
+}
+
+// Blob format
+
+func writeCacheBlob(f *os.File, payload []byte, checksum uint64) error {
+	header := make([]byte, 0, cacheHeaderSize)
+	header = append(header, cacheBlobMagic...)
+	header = binary.BigEndian.AppendUint32(header, cacheFormatVersion)
+	header = binary.BigEndian.AppendUint64(header, uint64(len(payload)))
+	header = binary.BigEndian.AppendUint64(header, checksum)
+	if _, err := f.Write(header); err != nil {
+		return err
+	}
+	_, err := f.Write(payload)
+	return err
+}
+
+// readCacheBlob returns the payload of a blob after checking its header
+// against the running build and the checksum recorded in the index
+func readCacheBlob(path string, checksum uint64) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < cacheHeaderSize || !bytes.Equal(data[:len(cacheBlobMagic)], []byte(cacheBlobMagic)) {
+		return nil, fmt.Errorf("cache corrupted: bad header")
+	}
+
+	header := data[len(cacheBlobMagic):cacheHeaderSize]
+	if version := binary.BigEndian.Uint32(header[0:4]); version != cacheFormatVersion {
+		return nil, fmt.Errorf("cache outdated: format version %d", version)
+	}
+	length := binary.BigEndian.Uint64(header[4:12])
+	sum := binary.BigEndian.Uint64(header[12:20])
+
+	payload := data[cacheHeaderSize:]
+	if uint64(len(payload)) != length || sum != checksum || xxhash.Sum64(payload) != sum {
+		return nil, fmt.Errorf("cache corrupted: checksum mismatch")
+	}
+	return payload, nil
+}
+
+// Index
+
+func getCacheStoreDir() (string, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, cacheStoreDir), nil
+}
+
+// loadCacheIndexLocked reads the index, starting an empty store when it is
+// missing, unreadable or from another version
+func loadCacheIndexLocked() (string, *cacheIndex, error) {
+	dir, err := getCacheStoreDir()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", nil, err
+	}
+
+	index := &cacheIndex{Version: cacheIndexVer, Entries: make(map[string]*cacheIndexEntry)}
+	data, err := os.ReadFile(filepath.Join(dir, cacheIndexFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", nil, err
+		}
+		removeLegacyCacheFiles()
+		return dir, index, nil
+	}
+
+	var loaded cacheIndex
+	if err := json.Unmarshal(data, &loaded); err != nil || loaded.Version != cacheIndexVer || loaded.Entries == nil {
+		// Blobs without a usable index cannot be trusted or evicted
+		removeCacheBlobs(dir)
+		return dir, index, nil
+	}
+	return dir, &loaded, nil
+}
+
+// saveCacheIndexLocked writes index with the uses noted since the last write
+func saveCacheIndexLocked(dir string, index *cacheIndex) error {
+	applyLastUseLocked(index)
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, cacheIndexFile), func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	}); err != nil {
+		return err
+	}
+	clear(cacheLastUse)
+	return nil
+}
+
+func removeCacheBlobs(dir string) {
+	blobs, _ := filepath.Glob(filepath.Join(dir, "*"+cacheBlobExt))
+	for _, blob := range blobs {
+		_ = os.Remove(blob)
+	}
+}
+
+// removeLegacyCacheFiles deletes the one-gob-per-path files older builds
+// wrote straight into the cache directory
+func removeLegacyCacheFiles() {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return
+	}
+	legacy, _ := filepath.Glob(filepath.Join(cacheDir, "*.cache"))
+	for _, file := range legacy {
+		_ = os.Remove(file)
+	}
+}
@@ -0,0 +1,111 @@
+package analyze
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+func writeTestBlob(t *testing.T, payload []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "entry"+cacheBlobExt)
+	if err := writeFileAtomic(path, func(f *os.File) error {
+		return writeCacheBlob(f, payload, xxhash.Sum64(payload))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCacheBlobRoundTrip(t *testing.T) {
+	payload := []byte("scan payload")
+	path := writeTestBlob(t, payload)
+
+	got, err := readCacheBlob(path, xxhash.Sum64(payload))
+	if err != nil || string(got) != string(payload) {
+		t.Fatalf("got %q, %v; want %q", got, err, payload)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected the temporary file to be gone, got %v", err)
+	}
+}
+
+func TestReadCacheBlobRejectsBadBlobs(t *testing.T) {
+	payload := []byte("scan payload")
+	checksum := xxhash.Sum64(payload)
+	magicEnd := len(cacheBlobMagic)
+
+	tests := []struct {
+		name     string
+		corrupt  func(data []byte) []byte
+		checksum uint64
+		want     string
+	}{
+		{"bad magic", func(data []byte) []byte { data[0] ^= 0xff; return data }, checksum, "bad header"},
+		{"short header", func(data []byte) []byte { return data[:cacheHeaderSize-1] }, checksum, "bad header"},
+		{"other version", func(data []byte) []byte {
+			binary.BigEndian.PutUint32(data[magicEnd:], cacheFormatVersion+1)
+			return data
+		}, checksum, "format version"},
+		{"truncated payload", func(data []byte) []byte { return data[:len(data)-1] }, checksum, "checksum mismatch"},
+		{"flipped payload byte", func(data []byte) []byte { data[cacheHeaderSize] ^= 0xff; return data }, checksum, "checksum mismatch"},
+		{"index checksum differs", func(data []byte) []byte { return data }, checksum + 1, "checksum mismatch"},
+	}
+
+	for _, tt := range tests {
+		path := writeTestBlob(t, payload)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, tt.corrupt(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := readCacheBlob(path, tt.checksum); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Fatalf("%s: got error %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestStoreGetDefersLastUsed(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Cleanup(func() { clear(cacheLastUse) })
+
+	if err := storePut("a", "/a", []byte("payload")); err != nil {
+		t.Fatal(err)
+	}
+	dir, index, err := loadCacheIndexLocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := index.Entries["a"].LastUsed
+	indexInfo, err := os.Stat(filepath.Join(dir, cacheIndexFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	if _, err := storeGet("a"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, cacheIndexFile)); err != nil || !info.ModTime().Equal(indexInfo.ModTime()) {
+		t.Fatalf("expected a cache hit to leave the index alone")
+	}
+
+	if err := storePut("b", "/b", []byte("payload")); err != nil {
+		t.Fatal(err)
+	}
+	_, index, err = loadCacheIndexLocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !index.Entries["a"].LastUsed.After(stored) {
+		t.Fatalf("expected the use of a to reach the index with the next write")
+	}
+}
@@ -12,9 +12,8 @@ const (
 	overviewCacheFile     = "overview_sizes.json"
 	duTimeout             = 30 * time.Second // Fail faster to fallback to concurrent scan
 	mdlsTimeout           = 5 * time.Second
-	maxConcurrentOverview = 8                // Increased parallel overview scans
-	batchUpdateSize       = 100              // Batch atomic updates every N items
-	cacheModTimeGrace     = 30 * time.Minute // Ignore minor directory mtime bumps
+	maxConcurrentOverview = 8   // Increased parallel overview scans
+	batchUpdateSize       = 100 // Batch atomic updates every N items
 
 	// Worker pool configuration
 	minWorkers         = 16               // Safe baseline for older machines
@@ -22,6 +21,19 @@ const (
 	cpuMultiplier      = 4                // Balanced CPU usage
 	maxDirWorkers      = 32               // Limit concurrent subdirectory scans
 	openCommandTimeout = 10 * time.Second // Timeout for open/reveal commands
+
+	// Scan cache configuration
+	cacheFormatVersion   = 5                  // Cache blob version; bump when cacheEntry or treeNode change meaning
+	cacheMaxAge          = 7 * 24 * time.Hour // Full rescan after this; revalidation misses in-place file growth
+	cacheRevalidateAfter = time.Minute        // In-memory scans are trusted this long
+	watchRefreshInterval = 30 * time.Second   // Watcher refreshes of one scan are at least this far apart
+
+	// Scan tree configuration
+	maxTreeChildren       = 64     // Children kept per directory before folding into "other"
+	defaultTreeNodeBudget = 200000 // Nodes kept per scan tree
+	maxIndexedDirs        = 250000 // Directories outside the tree stamped for revalidation
+	otherNodeName         = "Other"
+	defaultLayoutDepth    = 3 // Levels drawn by treemap/sunburst layouts
 )
 
 var foldDirs = map[string]bool{
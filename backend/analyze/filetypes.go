@@ -0,0 +1,403 @@
+package analyze
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"mole-wails/backend/models"
+)
+
+const (
+	maxTypeTopFiles = 10       // Largest files listed per category
+	sniffMinSize    = 64 << 10 // Files without an extension are sniffed from this size up
+	sniffHeaderSize = 512      // Bytes read for content sniffing
+	tsPacketSize    = 188      // MPEG transport stream packet, each starting with tsSyncByte
+	tsSyncByte      = 0x47
+)
+
+// File type categories, in the order they are reported
+const (
+	categoryVideo = iota
+	categoryImages
+	categoryAudio
+	categoryArchives
+	categoryDiskImages
+	categoryCode
+	categoryDocuments
+	categoryAppBundles
+	categoryOther
+	categoryUnscanned // Directories sized without a walk (folded, or sized from a cache)
+	numFileCategories
+)
+
+var fileCategoryIDs = [numFileCategories]string{
+	"video", "images", "audio", "archives", "disk_images", "code", "documents", "app_bundles", "other", "unscanned",
+}
+
+var fileCategoryLabels = [numFileCategories]string{
+	"Video", "Images", "Audio", "Archives", "Disk Images", "Code", "Documents", "App Bundles", "Other", "Not Scanned",
+}
+
+var fileCategoryByExt = buildCategoryTable(map[int][]string{
+	categoryVideo: {
+		".mp4", ".m4v", ".mov", ".avi", ".mkv", ".wmv", ".flv", ".webm", ".mpg", ".mpeg",
+		".3gp", ".mts", ".m2ts", ".vob", ".ogv", ".prores", ".braw", ".r3d",
+	},
+	categoryImages: {
+		".jpg", ".jpeg", ".png", ".gif", ".bmp", ".tif", ".tiff", ".heic", ".heif", ".webp",
+		".svg", ".ico", ".icns", ".raw", ".cr2", ".cr3", ".nef", ".arw", ".dng", ".orf",
+		".raf", ".psd", ".ai", ".xcf", ".avif", ".exr", ".hdr",
+	},
+	categoryAudio: {
+		".mp3", ".m4a", ".aac", ".wav", ".aif", ".aiff", ".flac", ".alac", ".ogg", ".oga",
+		".opus", ".wma", ".mid", ".midi", ".caf", ".m4b", ".m4p", ".logicx", ".band",
+	},
+	categoryArchives: {
+		".zip", ".tar", ".gz", ".tgz", ".bz2", ".tbz", ".xz", ".txz", ".7z", ".rar",
+		".zst", ".lz", ".lzma", ".lz4", ".cab", ".jar", ".war", ".xip", ".pkg", ".mpkg",
+		".deb", ".rpm", ".apk", ".ipa", ".whl", ".gem", ".cpio", ".sit", ".sitx",
+	},
+	categoryDiskImages: {
+		".dmg", ".iso", ".img", ".sparseimage", ".sparsebundle", ".vmdk", ".vdi", ".vhd",
+		".vhdx", ".qcow2", ".hdd", ".toast", ".cdr", ".raw-disk",
+	},
+	categoryCode: {
+		".go", ".c", ".h", ".cc", ".cpp", ".hpp", ".m", ".mm", ".swift", ".java", ".kt",
+		".kts", ".scala", ".rs", ".py", ".pyc", ".rb", ".php", ".js", ".mjs", ".cjs", ".ts",
+		".tsx", ".jsx", ".vue", ".svelte", ".css", ".scss", ".less", ".html", ".htm",
+		".sh", ".bash", ".zsh", ".fish", ".pl", ".lua", ".r", ".dart", ".cs", ".fs",
+		".sql", ".json", ".yaml", ".yml", ".toml", ".xml", ".plist", ".gradle", ".cmake",
+		".mk", ".proto", ".graphql", ".ipynb", ".o", ".a", ".so", ".dylib", ".class",
+		".wasm", ".map", ".lock", ".mod", ".sum",
+	},
+	categoryDocuments: {
+		".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".pages", ".numbers",
+		".key", ".odt", ".ods", ".odp", ".rtf", ".rtfd", ".txt", ".md", ".markdown", ".csv",
+		".tsv", ".epub", ".mobi", ".azw", ".azw3", ".tex", ".log", ".eml", ".emlx", ".vcf",
+		".ics", ".xps", ".djvu",
+	},
+})
+
+// buildCategoryTable inverts the per-category lists into an extension lookup
+func buildCategoryTable(lists map[int][]string) map[string]int {
+	table := make(map[string]int)
+	for category := 0; category < numFileCategories; category++ {
+		for _, ext := range lists[category] {
+			table[ext] = category
+		}
+	}
+	return table
+}
+
+// classifyFile returns the category of a file. Everything inside an app bundle
+// counts towards the bundle; files without an extension are sniffed.
+func classifyFile(path string, size int64) int {
+	if isInsideAppBundle(path) {
+		return categoryAppBundles
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".ts" && size >= sniffMinSize && isTransportStream(path) {
+		// ".ts" is TypeScript, except for recordings saved as MPEG transport streams
+		return categoryVideo
+	}
+	if ext != "" {
+		if category, ok := fileCategoryByExt[ext]; ok {
+			return category
+		}
+		return categoryOther
+	}
+
+	if size < sniffMinSize {
+		return categoryOther
+	}
+	return sniffFileCategory(path)
+}
+
+// readFileHeader reads up to size bytes from the start of a local file. Files
+// whose data is not on disk, such as iCloud placeholders, are not read, since
+// reading them would download them.
+func readFileHeader(path string, size int) []byte {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || isDataless(info) {
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	header := make([]byte, size)
+	n, _ := io.ReadFull(file, header)
+	return header[:n]
+}
+
+// isDataless reports whether a file has no data on disk: it has a size but
+// no allocated blocks, or is marked dataless by the file provider
+func isDataless(info fs.FileInfo) bool {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return false
+	}
+	return (info.Size() > 0 && stat.Blocks == 0) || hasDatalessFlag(stat)
+}
+
+// isTransportStream reports whether a file starts with MPEG transport stream packets
+func isTransportStream(path string) bool {
+	header := readFileHeader(path, 2*tsPacketSize+1)
+	return len(header) == 2*tsPacketSize+1 &&
+		header[0] == tsSyncByte && header[tsPacketSize] == tsSyncByte && header[2*tsPacketSize] == tsSyncByte
+}
+
+// sniffFileCategory classifies a file by its first bytes
+func sniffFileCategory(path string) int {
+	header := readFileHeader(path, sniffHeaderSize)
+	if len(header) == 0 {
+		return categoryOther
+	}
+
+	// Formats http.DetectContentType does not know
+	switch {
+	case bytes.HasPrefix(header, []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}),
+		bytes.HasPrefix(header, []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}),
+		bytes.HasPrefix(header, []byte("BZh")),
+		bytes.HasPrefix(header, []byte{0x28, 0xB5, 0x2F, 0xFD}), // zstd
+		bytes.HasPrefix(header, []byte("xar!")):
+		return categoryArchives
+	case bytes.HasPrefix(header, []byte("#!")),
+		bytes.HasPrefix(header, []byte{0xCF, 0xFA, 0xED, 0xFE}), // Mach-O 64-bit
+		bytes.HasPrefix(header, []byte{0xCA, 0xFE, 0xBA, 0xBE}), // Mach-O universal
+		bytes.HasPrefix(header, []byte{0x7F, 'E', 'L', 'F'}):
+		return categoryCode
+	case bytes.HasPrefix(header, []byte("encrcdsa")), bytes.HasPrefix(header, []byte("cdsaencr")):
+		return categoryDiskImages // Encrypted disk images
+	}
+
+	contentType := http.DetectContentType(header)
+	switch {
+	case strings.HasPrefix(contentType, "video/"):
+		return categoryVideo
+	case strings.HasPrefix(contentType, "image/"):
+		return categoryImages
+	case strings.HasPrefix(contentType, "audio/"), contentType == "application/ogg":
+		return categoryAudio
+	case contentType == "application/zip", contentType == "application/x-gzip",
+		contentType == "application/x-rar-compressed", contentType == "application/vnd.rar":
+		return categoryArchives
+	case contentType == "application/pdf", contentType == "application/postscript",
+		strings.HasPrefix(contentType, "text/plain"), strings.HasPrefix(contentType, "text/rtf"):
+		return categoryDocuments
+	case strings.HasPrefix(contentType, "text/"):
+		return categoryCode
+	}
+	return categoryOther
+}
+
+// isAppBundle reports whether a directory is an application bundle
+func isAppBundle(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".app")
+}
+
+// isInsideAppBundle reports whether path lies below an application bundle
+func isInsideAppBundle(path string) bool {
+	return strings.Contains(strings.ToLower(path), ".app/")
+}
+
+// typeStat is the breakdown of one category
+// NOTE: Fields must be exported (capitalized) for gob encoding/decoding in cache
+type typeStat struct {
+	Category string
+	Size     int64
+	Count    int64
+	TopFiles []fileEntry
+}
+
+type typeTotals struct {
+	size  int64
+	count int64
+	top   []fileEntry
+}
+
+// typeTally collects one directory's files without locking
+type typeTally [numFileCategories]typeTotals
+
+func (t *typeTally) add(path string, size int64) {
+	category := classifyFile(path, size)
+	totals := &t[category]
+	totals.size += size
+	if category == categoryAppBundles {
+		// Bundles are counted and listed whole, see countBundle and typeCollector.addBundle
+		return
+	}
+	totals.count++
+	totals.top = insertTopFile(totals.top, fileEntry{Name: filepath.Base(path), Path: path, Size: size})
+}
+
+// addVector adds the sizes and counts of a subtree that was not walked file by file
+func (t *typeTally) addVector(v typeVector) {
+	if len(v) != 2*numFileCategories {
+		return
+	}
+	for category := range t {
+		t[category].size += v[category]
+		t[category].count += v[numFileCategories+category]
+	}
+}
+
+// countBundle counts a top-level app bundle; its bytes are added file by file
+func (t *typeTally) countBundle() {
+	t[categoryAppBundles].count++
+}
+
+func (t *typeTally) vector() typeVector {
+	v := make(typeVector, 2*numFileCategories)
+	for category := range t {
+		v[category] = t[category].size
+		v[numFileCategories+category] = t[category].count
+	}
+	return v
+}
+
+// typeVector is a subtree's breakdown: sizes per category followed by counts.
+// Scan tree directories keep one so revalidation can reuse unchanged subtrees.
+type typeVector []int64
+
+// unwalkedTypes is the breakdown of a directory sized without a walk (du or
+// cache), whose contents are unknown and reported as unscanned
+func unwalkedTypes(size int64) typeVector {
+	v := make(typeVector, 2*numFileCategories)
+	v[categoryUnscanned] = size
+	return v
+}
+
+func (v typeVector) add(o typeVector) {
+	if len(o) != len(v) {
+		return
+	}
+	for i := range v {
+		v[i] += o[i]
+	}
+}
+
+func (v typeVector) sub(o typeVector) {
+	if len(o) != len(v) {
+		return
+	}
+	for i := range v {
+		v[i] -= o[i]
+	}
+}
+
+// typeCollector merges the tallies of a whole scan
+type typeCollector struct {
+	mu     sync.Mutex
+	totals typeTally
+}
+
+func (c *typeCollector) merge(t *typeTally) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for category := range t {
+		c.totals[category].size += t[category].size
+		c.totals[category].count += t[category].count
+		for _, file := range t[category].top {
+			c.totals[category].top = insertTopFile(c.totals[category].top, file)
+		}
+	}
+}
+
+// addBundle lists a finished app bundle; its bytes and count come with the tallies
+func (c *typeCollector) addBundle(path string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	totals := &c.totals[categoryAppBundles]
+	totals.top = insertTopFile(totals.top, fileEntry{Name: filepath.Base(path), Path: path, Size: size})
+}
+
+// addTopFiles offers files of a previous scan for the top lists of category,
+// skipping paths this scan already listed
+func (c *typeCollector) addTopFiles(category int, files []fileEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	totals := &c.totals[category]
+	for _, file := range files {
+		seen := false
+		for _, top := range totals.top {
+			if top.Path == file.Path {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			totals.top = insertTopFile(totals.top, file)
+		}
+	}
+}
+
+func (c *typeCollector) result() []typeStat {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := make([]typeStat, numFileCategories)
+	for category := range c.totals {
+		stats[category] = typeStat{
+			Category: fileCategoryIDs[category],
+			Size:     c.totals[category].size,
+			Count:    c.totals[category].count,
+			TopFiles: cloneFileEntries(c.totals[category].top),
+		}
+	}
+	return stats
+}
+
+// insertTopFile keeps the maxTypeTopFiles largest files, largest first
+func insertTopFile(top []fileEntry, file fileEntry) []fileEntry {
+	if len(top) == maxTypeTopFiles && file.Size <= top[len(top)-1].Size {
+		return top
+	}
+	i := len(top)
+	for i > 0 && top[i-1].Size < file.Size {
+		i--
+	}
+	if len(top) < maxTypeTopFiles {
+		top = append(top, fileEntry{})
+	}
+	copy(top[i+1:], top[i:len(top)-1])
+	top[i] = file
+	return top
+}
+
+func toModelFileTypes(stats []typeStat, totalSize int64) []models.FileTypeStat {
+	result := make([]models.FileTypeStat, 0, len(stats))
+	for i, stat := range stats {
+		label := stat.Category
+		if i < numFileCategories && fileCategoryIDs[i] == stat.Category {
+			label = fileCategoryLabels[i]
+		}
+		percent := 0.0
+		if totalSize > 0 {
+			percent = float64(stat.Size) / float64(totalSize) * 100
+		}
+		entry := models.FileTypeStat{
+			Category: stat.Category,
+			Label:    label,
+			Size:     stat.Size,
+			Count:    stat.Count,
+			Percent:  percent,
+			TopFiles: make([]models.FileEntry, len(stat.TopFiles)),
+		}
+		for j, file := range stat.TopFiles {
+			entry.TopFiles[j] = models.FileEntry{Name: file.Name, Path: file.Path, Size: file.Size}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
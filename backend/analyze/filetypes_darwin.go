@@ -0,0 +1,12 @@
+//go:build darwin
+
+package analyze
+
+import "syscall"
+
+// sfDataless is SF_DATALESS from sys/stat.h: the file's data lives with a file provider
+const sfDataless = 0x40000000
+
+func hasDatalessFlag(stat *syscall.Stat_t) bool {
+	return stat.Flags&sfDataless != 0
+}
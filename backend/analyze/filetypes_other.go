@@ -0,0 +1,10 @@
+//go:build !darwin
+
+package analyze
+
+import "syscall"
+
+// hasDatalessFlag is darwin only; elsewhere the block count alone decides
+func hasDatalessFlag(stat *syscall.Stat_t) bool {
+	return false
+}
@@ -0,0 +1,67 @@
+package analyze
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestClassifyTSFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	stream := make([]byte, sniffMinSize)
+	for i := 0; i < len(stream); i += tsPacketSize {
+		stream[i] = tsSyncByte
+	}
+	source := bytes.Repeat([]byte("export const x = 1;\n"), int(sniffMinSize)/20+1)
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"recording.ts", stream, categoryVideo},
+		{"large.ts", source, categoryCode},
+		{"small.ts", []byte("let a = 1\n"), categoryCode},
+	}
+
+	for _, tt := range tests {
+		path := writeTestFile(t, dir, tt.name, tt.data)
+		if got := classifyFile(path, int64(len(tt.data))); got != tt.want {
+			t.Fatalf("%s: got %s, want %s", tt.name, fileCategoryIDs[got], fileCategoryIDs[tt.want])
+		}
+	}
+}
+
+func TestReadFileHeaderSkipsSymlinks(t *testing.T) {
+	dir := t.TempDir()
+	target := writeTestFile(t, dir, "target", []byte("%PDF-1.7"))
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readFileHeader(target, 4); string(got) != "%PDF" {
+		t.Fatalf("got header %q, want %q", got, "%PDF")
+	}
+	if got := readFileHeader(link, 4); got != nil {
+		t.Fatalf("expected no header through a symlink, got %q", got)
+	}
+}
+
+func TestUnwalkedTypesAreUnscanned(t *testing.T) {
+	v := unwalkedTypes(42)
+	if v[categoryUnscanned] != 42 || v[categoryOther] != 0 {
+		t.Fatalf("expected unwalked bytes under unscanned, got %v", v)
+	}
+}
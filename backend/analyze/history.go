@@ -0,0 +1,463 @@
+package analyze
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"mole-wails/backend/models"
+)
+
+const (
+	sizeHistoryFile          = "analyze_size_history.json"
+	sizeHistoryVersion       = 1
+	sizeHistoryRetentionDays = 365
+	sizeHistoryDateFormat    = "2006-01-02"
+	defaultTrendWindowDays   = 30
+	sizeSampleDelay          = 2 * time.Minute // Let startup settle before measuring
+	sizeSampleInterval       = time.Hour       // How often the sampler looks for a new day
+	forecastHorizonDays      = 100 * 365       // No date is given for a volume filling up later than this
+)
+
+// sizeHistory keeps one sample per location and day
+type sizeHistory struct {
+	Version int                            `json:"version"`
+	Paths   map[string][]models.SizeSample `json:"paths"`
+	Volume  []models.VolumeSample          `json:"volume"`
+}
+
+var sizeHistoryMu sync.Mutex
+
+// GetSizeTrends returns the recorded history of each overview location with
+// its growth over the last days (default 30)
+func (s *Service) GetSizeTrends(days int) ([]models.SizeTrend, error) {
+	if days <= 0 {
+		days = defaultTrendWindowDays
+	}
+
+	sizeHistoryMu.Lock()
+	history, err := loadSizeHistory()
+	sizeHistoryMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	trends := []models.SizeTrend{}
+	for _, entry := range createOverviewEntries() {
+		samples := history.Paths[entry.Path]
+		trend := models.SizeTrend{
+			Name:    entry.Name,
+			Path:    entry.Path,
+			Samples: append([]models.SizeSample{}, samples...),
+		}
+		if len(samples) > 0 {
+			trend.CurrentSize = samples[len(samples)-1].Size
+			trend.Change7d = sizeChangeSince(samples, now.AddDate(0, 0, -7))
+			trend.Change30d = sizeChangeSince(samples, now.AddDate(0, 0, -30))
+
+			var points []trendPoint
+			for _, sample := range samplesSince(samples, now.AddDate(0, 0, -days)) {
+				points = append(points, trendPoint{date: sample.Date, value: float64(sample.Size)})
+			}
+			trend.BytesPerDay = trendSlope(points)
+		}
+		trends = append(trends, trend)
+	}
+	return trends, nil
+}
+
+// ForecastVolumeFull projects when the primary volume fills up from the
+// usage slope over the last days (default 30)
+func (s *Service) ForecastVolumeFull(days int) (*models.VolumeForecast, error) {
+	if days <= 0 {
+		days = defaultTrendWindowDays
+	}
+
+	usage, err := primaryVolumeUsage(s.baseContext())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sizeHistoryMu.Lock()
+	history, err := loadSizeHistory()
+	if err == nil {
+		history.Volume = upsertVolumeSample(history.Volume, models.VolumeSample{
+			Date:  now.Format(sizeHistoryDateFormat),
+			Used:  usage.Used,
+			Total: usage.Total,
+		}, now)
+		err = saveSizeHistory(history)
+	}
+	sizeHistoryMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := &models.VolumeForecast{
+		Mount:         usage.Path,
+		Total:         usage.Total,
+		Used:          usage.Used,
+		Free:          usage.Free,
+		DaysUntilFull: -1,
+		Samples:       history.Volume,
+	}
+
+	cutoff := now.AddDate(0, 0, -days).Format(sizeHistoryDateFormat)
+	var points []trendPoint
+	for _, sample := range history.Volume {
+		if sample.Date >= cutoff {
+			points = append(points, trendPoint{date: sample.Date, value: float64(sample.Used)})
+		}
+	}
+	forecast.BytesPerDay = trendSlope(points)
+
+	if forecast.BytesPerDay > 0 {
+		forecast.DaysUntilFull = float64(usage.Free) / forecast.BytesPerDay
+		forecast.FullAt = forecastFullAt(now, forecast.DaysUntilFull)
+	}
+	return forecast, nil
+}
+
+// forecastFullAt is the date days from now, or nil past forecastHorizonDays,
+// where it would overflow time.Duration and mean nothing anyway
+func forecastFullAt(now time.Time, days float64) *time.Time {
+	if days < 0 || days > forecastHorizonDays {
+		return nil
+	}
+	fullAt := now.Add(time.Duration(days * float64(24*time.Hour)))
+	return &fullAt
+}
+
+// sampleSizeHistory records each overview location once a day while ctx is alive
+func (s *Service) sampleSizeHistory(ctx context.Context) {
+	timer := time.NewTimer(sizeSampleDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		recordDailySizes(ctx)
+		timer.Reset(sizeSampleInterval)
+	}
+}
+
+// recordDailySizes measures the overview locations and the primary volume
+// that have no sample for today yet. It is the only writer of location
+// samples, so every sample is a fresh measurement of an overview location.
+func recordDailySizes(ctx context.Context) {
+	today := time.Now().Format(sizeHistoryDateFormat)
+
+	sizeHistoryMu.Lock()
+	history, err := loadSizeHistory()
+	sizeHistoryMu.Unlock()
+	if err != nil {
+		return
+	}
+
+	entries := createOverviewEntries()
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+		if samples := history.Paths[entry.Path]; len(samples) > 0 && samples[len(samples)-1].Date == today {
+			continue
+		}
+
+		// Measure directly: the overview cache may hold a size from days ago
+		size, err := measureDailySize(ctx, entry.Path)
+		if err != nil || size <= 0 {
+			continue
+		}
+		_ = storeOverviewSize(entry.Path, size)
+		_ = recordSizeSample(entries, entry.Path, size, time.Now())
+	}
+
+	if len(history.Volume) == 0 || history.Volume[len(history.Volume)-1].Date != today {
+		_ = recordVolumeSample(ctx)
+	}
+}
+
+// measureDailySize sizes an overview location. /Volumes is summed per volume
+// so network and FUSE mounts are never walked.
+func measureDailySize(ctx context.Context, path string) (int64, error) {
+	if path == "/Volumes" {
+		return localVolumesSize(ctx, path)
+	}
+
+	excludePath := overviewExcludePath(path)
+	size, err := getDirectorySizeFromDuWithExclude(ctx, path, excludePath)
+	if (err != nil || size <= 0) && ctx.Err() == nil {
+		size, err = getDirectoryLogicalSizeWithExclude(ctx, path, excludePath)
+	}
+	return size, err
+}
+
+// localVolumesSize sums the local volumes mounted below dir
+func localVolumesSize(ctx context.Context, dir string) (int64, error) {
+	children, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, child := range children {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		if strings.HasPrefix(child.Name(), ".") || !child.IsDir() {
+			continue // Symlinks such as "Macintosh HD" point back at the boot volume
+		}
+		path := filepath.Join(dir, child.Name())
+		fsType, responsive := filesystemTypeWithTimeout(path, defaultRemoteDirTimeout)
+		if !responsive || mountKindOf(fsType) != mountKindLocal {
+			continue
+		}
+		if size, err := getDirectorySizeFromDu(ctx, path); err == nil {
+			total += size
+		}
+	}
+	return total, nil
+}
+
+// recordSizeSample stores size as today's sample for path, one of entries.
+// Series of paths that are no longer overview locations are dropped; /Volumes
+// is kept while no external disk is attached.
+func recordSizeSample(entries []dirEntry, path string, size int64, at time.Time) error {
+	sizeHistoryMu.Lock()
+	defer sizeHistoryMu.Unlock()
+
+	history, err := loadSizeHistory()
+	if err != nil {
+		return err
+	}
+
+	tracked := map[string]bool{"/Volumes": true}
+	for _, entry := range entries {
+		tracked[entry.Path] = true
+	}
+	if !tracked[path] {
+		return fmt.Errorf("%s is not an overview location", path)
+	}
+	for p := range history.Paths {
+		if !tracked[p] {
+			delete(history.Paths, p)
+		}
+	}
+
+	history.Paths[path] = upsertSizeSample(history.Paths[path], models.SizeSample{
+		Date: at.Format(sizeHistoryDateFormat),
+		Size: size,
+	}, at)
+	return saveSizeHistory(history)
+}
+
+func recordVolumeSample(ctx context.Context) error {
+	usage, err := primaryVolumeUsage(ctx)
+	if err != nil {
+		return err
+	}
+
+	sizeHistoryMu.Lock()
+	defer sizeHistoryMu.Unlock()
+
+	history, err := loadSizeHistory()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	history.Volume = upsertVolumeSample(history.Volume, models.VolumeSample{
+		Date:  now.Format(sizeHistoryDateFormat),
+		Used:  usage.Used,
+		Total: usage.Total,
+	}, now)
+	return saveSizeHistory(history)
+}
+
+// primaryVolumeUsage reads the writable data volume, which is the one that fills up on APFS
+func primaryVolumeUsage(ctx context.Context) (*disk.UsageStat, error) {
+	for _, mount := range []string{"/System/Volumes/Data", "/"} {
+		if usage, err := disk.UsageWithContext(ctx, mount); err == nil {
+			return usage, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to read primary volume usage")
+}
+
+// Series helpers
+
+// upsertSizeSample keeps the latest sample per day, in date order, within retention
+func upsertSizeSample(samples []models.SizeSample, sample models.SizeSample, now time.Time) []models.SizeSample {
+	replaced := false
+	for i := range samples {
+		if samples[i].Date == sample.Date {
+			samples[i] = sample
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		samples = append(samples, sample)
+		sort.Slice(samples, func(i, j int) bool { return samples[i].Date < samples[j].Date })
+	}
+
+	cutoff := now.AddDate(0, 0, -sizeHistoryRetentionDays).Format(sizeHistoryDateFormat)
+	for len(samples) > 0 && samples[0].Date < cutoff {
+		samples = samples[1:]
+	}
+	return samples
+}
+
+func upsertVolumeSample(samples []models.VolumeSample, sample models.VolumeSample, now time.Time) []models.VolumeSample {
+	replaced := false
+	for i := range samples {
+		if samples[i].Date == sample.Date {
+			samples[i] = sample
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		samples = append(samples, sample)
+		sort.Slice(samples, func(i, j int) bool { return samples[i].Date < samples[j].Date })
+	}
+
+	cutoff := now.AddDate(0, 0, -sizeHistoryRetentionDays).Format(sizeHistoryDateFormat)
+	for len(samples) > 0 && samples[0].Date < cutoff {
+		samples = samples[1:]
+	}
+	return samples
+}
+
+func samplesSince(samples []models.SizeSample, since time.Time) []models.SizeSample {
+	cutoff := since.Format(sizeHistoryDateFormat)
+	for i, sample := range samples {
+		if sample.Date >= cutoff {
+			return samples[i:]
+		}
+	}
+	return nil
+}
+
+// sizeChangeSince compares the latest sample with the last one taken on or
+// before since, or the oldest one if history is shorter than that
+func sizeChangeSince(samples []models.SizeSample, since time.Time) int64 {
+	if len(samples) < 2 {
+		return 0
+	}
+	cutoff := since.Format(sizeHistoryDateFormat)
+	base := samples[0]
+	for _, sample := range samples[:len(samples)-1] {
+		if sample.Date > cutoff {
+			break
+		}
+		base = sample
+	}
+	return samples[len(samples)-1].Size - base.Size
+}
+
+type trendPoint struct {
+	date  string
+	value float64
+}
+
+// trendSlope is the least-squares slope in units per day. It needs samples
+// from at least two different days.
+func trendSlope(points []trendPoint) float64 {
+	if len(points) < 2 {
+		return 0
+	}
+
+	first, err := time.ParseInLocation(sizeHistoryDateFormat, points[0].date, time.Local)
+	if err != nil {
+		return 0
+	}
+
+	var n, sumX, sumY, sumXY, sumXX float64
+	for _, p := range points {
+		day, err := time.ParseInLocation(sizeHistoryDateFormat, p.date, time.Local)
+		if err != nil {
+			continue
+		}
+		x := day.Sub(first).Hours() / 24
+		n++
+		sumX += x
+		sumY += p.value
+		sumXY += x * p.value
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if n < 2 || denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+// Storage
+
+func getSizeHistoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	configDir := filepath.Join(home, ".config", "mole")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, sizeHistoryFile), nil
+}
+
+func loadSizeHistory() (*sizeHistory, error) {
+	history := &sizeHistory{Version: sizeHistoryVersion, Paths: make(map[string][]models.SizeSample)}
+
+	historyPath, err := getSizeHistoryPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate size history: %w", err)
+	}
+
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return history, nil
+		}
+		return nil, fmt.Errorf("failed to read size history: %w", err)
+	}
+
+	if err := json.Unmarshal(data, history); err != nil || history.Version != sizeHistoryVersion {
+		// Keep the unreadable file for inspection and start a new series
+		_ = os.Rename(historyPath, historyPath+".corrupt")
+		return &sizeHistory{Version: sizeHistoryVersion, Paths: make(map[string][]models.SizeSample)}, nil
+	}
+	if history.Paths == nil {
+		history.Paths = make(map[string][]models.SizeSample)
+	}
+	return history, nil
+}
+
+func saveSizeHistory(history *sizeHistory) error {
+	historyPath, err := getSizeHistoryPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(historyPath, func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
@@ -0,0 +1,105 @@
+package analyze
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+func TestTrendSlope(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []trendPoint
+		want   float64
+	}{
+		{"no points", nil, 0},
+		{"single point", []trendPoint{{"2026-01-01", 100}}, 0},
+		{"flat", []trendPoint{{"2026-01-01", 100}, {"2026-01-02", 100}, {"2026-01-03", 100}}, 0},
+		{"steady growth", []trendPoint{{"2026-01-01", 100}, {"2026-01-02", 110}, {"2026-01-03", 120}}, 10},
+		{"uneven spacing", []trendPoint{{"2026-01-01", 0}, {"2026-01-11", 50}}, 5},
+		{"shrinking", []trendPoint{{"2026-03-01", 300}, {"2026-03-02", 200}, {"2026-03-03", 100}}, -100},
+		{"least squares", []trendPoint{{"2026-01-01", 0}, {"2026-01-02", 20}, {"2026-01-03", 10}}, 5},
+		{"same day", []trendPoint{{"2026-01-01", 0}, {"2026-01-01", 50}}, 0},
+		{"bad date skipped", []trendPoint{{"2026-01-01", 0}, {"garbage", 1e9}, {"2026-01-03", 20}}, 10},
+	}
+
+	for _, tt := range tests {
+		if got := trendSlope(tt.points); math.Abs(got-tt.want) > 1e-9 {
+			t.Fatalf("%s: got %f, want %f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpsertSizeSample(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.Local)
+	samples := []models.SizeSample{
+		{Date: "2025-01-01", Size: 1}, // Past retention
+		{Date: "2026-06-08", Size: 8},
+		{Date: "2026-06-10", Size: 10},
+	}
+
+	samples = upsertSizeSample(samples, models.SizeSample{Date: "2026-06-10", Size: 11}, now)
+	samples = upsertSizeSample(samples, models.SizeSample{Date: "2026-06-09", Size: 9}, now)
+
+	want := []models.SizeSample{{Date: "2026-06-08", Size: 8}, {Date: "2026-06-09", Size: 9}, {Date: "2026-06-10", Size: 11}}
+	if len(samples) != len(want) {
+		t.Fatalf("got %+v, want %+v", samples, want)
+	}
+	for i := range want {
+		if samples[i] != want[i] {
+			t.Fatalf("got %+v, want %+v", samples, want)
+		}
+	}
+}
+
+func TestSizeChangeSince(t *testing.T) {
+	samples := []models.SizeSample{
+		{Date: "2026-06-01", Size: 100},
+		{Date: "2026-06-05", Size: 150},
+		{Date: "2026-06-10", Size: 180},
+	}
+	since := func(date string) time.Time {
+		day, _ := time.ParseInLocation(sizeHistoryDateFormat, date, time.Local)
+		return day
+	}
+
+	if got := sizeChangeSince(samples, since("2026-06-06")); got != 30 {
+		t.Fatalf("change since the 6th: got %d, want 30", got)
+	}
+	if got := sizeChangeSince(samples, since("2026-05-01")); got != 80 {
+		t.Fatalf("change over a longer window than the history: got %d, want 80", got)
+	}
+	if got := sizeChangeSince(samples[:1], since("2026-05-01")); got != 0 {
+		t.Fatalf("single sample: got %d, want 0", got)
+	}
+}
+
+func TestForecastFullAt(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		days float64
+		want *time.Time
+	}{
+		{"tomorrow", 1, ptrTime(now.AddDate(0, 0, 1))},
+		{"at the horizon", forecastHorizonDays, ptrTime(now.Add(forecastHorizonDays * 24 * time.Hour))},
+		{"past the horizon", forecastHorizonDays + 1, nil},
+		// 200 GB free growing by 1 KB a day overflowed time.Duration
+		{"overflow", 200e9 / 1e3, nil},
+		{"negative", -1, nil},
+	}
+
+	for _, tt := range tests {
+		got := forecastFullAt(now, tt.days)
+		if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
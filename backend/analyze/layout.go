@@ -0,0 +1,237 @@
+package analyze
+
+import (
+	"math"
+	"path/filepath"
+
+	"mole-wails/backend/models"
+)
+
+// Layouts are pure functions of the scan tree: the same tree and viewport
+// always produce the same shapes, in parent-before-children order.
+
+type layoutRect struct {
+	x, y, w, h float64
+}
+
+// layoutTreemap lays out root's subtree as squarified rectangles filling
+// width x height, down to maxDepth levels. Shapes narrower or shorter than
+// minPixels are dropped together with their descendants.
+func layoutTreemap(root *treeNode, rootPath string, width, height float64, maxDepth int, minPixels float64) []models.TreemapRect {
+	if root == nil || width <= 0 || height <= 0 {
+		return []models.TreemapRect{}
+	}
+	if minPixels < 1 {
+		minPixels = 1
+	}
+
+	var rects []models.TreemapRect
+	var visit func(node *treeNode, path string, depth int, r layoutRect)
+	visit = func(node *treeNode, path string, depth int, r layoutRect) {
+		rects = append(rects, models.TreemapRect{
+			Name:      node.Name,
+			Path:      layoutPath(node, path),
+			Size:      node.Size,
+			Depth:     depth,
+			X:         r.x,
+			Y:         r.y,
+			Width:     r.w,
+			Height:    r.h,
+			IsDir:     node.IsDir,
+			IsOther:   node.Other,
+			Collapsed: node.Collapsed,
+		})
+		if depth >= maxDepth {
+			return
+		}
+
+		children := layoutChildren(node)
+		if len(children) == 0 {
+			return
+		}
+		for i, cr := range squarify(children, layoutTotal(node, children), r) {
+			if cr.w < minPixels || cr.h < minPixels {
+				continue
+			}
+			child := children[i]
+			visit(child, filepath.Join(path, child.Name), depth+1, cr)
+		}
+	}
+
+	visit(root, rootPath, 0, layoutRect{w: width, h: height})
+	return rects
+}
+
+// squarify places nodes (largest first) in r using the squarified treemap
+// algorithm of Bruls, Huizing and van Wijk, keeping aspect ratios near 1.
+// Areas are proportional to size/total, so a total above the children's sum
+// leaves the remainder of r empty.
+func squarify(nodes []*treeNode, total int64, r layoutRect) []layoutRect {
+	out := make([]layoutRect, len(nodes))
+	if total <= 0 {
+		return out
+	}
+
+	scale := r.w * r.h / float64(total)
+	areas := make([]float64, len(nodes))
+	for i, node := range nodes {
+		areas[i] = float64(node.Size) * scale
+	}
+
+	start := 0
+	for start < len(nodes) {
+		side := math.Min(r.w, r.h)
+		if side <= 0 {
+			break
+		}
+
+		// Grow the row while it improves the worst aspect ratio
+		end := start + 1
+		for end < len(nodes) && worstRatio(areas[start:end+1], side) <= worstRatio(areas[start:end], side) {
+			end++
+		}
+		r = layoutRow(areas[start:end], r, out[start:end])
+		start = end
+	}
+	return out
+}
+
+// worstRatio is the largest aspect ratio in a row of areas laid along side
+func worstRatio(row []float64, side float64) float64 {
+	var sum, minArea, maxArea float64
+	minArea = math.Inf(1)
+	for _, a := range row {
+		sum += a
+		minArea = math.Min(minArea, a)
+		maxArea = math.Max(maxArea, a)
+	}
+	if sum <= 0 || minArea <= 0 {
+		return math.Inf(1)
+	}
+	s2, w2 := sum*sum, side*side
+	return math.Max(w2*maxArea/s2, s2/(w2*minArea))
+}
+
+// layoutRow places a row along the shorter side of r and returns the space left
+func layoutRow(row []float64, r layoutRect, out []layoutRect) layoutRect {
+	var sum float64
+	for _, a := range row {
+		sum += a
+	}
+	if sum <= 0 {
+		return r
+	}
+
+	if r.w >= r.h {
+		// Column on the left edge
+		colWidth := sum / r.h
+		y := r.y
+		for i, a := range row {
+			h := a / colWidth
+			out[i] = layoutRect{x: r.x, y: y, w: colWidth, h: h}
+			y += h
+		}
+		return layoutRect{x: r.x + colWidth, y: r.y, w: math.Max(r.w-colWidth, 0), h: r.h}
+	}
+
+	// Row along the top edge
+	rowHeight := sum / r.w
+	x := r.x
+	for i, a := range row {
+		w := a / rowHeight
+		out[i] = layoutRect{x: x, y: r.y, w: w, h: rowHeight}
+		x += w
+	}
+	return layoutRect{x: r.x, y: r.y + rowHeight, w: r.w, h: math.Max(r.h-rowHeight, 0)}
+}
+
+// layoutSunburst lays out root's subtree as rings around the centre. The root
+// is the inner disc and each level below it gets an equal-width ring out to
+// radius. Segments whose outer arc is shorter than minPixels are dropped
+// together with their descendants.
+func layoutSunburst(root *treeNode, rootPath string, radius float64, maxDepth int, minPixels float64) []models.SunburstArc {
+	if root == nil || radius <= 0 {
+		return []models.SunburstArc{}
+	}
+	if maxDepth < 0 {
+		maxDepth = 0
+	}
+	if minPixels < 1 {
+		minPixels = 1
+	}
+	ring := radius / float64(maxDepth+1)
+
+	var arcs []models.SunburstArc
+	var visit func(node *treeNode, path string, depth int, start, end float64)
+	visit = func(node *treeNode, path string, depth int, start, end float64) {
+		inner, outer := float64(depth)*ring, float64(depth+1)*ring
+		arcs = append(arcs, models.SunburstArc{
+			Name:        node.Name,
+			Path:        layoutPath(node, path),
+			Size:        node.Size,
+			Depth:       depth,
+			StartAngle:  start,
+			EndAngle:    end,
+			InnerRadius: inner,
+			OuterRadius: outer,
+			IsDir:       node.IsDir,
+			IsOther:     node.Other,
+			Collapsed:   node.Collapsed,
+		})
+		if depth >= maxDepth {
+			return
+		}
+
+		children := layoutChildren(node)
+		total := layoutTotal(node, children)
+		if total <= 0 {
+			return
+		}
+
+		span := end - start
+		angle := start
+		for _, child := range children {
+			sweep := span * float64(child.Size) / float64(total)
+			childStart := angle
+			angle += sweep
+			if sweep*(outer+ring) < minPixels {
+				continue
+			}
+			visit(child, filepath.Join(path, child.Name), depth+1, childStart, angle)
+		}
+	}
+
+	visit(root, rootPath, 0, 0, 2*math.Pi)
+	return arcs
+}
+
+// layoutChildren returns the children with a positive size, largest first
+func layoutChildren(node *treeNode) []*treeNode {
+	children := make([]*treeNode, 0, len(node.Children))
+	for i := range node.Children {
+		if node.Children[i].Size > 0 {
+			children = append(children, &node.Children[i])
+		}
+	}
+	return children
+}
+
+// layoutTotal is the size children are measured against. It is the node's own
+// size unless the children add up to more (sizes from du can disagree slightly).
+func layoutTotal(node *treeNode, children []*treeNode) int64 {
+	var sum int64
+	for _, child := range children {
+		sum += child.Size
+	}
+	if node.Size > sum {
+		return node.Size
+	}
+	return sum
+}
+
+func layoutPath(node *treeNode, path string) string {
+	if node.Other {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,203 @@
+package analyze
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+const layoutEpsilon = 1e-6
+
+func layoutNodes(sizes ...int64) []*treeNode {
+	nodes := make([]*treeNode, len(sizes))
+	for i, size := range sizes {
+		nodes[i] = &treeNode{Name: string(rune('a' + i)), Size: size}
+	}
+	return nodes
+}
+
+func sampleLayoutTree() *treeNode {
+	return &treeNode{
+		Name:  "root",
+		Size:  1000,
+		IsDir: true,
+		Children: []treeNode{
+			{Name: "big", Size: 600, IsDir: true, Children: []treeNode{
+				{Name: "x", Size: 400},
+				{Name: "y", Size: 200},
+			}},
+			{Name: "mid", Size: 300},
+			{Name: "small", Size: 99},
+			{Name: "tiny", Size: 1, IsDir: true, Children: []treeNode{
+				{Name: "z", Size: 1},
+			}},
+		},
+	}
+}
+
+func TestSquarifyConservesArea(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []int64
+		total int64
+		rect  layoutRect
+	}{
+		{"single", []int64{10}, 10, layoutRect{w: 100, h: 50}},
+		{"equal", []int64{5, 5, 5, 5}, 20, layoutRect{w: 80, h: 80}},
+		{"skewed", []int64{600, 300, 60, 30, 9, 1}, 1000, layoutRect{x: 10, y: 20, w: 300, h: 200}},
+		{"tall", []int64{6, 6, 4, 3, 2, 2, 1}, 24, layoutRect{w: 60, h: 400}},
+		{"total above sum", []int64{30, 20}, 100, layoutRect{w: 100, h: 100}},
+	}
+
+	for _, tt := range tests {
+		rects := squarify(layoutNodes(tt.sizes...), tt.total, tt.rect)
+
+		var area float64
+		for i, r := range rects {
+			want := tt.rect.w * tt.rect.h * float64(tt.sizes[i]) / float64(tt.total)
+			if math.Abs(r.w*r.h-want) > layoutEpsilon*want+layoutEpsilon {
+				t.Fatalf("%s: rect %d has area %f, want %f", tt.name, i, r.w*r.h, want)
+			}
+			if r.x < tt.rect.x-layoutEpsilon || r.y < tt.rect.y-layoutEpsilon ||
+				r.x+r.w > tt.rect.x+tt.rect.w+layoutEpsilon || r.y+r.h > tt.rect.y+tt.rect.h+layoutEpsilon {
+				t.Fatalf("%s: rect %d %+v lies outside %+v", tt.name, i, r, tt.rect)
+			}
+			area += r.w * r.h
+		}
+
+		var sum int64
+		for _, size := range tt.sizes {
+			sum += size
+		}
+		want := tt.rect.w * tt.rect.h * float64(sum) / float64(tt.total)
+		if math.Abs(area-want) > layoutEpsilon*want {
+			t.Fatalf("%s: total area %f, want %f", tt.name, area, want)
+		}
+	}
+}
+
+func TestSquarifyRectsDoNotOverlap(t *testing.T) {
+	rects := squarify(layoutNodes(500, 250, 125, 60, 40, 20, 5), 1000, layoutRect{w: 320, h: 240})
+	for i := range rects {
+		for j := i + 1; j < len(rects); j++ {
+			a, b := rects[i], rects[j]
+			w := math.Min(a.x+a.w, b.x+b.w) - math.Max(a.x, b.x)
+			h := math.Min(a.y+a.h, b.y+b.h) - math.Max(a.y, b.y)
+			if w > layoutEpsilon && h > layoutEpsilon {
+				t.Fatalf("rects %d %+v and %d %+v overlap", i, a, j, b)
+			}
+		}
+	}
+}
+
+func TestSquarifyZeroTotal(t *testing.T) {
+	rects := squarify(layoutNodes(0, 0), 0, layoutRect{w: 10, h: 10})
+	for i, r := range rects {
+		if r != (layoutRect{}) {
+			t.Fatalf("rect %d: expected empty rect, got %+v", i, r)
+		}
+	}
+}
+
+func TestWorstRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		row  []float64
+		side float64
+		want float64
+	}{
+		{"square", []float64{4}, 2, 1},
+		{"two halves", []float64{2, 2}, 2, 2},
+		{"thin strip", []float64{1}, 4, 16},
+		{"uneven", []float64{9, 1}, 5, 4},
+		{"zero area", []float64{4, 0}, 2, math.Inf(1)},
+		{"empty", nil, 2, math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		got := worstRatio(tt.row, tt.side)
+		if math.IsInf(tt.want, 1) {
+			if !math.IsInf(got, 1) {
+				t.Fatalf("%s: got %f, want +Inf", tt.name, got)
+			}
+			continue
+		}
+		if math.Abs(got-tt.want) > layoutEpsilon {
+			t.Fatalf("%s: got %f, want %f", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLayoutTreemapIsDeterministic(t *testing.T) {
+	first := layoutTreemap(sampleLayoutTree(), "/root", 800, 600, 3, 1)
+	second := layoutTreemap(sampleLayoutTree(), "/root", 800, 600, 3, 1)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("layouts differ:\n%+v\n%+v", first, second)
+	}
+	if len(first) == 0 || first[0].Path != "/root" || first[0].Width != 800 || first[0].Height != 600 {
+		t.Fatalf("unexpected root rect: %+v", first)
+	}
+}
+
+func TestLayoutTreemapDropsSmallRects(t *testing.T) {
+	rects := layoutTreemap(sampleLayoutTree(), "/root", 100, 100, 3, 5)
+	for _, r := range rects {
+		if r.Name == "tiny" || r.Name == "z" {
+			t.Fatalf("expected %s to be dropped, got %+v", r.Name, r)
+		}
+		if r.Depth > 0 && (r.Width < 5 || r.Height < 5) {
+			t.Fatalf("rect below minPixels kept: %+v", r)
+		}
+	}
+}
+
+func TestLayoutSunburstConservesAngles(t *testing.T) {
+	arcs := layoutSunburst(sampleLayoutTree(), "/root", 400, 2, 1)
+
+	var sweep float64
+	for _, arc := range arcs {
+		if arc.Depth == 1 {
+			sweep += arc.EndAngle - arc.StartAngle
+		}
+	}
+	if math.Abs(sweep-2*math.Pi) > layoutEpsilon {
+		t.Fatalf("depth 1 arcs sweep %f, want 2π", sweep)
+	}
+
+	again := layoutSunburst(sampleLayoutTree(), "/root", 400, 2, 1)
+	if !reflect.DeepEqual(arcs, again) {
+		t.Fatalf("layouts differ:\n%+v\n%+v", arcs, again)
+	}
+}
+
+func TestLayoutSunburstDropsNarrowArcs(t *testing.T) {
+	// "tiny" sweeps 2π/1000 radians; its outer arc at radius 200 is about 1.26px
+	arcs := layoutSunburst(sampleLayoutTree(), "/root", 200, 1, 2)
+
+	names := make(map[string]bool)
+	for _, arc := range arcs {
+		names[arc.Name] = true
+	}
+	if names["tiny"] || names["z"] {
+		t.Fatalf("expected tiny and its children to be dropped: %+v", arcs)
+	}
+	for _, name := range []string{"root", "big", "mid", "small"} {
+		if !names[name] {
+			t.Fatalf("expected %s to be kept: %+v", name, arcs)
+		}
+	}
+
+	// Kept siblings stay contiguous, largest first
+	var small, mid float64
+	for _, arc := range arcs {
+		switch arc.Name {
+		case "mid":
+			mid = arc.EndAngle
+		case "small":
+			small = arc.StartAngle
+		}
+	}
+	if math.Abs(small-mid) > layoutEpsilon {
+		t.Fatalf("small starts at %f, want %f", small, mid)
+	}
+}
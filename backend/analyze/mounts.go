@@ -0,0 +1,340 @@
+package analyze
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// Mount points
+//
+// A directory whose device differs from its parent's is a mount point. Each
+// one a scan meets is recorded and labelled. Local mounts are descended unless
+// the scan stays on one filesystem; network and FUSE mounts can be skipped, and
+// when they are scanned every directory listing and stat on them has a
+// timeout, so a dead share cannot hang the scan. du and Spotlight have no such
+// timeout, so folded directories on them are walked like any other and
+// Spotlight is not asked.
+//
+// On macOS the sealed system volume and its data volume have different devices
+// but are one filesystem to the user, joined by firmlinks (/Users,
+// /Applications and others live on the data volume), so crossing between them
+// is not crossing a mount.
+
+const (
+	mountKindLocal          = "local"
+	mountKindNetwork        = "network"
+	mountKindFUSE           = "fuse"
+	defaultRemoteDirTimeout = 5 * time.Second
+)
+
+var networkFSTypes = map[string]bool{
+	"smbfs": true, "cifs": true, "smb2": true, "smb3": true, "nfs": true, "nfs4": true,
+	"afpfs": true, "webdav": true, "davfs": true, "ftp": true, "9p": true, "ceph": true,
+	"afs": true, "coda": true, "sshfs": true,
+}
+
+// mountEntry is a mount point met during a scan
+// NOTE: Fields must be exported (capitalized) for gob encoding/decoding in cache
+type mountEntry struct {
+	Path     string
+	FSType   string
+	Kind     string
+	Size     int64 // Bytes counted in the scan; 0 when skipped
+	Skipped  bool
+	TimedOut int64 // Directory listings given up on
+}
+
+// mountCollector records the mount points of one scan
+type mountCollector struct {
+	mu     sync.Mutex
+	byPath map[string]*mountEntry
+	remote map[uint64]string // Device of a network or FUSE mount to its path
+}
+
+func newMountCollector() *mountCollector {
+	return &mountCollector{
+		byPath: make(map[string]*mountEntry),
+		remote: make(map[uint64]string),
+	}
+}
+
+// crossMount records the mount point at path; Skipped tells whether the scan
+// leaves it out
+func (o *scanOptions) crossMount(path string, dev uint64) *mountEntry {
+	fsType, responsive := filesystemTypeWithTimeout(path, o.remoteDirTimeout)
+	kind := mountKindOf(fsType)
+	if !responsive {
+		// statfs hanging is the hallmark of a dead network share
+		kind = mountKindNetwork
+	}
+
+	mount := &mountEntry{
+		Path:    path,
+		FSType:  fsType,
+		Kind:    kind,
+		Skipped: o.stayOnFilesystem || !responsive || (kind != mountKindLocal && o.skipRemoteMounts),
+	}
+	if !responsive {
+		mount.TimedOut = 1
+	}
+
+	o.mounts.mu.Lock()
+	defer o.mounts.mu.Unlock()
+	o.mounts.byPath[path] = mount
+	if kind != mountKindLocal {
+		o.mounts.remote[dev] = path
+	}
+	return mount
+}
+
+// setMountSize records the bytes a descended mount point added to the scan
+func (c *mountCollector) setMountSize(path string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if mount, ok := c.byPath[path]; ok && !mount.Skipped {
+		mount.Size = size
+	}
+}
+
+// recordMountSizes records the size of each descended mount point among kids
+func recordMountSizes(opts *scanOptions, root string, kids []treeNode) {
+	for i := range kids {
+		if kids[i].Mount != "" {
+			opts.mounts.setMountSize(filepath.Join(root, kids[i].Name), kids[i].Size)
+		}
+	}
+}
+
+// remoteMount reports whether path lies on a network or FUSE mount met so far,
+// returning the mount's device
+func (c *mountCollector) remoteMount(path string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for dev, mount := range c.remote {
+		if path == mount || strings.HasPrefix(path, strings.TrimSuffix(mount, string(os.PathSeparator))+string(os.PathSeparator)) {
+			return dev, true
+		}
+	}
+	return 0, false
+}
+
+func (c *mountCollector) onRemote(path string) bool {
+	_, ok := c.remoteMount(path)
+	return ok
+}
+
+func (c *mountCollector) isRemote(dev uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.remote[dev]
+	return ok
+}
+
+func (c *mountCollector) timedOut(dev uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if mount, ok := c.byPath[c.remote[dev]]; ok {
+		mount.TimedOut++
+	}
+}
+
+// result returns the recorded mount points by path
+func (c *mountCollector) result() []mountEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	mounts := make([]mountEntry, 0, len(c.byPath))
+	for _, mount := range c.byPath {
+		mounts = append(mounts, *mount)
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Path < mounts[j].Path })
+	return mounts
+}
+
+// readDir lists a directory, giving up after the remote timeout when it lies
+// on a network or FUSE mount. A listing that never returns leaks its goroutine.
+func (o *scanOptions) readDir(path string, info fs.FileInfo) ([]fs.DirEntry, error) {
+	dev, ok := deviceID(info)
+	if !ok || !o.mounts.isRemote(dev) {
+		return os.ReadDir(path)
+	}
+
+	type listing struct {
+		entries []fs.DirEntry
+		err     error
+	}
+	done := make(chan listing, 1)
+	go func() {
+		entries, err := os.ReadDir(path)
+		done <- listing{entries, err}
+	}()
+
+	timer := time.NewTimer(o.remoteDirTimeout)
+	defer timer.Stop()
+	select {
+	case l := <-done:
+		return l.entries, l.err
+	case <-timer.C:
+		o.mounts.timedOut(dev)
+		return nil, fmt.Errorf("listing %s timed out", path)
+	}
+}
+
+// withRemoteTimeout runs fn, giving up after the remote timeout when path lies
+// on a network or FUSE mount. A call that never returns leaks its goroutine.
+func withRemoteTimeout[T any](o *scanOptions, path string, fn func() (T, error)) (T, error) {
+	dev, remote := o.mounts.remoteMount(path)
+	if !remote {
+		return fn()
+	}
+
+	type outcome struct {
+		value T
+		err   error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		value, err := fn()
+		done <- outcome{value, err}
+	}()
+
+	timer := time.NewTimer(o.remoteDirTimeout)
+	defer timer.Stop()
+	select {
+	case out := <-done:
+		return out.value, out.err
+	case <-timer.C:
+		o.mounts.timedOut(dev)
+		var zero T
+		return zero, fmt.Errorf("stat %s timed out", path)
+	}
+}
+
+// stat is os.Stat under the remote timeout
+func (o *scanOptions) stat(path string) (fs.FileInfo, error) {
+	return withRemoteTimeout(o, path, func() (fs.FileInfo, error) { return os.Stat(path) })
+}
+
+// entryInfo is entry.Info under the remote timeout; path is the entry's path
+func (o *scanOptions) entryInfo(entry fs.DirEntry, path string) (fs.FileInfo, error) {
+	return withRemoteTimeout(o, path, entry.Info)
+}
+
+// foldsWithDu reports whether a directory is folded and sized with du, which
+// only happens off network and FUSE mounts
+func (o *scanOptions) foldsWithDu(name, path string) bool {
+	return o.isFolded(name, path) && !o.mounts.onRemote(path)
+}
+
+// sameFilesystem reports whether two devices are one filesystem to the user
+func sameFilesystem(dev, otherDev uint64) bool {
+	if dev == otherDev {
+		return true
+	}
+	system, data, ok := firmlinkedVolumes()
+	return ok && (dev == system && otherDev == data || dev == data && otherDev == system)
+}
+
+// filesystemTypeWithTimeout is filesystemType, reporting false when statfs
+// does not return in time
+func filesystemTypeWithTimeout(path string, timeout time.Duration) (string, bool) {
+	done := make(chan string, 1)
+	go func() { done <- filesystemType(path) }()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case fsType := <-done:
+		return fsType, true
+	case <-timer.C:
+		return "", false
+	}
+}
+
+func mountKindOf(fsType string) string {
+	fsType = strings.ToLower(fsType)
+	switch {
+	case networkFSTypes[fsType]:
+		return mountKindNetwork
+	case strings.Contains(fsType, "fuse"):
+		return mountKindFUSE
+	default:
+		return mountKindLocal
+	}
+}
+
+// mountLabel names a mount point for display, e.g. "Network share (smbfs)"
+func mountLabel(mount *mountEntry) string {
+	fsType := mount.FSType
+	if fsType == "" {
+		fsType = "unknown"
+	}
+	switch mount.Kind {
+	case mountKindNetwork:
+		return fmt.Sprintf("Network share (%s)", fsType)
+	case mountKindFUSE:
+		return fmt.Sprintf("FUSE mount (%s)", fsType)
+	default:
+		return fmt.Sprintf("Mounted volume (%s)", fsType)
+	}
+}
+
+// mountPlaceholder stands in the tree for a mount point that was not descended
+func mountPlaceholder(name string, mount *mountEntry) treeNode {
+	return treeNode{Name: name, IsDir: true, Collapsed: true, Mount: mountLabel(mount), Types: unwalkedTypes(0)}
+}
+
+// carryOverMounts keeps the mount points of an earlier scan that this one did
+// not meet because they lie in a reused collapsed subtree, if they are still mounted
+func carryOverMounts(mounts, prev []mountEntry) []mountEntry {
+	seen := make(map[string]bool, len(mounts))
+	for _, mount := range mounts {
+		seen[mount.Path] = true
+	}
+	for _, mount := range prev {
+		if seen[mount.Path] || !isMountPoint(mount.Path) {
+			continue
+		}
+		mounts = append(mounts, mount)
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].Path < mounts[j].Path })
+	return mounts
+}
+
+func isMountPoint(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	parent, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		return false
+	}
+	dev, ok := deviceID(info)
+	parentDev, parentOK := deviceID(parent)
+	return ok && parentOK && !sameFilesystem(dev, parentDev)
+}
+
+func toModelMounts(mounts []mountEntry) []models.MountEntry {
+	result := make([]models.MountEntry, len(mounts))
+	for i := range mounts {
+		mount := &mounts[i]
+		result[i] = models.MountEntry{
+			Path:     mount.Path,
+			Label:    mountLabel(mount),
+			FSType:   mount.FSType,
+			Kind:     mount.Kind,
+			Size:     mount.Size,
+			Skipped:  mount.Skipped,
+			TimedOut: mount.TimedOut,
+		}
+	}
+	return result
+}
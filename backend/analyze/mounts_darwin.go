@@ -0,0 +1,53 @@
+//go:build darwin
+
+package analyze
+
+import (
+	"os"
+	"sync"
+	"syscall"
+)
+
+// dataVolumePath is where the data volume of the system volume group is mounted
+const dataVolumePath = "/System/Volumes/Data"
+
+var firmlinks struct {
+	once         sync.Once
+	system, data uint64
+	ok           bool
+}
+
+// firmlinkedVolumes returns the devices of the sealed system volume and its
+// data volume, which firmlinks join into one tree since macOS 10.15
+func firmlinkedVolumes() (system, data uint64, ok bool) {
+	firmlinks.once.Do(func() {
+		systemInfo, err := os.Stat("/")
+		if err != nil {
+			return
+		}
+		dataInfo, err := os.Stat(dataVolumePath)
+		if err != nil {
+			return
+		}
+		firmlinks.system, _ = deviceID(systemInfo)
+		firmlinks.data, _ = deviceID(dataInfo)
+		firmlinks.ok = firmlinks.system != firmlinks.data
+	})
+	return firmlinks.system, firmlinks.data, firmlinks.ok
+}
+
+// filesystemType returns the filesystem name of path, e.g. "apfs" or "smbfs"
+func filesystemType(path string) string {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return ""
+	}
+	name := make([]byte, 0, len(stat.Fstypename))
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+		name = append(name, byte(c))
+	}
+	return string(name)
+}
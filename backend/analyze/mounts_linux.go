@@ -0,0 +1,50 @@
+//go:build linux
+
+package analyze
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Filesystem magic numbers from statfs(2)
+var linuxFSTypes = map[int64]string{
+	0xEF53:     "ext4",
+	0x9123683E: "btrfs",
+	0x58465342: "xfs",
+	0x01021994: "tmpfs",
+	0x794C7630: "overlayfs",
+	0x0027E0EB: "cgroup",
+	0x63677270: "cgroup2",
+	0x62656572: "sysfs",
+	0x9FA0:     "proc",
+	0x2FC12FC1: "zfs",
+	0x4D44:     "vfat",
+	0x5346544E: "ntfs",
+	0x6969:     "nfs",
+	0x517B:     "smbfs",
+	0xFF534D42: "cifs",
+	0xFE534D42: "smb2",
+	0x01021997: "9p",
+	0x00C36400: "ceph",
+	0x5346414F: "afs",
+	0x73757245: "coda",
+	0x65735546: "fuse",
+}
+
+// filesystemType returns the filesystem name of path, e.g. "ext4" or "nfs"
+func filesystemType(path string) string {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return ""
+	}
+	if name, ok := linuxFSTypes[int64(stat.Type)]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%x", stat.Type)
+}
+
+// firmlinkedVolumes is darwin only; elsewhere every device is its own filesystem
+func firmlinkedVolumes() (system, data uint64, ok bool) {
+	return 0, 0, false
+}
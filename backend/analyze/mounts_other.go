@@ -0,0 +1,13 @@
+//go:build !darwin && !linux
+
+package analyze
+
+// filesystemType is unknown on this platform, so every mount counts as local
+func filesystemType(path string) string {
+	return ""
+}
+
+// firmlinkedVolumes is darwin only; elsewhere every device is its own filesystem
+func firmlinkedVolumes() (system, data uint64, ok bool) {
+	return 0, 0, false
+}
@@ -0,0 +1,70 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+func TestRemoteMountMatchesPathsBelow(t *testing.T) {
+	c := newMountCollector()
+	c.remote[7] = "/mnt/share"
+
+	tests := []struct {
+		path   string
+		remote bool
+	}{
+		{"/mnt/share", true},
+		{"/mnt/share/a/b", true},
+		{"/mnt/shared", false},
+		{"/mnt", false},
+	}
+	for _, tt := range tests {
+		if dev, ok := c.remoteMount(tt.path); ok != tt.remote || (ok && dev != 7) {
+			t.Fatalf("%s: got dev %d, remote=%v; want remote=%v", tt.path, dev, ok, tt.remote)
+		}
+	}
+}
+
+func TestWithRemoteTimeoutGivesUp(t *testing.T) {
+	opts, err := newScanOptions(models.ScanOptions{RemoteDirTimeoutMs: 10}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.mounts.byPath["/mnt/share"] = &mountEntry{Path: "/mnt/share", Kind: mountKindNetwork}
+	opts.mounts.remote[7] = "/mnt/share"
+
+	release := make(chan struct{})
+	defer close(release)
+	hang := func() (int, error) {
+		<-release
+		return 1, nil
+	}
+
+	if _, err := withRemoteTimeout(opts, "/mnt/share/dir", hang); err == nil {
+		t.Fatalf("expected a timeout on the remote mount")
+	}
+	if got := opts.mounts.byPath["/mnt/share"].TimedOut; got != 1 {
+		t.Fatalf("expected the mount to record one timeout, got %d", got)
+	}
+
+	start := time.Now()
+	value, err := withRemoteTimeout(opts, "/local/dir", func() (int, error) { return 2, nil })
+	if err != nil || value != 2 || time.Since(start) > time.Second {
+		t.Fatalf("local call: got %d, %v", value, err)
+	}
+}
+
+func TestSameFilesystem(t *testing.T) {
+	if !sameFilesystem(3, 3) {
+		t.Fatalf("a device must be on its own filesystem")
+	}
+	system, data, ok := firmlinkedVolumes()
+	if ok && !sameFilesystem(system, data) {
+		t.Fatalf("the system and data volumes must count as one filesystem")
+	}
+	if sameFilesystem(3, 4) && !ok {
+		t.Fatalf("distinct devices must differ without firmlinks")
+	}
+}
@@ -0,0 +1,246 @@
+package analyze
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"mole-wails/backend/models"
+)
+
+// scanOptions is the validated form of models.ScanOptions used by the scanner
+type scanOptions struct {
+	maxEntries       int
+	maxLargeFiles    int
+	minLargeFileSize int64
+	followSymlinks   bool
+	stayOnFilesystem bool
+	skipRemoteMounts bool
+	remoteDirTimeout time.Duration
+	foldPatterns     []string
+	skipPatterns     []string
+	maxWorkers       int
+	nodeBudget       int
+
+	// Per-scan state
+	rootDev uint64          // Device of the scan root, for stayOnFilesystem
+	visited *sync.Map       // Directories already entered, when following symlinks
+	types   *typeCollector  // File type breakdown
+	stale   map[string]bool // Directories re-read even if their mtime is unchanged
+	mounts  *mountCollector // Mount points met, see mounts.go
+	dirs    *dirIndex       // Directories walked or reused, see revalidate.go
+
+	prevDirs    []dirStamp // Index of the earlier scan being revalidated
+	prevIndexed bool
+}
+
+// newScanOptions fills in defaults and validates the patterns
+func newScanOptions(opts models.ScanOptions, nodeBudget int) (*scanOptions, error) {
+	o := &scanOptions{
+		maxEntries:       opts.MaxEntries,
+		maxLargeFiles:    opts.MaxLargeFiles,
+		minLargeFileSize: opts.MinLargeFileSize,
+		followSymlinks:   opts.FollowSymlinks,
+		stayOnFilesystem: opts.StayOnFilesystem,
+		skipRemoteMounts: opts.SkipRemoteMounts,
+		remoteDirTimeout: time.Duration(opts.RemoteDirTimeoutMs) * time.Millisecond,
+		maxWorkers:       opts.MaxWorkers,
+		nodeBudget:       opts.NodeBudget,
+		visited:          &sync.Map{},
+		types:            &typeCollector{},
+		mounts:           newMountCollector(),
+		dirs:             &dirIndex{},
+	}
+	if o.maxEntries <= 0 {
+		o.maxEntries = maxEntries
+	}
+	if o.maxLargeFiles <= 0 {
+		o.maxLargeFiles = maxLargeFiles
+	}
+	if o.minLargeFileSize <= 0 {
+		o.minLargeFileSize = minLargeFileSize
+	}
+	if o.remoteDirTimeout <= 0 {
+		o.remoteDirTimeout = defaultRemoteDirTimeout
+	}
+	if o.maxWorkers <= 0 {
+		o.maxWorkers = maxWorkers
+	}
+	if o.nodeBudget <= 0 {
+		o.nodeBudget = nodeBudget
+	}
+	if o.nodeBudget <= 0 {
+		o.nodeBudget = defaultTreeNodeBudget
+	}
+
+	var err error
+	if o.foldPatterns, err = normalizePatterns(opts.FoldPatterns); err != nil {
+		return nil, fmt.Errorf("invalid fold pattern: %w", err)
+	}
+	if o.skipPatterns, err = normalizePatterns(opts.SkipPatterns); err != nil {
+		return nil, fmt.Errorf("invalid skip pattern: %w", err)
+	}
+	return o, nil
+}
+
+func defaultScanOptions() *scanOptions {
+	o, _ := newScanOptions(models.ScanOptions{}, defaultTreeNodeBudget)
+	return o
+}
+
+// normalizePatterns trims, de-duplicates and sorts patterns so equal sets
+// produce equal cache keys
+func normalizePatterns(patterns []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var out []string
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || seen[p] {
+			continue
+		}
+		if _, err := filepath.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("%q: %w", p, err)
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// cacheKey identifies a scan of path with these options. Default options
+// use the bare path so existing cache entries stay valid.
+func (o *scanOptions) cacheKey(path string) string {
+	if o.isDefault() {
+		return path
+	}
+	return fmt.Sprintf("%s#%x", path, xxhash.Sum64String(o.fingerprint()))
+}
+
+// isDefault reports whether these are the default options
+func (o *scanOptions) isDefault() bool {
+	return o.fingerprint() == defaultScanOptions().fingerprint()
+}
+
+func (o *scanOptions) fingerprint() string {
+	return fmt.Sprintf("e=%d;l=%d;m=%d;s=%t;x=%t;r=%t;t=%d;f=%s;k=%s;w=%d;n=%d",
+		o.maxEntries, o.maxLargeFiles, o.minLargeFileSize,
+		o.followSymlinks, o.stayOnFilesystem, o.skipRemoteMounts, o.remoteDirTimeout.Milliseconds(),
+		strings.Join(o.foldPatterns, "\x00"), strings.Join(o.skipPatterns, "\x00"),
+		o.maxWorkers, o.nodeBudget)
+}
+
+// treeFingerprint covers the options that decide which entries a scan sees.
+// Limits and the node budget only change how much of the tree is kept.
+func (o *scanOptions) treeFingerprint() string {
+	return fmt.Sprintf("s=%t;x=%t;r=%t;f=%s;k=%s",
+		o.followSymlinks, o.stayOnFilesystem, o.skipRemoteMounts,
+		strings.Join(o.foldPatterns, "\x00"), strings.Join(o.skipPatterns, "\x00"))
+}
+
+// sameTreeOptions reports whether scans with a and b see the same entries
+func sameTreeOptions(a, b models.ScanOptions) bool {
+	optsA, errA := newScanOptions(a, 0)
+	optsB, errB := newScanOptions(b, 0)
+	return errA == nil && errB == nil && optsA.treeFingerprint() == optsB.treeFingerprint()
+}
+
+// isFolded reports whether a directory is sized without being expanded
+func (o *scanOptions) isFolded(name, path string) bool {
+	return shouldFoldDirWithPath(name, path) || matchAnyPattern(o.foldPatterns, name)
+}
+
+// isSkipped reports whether an entry is left out of the scan
+func (o *scanOptions) isSkipped(name string) bool {
+	return matchAnyPattern(o.skipPatterns, name)
+}
+
+// isExcludedPath reports whether any component of path is folded or skipped
+func (o *scanOptions) isExcludedPath(path string) bool {
+	if isInFoldedDir(path) {
+		return true
+	}
+	for _, part := range strings.Split(path, string(os.PathSeparator)) {
+		if part != "" && (matchAnyPattern(o.foldPatterns, part) || o.isSkipped(part)) {
+			return true
+		}
+	}
+	return false
+}
+
+// setRoot records the scan root's device. A root on a network or FUSE mount
+// gets the same listing timeouts as one met below it.
+func (o *scanOptions) setRoot(root string) {
+	if info, err := os.Stat(root); err == nil {
+		o.rootDev, _ = deviceID(info)
+		o.enterDir(info)
+		if fsType, _ := filesystemTypeWithTimeout(root, o.remoteDirTimeout); mountKindOf(fsType) != mountKindLocal {
+			o.mounts.mu.Lock()
+			o.mounts.remote[o.rootDev] = root
+			o.mounts.mu.Unlock()
+		}
+	}
+}
+
+// admitDir applies the mount and symlink-loop rules to a directory whose
+// parent is on device parentDev. mount is set when the directory is a mount
+// point, whether or not it is descended into.
+func (o *scanOptions) admitDir(path string, info fs.FileInfo, parentDev uint64) (ok bool, mount *mountEntry) {
+	if info == nil {
+		return true, nil
+	}
+	if dev, known := deviceID(info); known && !sameFilesystem(dev, parentDev) {
+		if mount = o.crossMount(path, dev); mount.Skipped {
+			return false, mount
+		}
+	}
+	return o.enterDir(info), mount
+}
+
+// crossesFilesystem reports whether a file lives on another device than the root
+func (o *scanOptions) crossesFilesystem(info fs.FileInfo) bool {
+	if !o.stayOnFilesystem {
+		return false
+	}
+	dev, ok := deviceID(info)
+	return ok && !sameFilesystem(dev, o.rootDev)
+}
+
+// enterDir marks a directory as walked and reports whether it was new.
+// It only guards symlink loops, so it always succeeds when links are not followed.
+func (o *scanOptions) enterDir(info fs.FileInfo) bool {
+	if !o.followSymlinks {
+		return true
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return true
+	}
+	key := [2]uint64{uint64(stat.Dev), uint64(stat.Ino)}
+	_, loaded := o.visited.LoadOrStore(key, true)
+	return !loaded
+}
+
+func deviceID(info fs.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
+
+func matchAnyPattern(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
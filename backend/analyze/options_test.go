@@ -0,0 +1,108 @@
+package analyze
+
+import (
+	"strings"
+	"testing"
+
+	"mole-wails/backend/models"
+)
+
+func TestCacheKeyNormalization(t *testing.T) {
+	const path = "/Users/u/Projects"
+
+	key := func(options models.ScanOptions) string {
+		t.Helper()
+		opts, err := newScanOptions(options, defaultTreeNodeBudget)
+		if err != nil {
+			t.Fatalf("newScanOptions(%+v): %v", options, err)
+		}
+		return opts.cacheKey(path)
+	}
+
+	tests := []struct {
+		name string
+		a, b models.ScanOptions
+		same bool
+	}{
+		{
+			name: "explicit defaults",
+			a:    models.ScanOptions{},
+			b:    models.ScanOptions{MaxEntries: maxEntries, MaxLargeFiles: maxLargeFiles, MaxWorkers: maxWorkers, NodeBudget: defaultTreeNodeBudget},
+			same: true,
+		},
+		{
+			name: "pattern order, duplicates and whitespace",
+			a:    models.ScanOptions{FoldPatterns: []string{"node_modules", "*.photoslibrary"}},
+			b:    models.ScanOptions{FoldPatterns: []string{" *.photoslibrary", "node_modules", "node_modules", ""}},
+			same: true,
+		},
+		{
+			name: "fold and skip patterns are distinct",
+			a:    models.ScanOptions{FoldPatterns: []string{"build"}},
+			b:    models.ScanOptions{SkipPatterns: []string{"build"}},
+			same: false,
+		},
+		{
+			name: "node budget",
+			a:    models.ScanOptions{},
+			b:    models.ScanOptions{NodeBudget: defaultTreeNodeBudget / 2},
+			same: false,
+		},
+		{
+			name: "follow symlinks",
+			a:    models.ScanOptions{},
+			b:    models.ScanOptions{FollowSymlinks: true},
+			same: false,
+		},
+	}
+
+	for _, tt := range tests {
+		a, b := key(tt.a), key(tt.b)
+		if (a == b) != tt.same {
+			t.Fatalf("%s: keys %q and %q, want same=%v", tt.name, a, b, tt.same)
+		}
+	}
+
+	if got := key(models.ScanOptions{}); got != path {
+		t.Fatalf("default options: got key %q, want the bare path", got)
+	}
+	if got := key(models.ScanOptions{FollowSymlinks: true}); !strings.HasPrefix(got, path+"#") {
+		t.Fatalf("custom options: got key %q, want %q followed by a hash", got, path+"#")
+	}
+}
+
+func TestNewScanOptionsRejectsBadPatterns(t *testing.T) {
+	if _, err := newScanOptions(models.ScanOptions{FoldPatterns: []string{"[unclosed"}}, 0); err == nil {
+		t.Fatalf("expected an error for an invalid fold pattern")
+	}
+	if _, err := newScanOptions(models.ScanOptions{SkipPatterns: []string{"[unclosed"}}, 0); err == nil {
+		t.Fatalf("expected an error for an invalid skip pattern")
+	}
+}
+
+func TestFindTreeNodeMatchesOptions(t *testing.T) {
+	custom, err := newScanOptions(models.ScanOptions{FollowSymlinks: true}, defaultTreeNodeBudget)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaults := defaultScanOptions()
+
+	cm := newCacheManager()
+	cm.set(defaults.cacheKey("/a"), &scanResult{Path: "/a", Tree: &treeNode{Name: "a", IsDir: true, Size: 1}})
+	cm.set(custom.cacheKey("/a"), &scanResult{Path: "/a", Tree: &treeNode{Name: "a", IsDir: true, Size: 2}})
+
+	for _, tt := range []struct {
+		opts *scanOptions
+		size int64
+	}{{defaults, 1}, {custom, 2}} {
+		node, found := cm.findTreeNode("/a", tt.opts)
+		if !found || node.Size != tt.size {
+			t.Fatalf("expected the tree of size %d, got %+v (found=%v)", tt.size, node, found)
+		}
+	}
+
+	other, _ := newScanOptions(models.ScanOptions{StayOnFilesystem: true}, defaultTreeNodeBudget)
+	if _, found := cm.findTreeNode("/a", other); found {
+		t.Fatalf("expected no tree for options that were never scanned")
+	}
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package analyze
+
+import (
+	"os"
+)
+
+// createOverviewEntries generates the standard overview entries for disk analysis
+func createOverviewEntries() []dirEntry {
+	entries := []dirEntry{}
+	if home := os.Getenv("HOME"); home != "" {
+		entries = append(entries, dirEntry{Name: "Home", Path: home, IsDir: true, Size: -1})
+	}
+
+	// Third-party software installed outside the package manager
+	if info, err := os.Stat("/opt"); err == nil && info.IsDir() {
+		entries = append(entries, dirEntry{Name: "Optional Software", Path: "/opt", IsDir: true, Size: -1})
+	}
+	return entries
+}
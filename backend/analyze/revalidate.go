@@ -0,0 +1,401 @@
+package analyze
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// Revalidation
+//
+// Each directory in a scan tree remembers its mtime and the type breakdown of
+// its subtree. Rescanning with the previous tree (see scanPathConcurrent)
+// stats every directory it kept: a directory whose mtime is unchanged is not
+// read again, and a changed one is re-read while its unchanged subdirectories
+// are still reused. Only the changed parts are walked.
+//
+// Directories that were walked but not kept in the tree (below a collapsed
+// directory or folded into "other") are stamped in the result's index, so
+// reusing a collapsed directory, or a directory's "other" node, first checks
+// every directory below it. Without an index (more than maxIndexedDirs
+// directories) those subtrees are walked again. Folded directories are sized
+// with du again, since du reports nothing about what is below them.
+//
+// A directory's mtime only moves when entries are added, removed or renamed,
+// so a file growing in place goes unnoticed until the entry expires, unless the
+// Linux watcher marked its directory stale.
+
+// unchanged reports whether the directory at path still matches prev, its
+// node from an earlier scan
+func (o *scanOptions) unchanged(prev *treeNode, path string, info fs.FileInfo) bool {
+	return prev != nil && prev.IsDir && !prev.Other && prev.Types != nil &&
+		prev.ModTime == info.ModTime().UnixNano() && !o.stale[path]
+}
+
+// dirStamp is the mtime of a directory walked by a scan
+type dirStamp struct {
+	Path    string
+	ModTime int64
+}
+
+// dirIndex collects the stamps of the directories a scan walks or reuses
+type dirIndex struct {
+	mu     sync.Mutex
+	stamps []dirStamp
+}
+
+func (d *dirIndex) add(stamps ...dirStamp) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stamps = append(d.stamps, stamps...)
+}
+
+// hidden returns the stamps of the directories that are not in tree (scanned
+// at root), sorted by path, or false when there are more than maxIndexedDirs
+func (d *dirIndex) hidden(tree *treeNode, root string) ([]dirStamp, bool) {
+	kept := make(map[string]bool)
+	var visit func(node *treeNode, path string)
+	visit = func(node *treeNode, path string) {
+		if !node.IsDir || node.Other {
+			return
+		}
+		kept[path] = true
+		for i := range node.Children {
+			visit(&node.Children[i], filepath.Join(path, node.Children[i].Name))
+		}
+	}
+	if tree != nil {
+		visit(tree, root)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var hidden []dirStamp
+	for _, stamp := range d.stamps {
+		if kept[stamp.Path] {
+			continue
+		}
+		if len(hidden) == maxIndexedDirs {
+			return nil, false
+		}
+		hidden = append(hidden, stamp)
+	}
+	sort.Slice(hidden, func(i, j int) bool { return hidden[i].Path < hidden[j].Path })
+	return hidden, true
+}
+
+// stampsBelow returns the stamps of the directories below path; sorted paths
+// sharing a prefix are contiguous
+func stampsBelow(stamps []dirStamp, path string) []dirStamp {
+	prefix := path
+	if !strings.HasSuffix(prefix, string(os.PathSeparator)) {
+		prefix += string(os.PathSeparator)
+	}
+	start := sort.Search(len(stamps), func(i int) bool { return stamps[i].Path >= prefix })
+	end := start
+	for end < len(stamps) && strings.HasPrefix(stamps[end].Path, prefix) {
+		end++
+	}
+	return stamps[start:end]
+}
+
+// hiddenUnchanged reports whether the directories the earlier scan walked
+// below path but did not keep still have their mtime, leaving out those below
+// the subdirectories in kept, which are checked on their own. The stamps
+// checked carry over to the new index.
+func (o *scanOptions) hiddenUnchanged(path string, kept map[string]bool) bool {
+	if !o.prevIndexed {
+		return false
+	}
+	below := stampsBelow(o.prevDirs, path)
+	checked := make([]dirStamp, 0, len(below))
+	for _, stamp := range below {
+		rel := strings.TrimPrefix(stamp.Path[len(path):], string(os.PathSeparator))
+		if name, _, _ := strings.Cut(rel, string(os.PathSeparator)); kept[name] {
+			continue
+		}
+		if o.stale[stamp.Path] {
+			return false
+		}
+		if info, err := o.stat(stamp.Path); err != nil || info.ModTime().UnixNano() != stamp.ModTime {
+			return false
+		}
+		checked = append(checked, stamp)
+	}
+	o.dirs.add(checked...)
+	return true
+}
+
+// keptSubdirs returns the names of prev's subdirectories kept in the tree
+func keptSubdirs(prev *treeNode) map[string]bool {
+	kept := make(map[string]bool, len(prev.Children))
+	for i := range prev.Children {
+		if prev.Children[i].IsDir && !prev.Children[i].Other {
+			kept[prev.Children[i].Name] = true
+		}
+	}
+	return kept
+}
+
+// reusableChildren reports whether every subdirectory of prev carries the
+// breakdown needed to reuse it
+func reusableChildren(prev *treeNode) bool {
+	for i := range prev.Children {
+		child := &prev.Children[i]
+		if child.IsDir && !child.Other && child.Types == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// prevChild returns the subdirectory name of prev, if the earlier scan kept it
+func prevChild(prev *treeNode, name string) *treeNode {
+	if prev == nil {
+		return nil
+	}
+	for i := range prev.Children {
+		child := &prev.Children[i]
+		if child.Name == name && child.IsDir && !child.Other {
+			return child
+		}
+	}
+	return nil
+}
+
+// ownTypes is the part of prev's breakdown that is not in its subdirectories:
+// its own files and whatever was folded into "other"
+func ownTypes(prev *treeNode) typeVector {
+	own := append(typeVector{}, prev.Types...)
+	for i := range prev.Children {
+		child := &prev.Children[i]
+		if child.IsDir && !child.Other {
+			own.sub(child.Types)
+		}
+	}
+	return own
+}
+
+// subtreeTypes completes a directory's breakdown once its kids are filled in.
+// Collapsed kids were not walked, so their breakdown is added to tally for the
+// collector; walked kids have already reported theirs.
+func subtreeTypes(tally *typeTally, kids []treeNode) typeVector {
+	for i := range kids {
+		if kids[i].IsDir && kids[i].Collapsed {
+			tally.addVector(kids[i].Types)
+		}
+	}
+	types := tally.vector()
+	for i := range kids {
+		if kids[i].IsDir && !kids[i].Collapsed && !kids[i].Other {
+			types.add(kids[i].Types)
+		}
+	}
+	return types
+}
+
+func dirModTime(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().UnixNano()
+}
+
+// carryOverLargeFiles merges the large files of an earlier scan into files.
+// They are stat'ed again, so deleted files drop out and sizes are current.
+func carryOverLargeFiles(files, prev []fileEntry, opts *scanOptions) []fileEntry {
+	seen := make(map[string]bool, len(files))
+	for _, file := range files {
+		seen[file.Path] = true
+	}
+	for _, file := range prev {
+		if seen[file.Path] {
+			continue
+		}
+		size, ok := currentFileSize(file.Path)
+		if !ok || size < opts.minLargeFileSize {
+			continue
+		}
+		file.Size = size
+		files = append(files, file)
+		seen[file.Path] = true
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return files[i].Size > files[j].Size })
+	if len(files) > opts.maxLargeFiles {
+		files = files[:opts.maxLargeFiles]
+	}
+	return files
+}
+
+// carryOverTopFiles offers the top files of an earlier scan to collector.
+// App bundles are sized from the new tree, everything else is stat'ed again.
+func carryOverTopFiles(collector *typeCollector, prev []typeStat, root string, tree *treeNode) {
+	for category, stat := range prev {
+		if category >= numFileCategories || stat.Category != fileCategoryIDs[category] {
+			return
+		}
+		var files []fileEntry
+		for _, file := range stat.TopFiles {
+			if category == categoryAppBundles {
+				node := findTreeNode(tree, root, file.Path)
+				if node == nil {
+					continue
+				}
+				file.Size = node.Size
+			} else {
+				size, ok := currentFileSize(file.Path)
+				if !ok {
+					continue
+				}
+				file.Size = size
+			}
+			files = append(files, file)
+		}
+		collector.addTopFiles(category, files)
+	}
+}
+
+func currentFileSize(path string) (int64, bool) {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return 0, false
+	}
+	return getActualFileSize(path, info), true
+}
+
+// WatchCaches keeps the scans cached in memory fresh while enabled by
+// watching their directories for changes. Only available on Linux.
+func (s *Service) WatchCaches(enabled bool) error {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	if !enabled {
+		if s.watcher != nil {
+			s.watcher.close()
+			s.watcher = nil
+		}
+		return nil
+	}
+	if s.watcher != nil {
+		return nil
+	}
+
+	watcher, err := newCacheWatcher(s.refreshStale)
+	if err != nil {
+		return fmt.Errorf("failed to watch scan caches: %w", err)
+	}
+	s.watcher = watcher
+	for _, result := range s.cache.results() {
+		watcher.watchTree(result.Path, result.Tree)
+	}
+	return nil
+}
+
+func (s *Service) watchResult(result *scanResult) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	if s.watcher != nil {
+		s.watcher.watchTree(result.Path, result.Tree)
+	}
+}
+
+// staleRefresh holds the changes pending for one cached scan
+type staleRefresh struct {
+	dirs    map[string]bool
+	running bool      // A goroutine is refreshing the scan
+	last    time.Time // When the last refresh started
+}
+
+func (s *Service) watching() bool {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+	return s.watcher != nil
+}
+
+// refreshStale queues the changed directories for the cached scans containing
+// them. Each scan is refreshed by one goroutine at a time, at most once per
+// watchRefreshInterval, so changes arriving meanwhile are merged.
+func (s *Service) refreshStale(dirs map[string]bool) {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	if s.refreshes == nil {
+		s.refreshes = make(map[string]*staleRefresh)
+	}
+
+	for key, result := range s.cache.results() {
+		refresh := s.refreshes[key]
+		for dir := range dirs {
+			if rel, err := filepath.Rel(result.Path, dir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+				if refresh == nil {
+					refresh = &staleRefresh{dirs: make(map[string]bool)}
+					s.refreshes[key] = refresh
+				}
+				refresh.dirs[dir] = true
+			}
+		}
+		if refresh != nil && len(refresh.dirs) > 0 && !refresh.running {
+			refresh.running = true
+			go s.runRefresh(key, refresh)
+		}
+	}
+}
+
+// runRefresh refreshes the cached scan under key until no changes are pending
+func (s *Service) runRefresh(key string, refresh *staleRefresh) {
+	for {
+		s.refreshMu.Lock()
+		wait := time.Until(refresh.last.Add(watchRefreshInterval))
+		s.refreshMu.Unlock()
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+
+		s.refreshMu.Lock()
+		stale := refresh.dirs
+		if len(stale) == 0 || !s.watching() {
+			refresh.dirs = make(map[string]bool)
+			refresh.running = false
+			s.refreshMu.Unlock()
+			return
+		}
+		refresh.dirs = make(map[string]bool)
+		refresh.last = time.Now()
+		s.refreshMu.Unlock()
+
+		s.refreshScan(key, stale)
+	}
+}
+
+// refreshScan revalidates the cached scan under key, re-reading the stale
+// directories even if their mtime did not move. It runs under the scan
+// context, so CancelScan stops it.
+func (s *Service) refreshScan(key string, stale map[string]bool) {
+	result, found := s.cache.get(key)
+	if !found {
+		return
+	}
+	opts, err := newScanOptions(result.Options, s.treeBudget())
+	if err != nil {
+		return
+	}
+	opts.stale = stale
+	fresh, err := scanDirectoryInternal(s.scanContext(), result.Path, opts, result, nil)
+	if err != nil {
+		return
+	}
+	fresh.Options = result.Options
+	s.cache.set(key, fresh)
+	s.watchResult(fresh)
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, "analyze:cache-updated", result.Path)
+	}
+}
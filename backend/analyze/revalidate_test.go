@@ -0,0 +1,90 @@
+package analyze
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mole-wails/backend/models"
+)
+
+func TestStampsBelow(t *testing.T) {
+	stamps := []dirStamp{
+		{Path: "/a"},
+		{Path: "/a/b"},
+		{Path: "/a/b/c"},
+		{Path: "/a/bc"},
+		{Path: "/ab"},
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/a", []string{"/a/b", "/a/b/c", "/a/bc"}},
+		{"/a/b", []string{"/a/b/c"}},
+		{"/ab", nil},
+		{"/", []string{"/a", "/a/b", "/a/b/c", "/a/bc", "/ab"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, stamp := range stampsBelow(stamps, tt.path) {
+			got = append(got, stamp.Path)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: got %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRevalidationSeesChangesBelowCollapsedDirs(t *testing.T) {
+	root := t.TempDir()
+	deep := filepath.Join(root, "a", "top", "mid", "deep")
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, deep, "a", make([]byte, 4096))
+	writeTestFile(t, root, "f", make([]byte, 4096))
+
+	scan := func(prev *scanResult) *scanResult {
+		t.Helper()
+		// A budget of 4 keeps root, "a", "f" and "top", which is collapsed
+		opts, err := newScanOptions(models.ScanOptions{}, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := scanDirectoryInternal(context.Background(), root, opts, prev, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	first := scan(nil)
+	top := findTreeNode(first.Tree, root, filepath.Join(root, "a", "top"))
+	if top == nil || !top.Collapsed {
+		t.Fatalf("expected top to be collapsed, got %+v", top)
+	}
+	if !first.Indexed || len(first.Hidden) != 2 {
+		t.Fatalf("expected mid and deep in the index, got %+v (indexed=%v)", first.Hidden, first.Indexed)
+	}
+
+	// Changes two levels below "top" leave its own mtime alone
+	writeTestFile(t, deep, "b", make([]byte, 64<<10))
+
+	second := scan(first)
+	if second.TotalSize <= first.TotalSize {
+		t.Fatalf("expected the new file to be counted: %d then %d", first.TotalSize, second.TotalSize)
+	}
+	if !second.Indexed || len(second.Hidden) != 2 {
+		t.Fatalf("expected the index to be rebuilt, got %+v", second.Hidden)
+	}
+
+	third := scan(second)
+	if third.TotalSize != second.TotalSize || !reflect.DeepEqual(third.Hidden, second.Hidden) {
+		t.Fatalf("unchanged rescan differs: %d then %d, %+v then %+v", second.TotalSize, third.TotalSize, second.Hidden, third.Hidden)
+	}
+}
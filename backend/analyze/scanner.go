@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"golang.org/x/sync/singleflight"
+	"mole-wails/backend/models"
 )
 
 // Type definitions for scanner results
@@ -29,6 +30,7 @@ type dirEntry struct {
 	Size       int64
 	IsDir      bool
 	LastAccess time.Time
+	Mount      string // Label when the entry is a mount point
 }
 
 type fileEntry struct {
@@ -38,15 +40,29 @@ type fileEntry struct {
 }
 
 type scanResult struct {
+	Path       string
 	Entries    []dirEntry
 	LargeFiles []fileEntry
+	Types      []typeStat
 	TotalSize  int64
+	Tree       *treeNode
+	Mounts     []mountEntry
+	Hidden     []dirStamp         // Walked directories not kept in Tree, see revalidate.go
+	Indexed    bool               // Whether Hidden lists all of them
+	Scanned    time.Time          // When the tree was last walked in full; revalidation keeps it
+	Validated  time.Time          // When the result was last checked against the disk
+	Options    models.ScanOptions // What the scan ran with, for background revalidation
 }
 
 type cacheEntry struct {
 	Entries    []dirEntry
 	LargeFiles []fileEntry
+	Types      []typeStat
 	TotalSize  int64
+	Tree       *treeNode
+	Mounts     []mountEntry
+	Hidden     []dirStamp
+	Indexed    bool
 	ModTime    time.Time
 	ScanTime   time.Time
 }
@@ -66,11 +82,22 @@ type historyEntry struct {
 
 var scanGroup singleflight.Group
 
-func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) (scanResult, error) {
-	children, err := os.ReadDir(root)
+// scanPathConcurrent scans root. prev is an earlier scan of root, or nil;
+// its unchanged subtrees are reused instead of walked again (see revalidate.go).
+func scanPathConcurrent(ctx context.Context, root string, opts *scanOptions, prev *scanResult, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) (scanResult, error) {
+	rootInfo, err := opts.stat(root)
 	if err != nil {
 		return scanResult{}, err
 	}
+	children, err := opts.readDir(root, rootInfo)
+	if err != nil {
+		return scanResult{}, err
+	}
+	var prevTree *treeNode
+	if prev != nil {
+		prevTree = prev.Tree
+		opts.prevDirs, opts.prevIndexed = prev.Hidden, prev.Indexed
+	}
 
 	var total int64
 
@@ -88,8 +115,8 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	if numWorkers < minWorkers {
 		numWorkers = minWorkers
 	}
-	if numWorkers > maxWorkers {
-		numWorkers = maxWorkers
+	if numWorkers > opts.maxWorkers {
+		numWorkers = opts.maxWorkers
 	}
 	if numWorkers > len(children) {
 		numWorkers = len(children)
@@ -102,7 +129,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 
 	// Use channels to collect results without lock contention
 	entryChan := make(chan dirEntry, len(children))
-	largeFileChan := make(chan fileEntry, maxLargeFiles*2)
+	largeFileChan := make(chan fileEntry, opts.maxLargeFiles*2)
 
 	// Start goroutines to collect from channels into heaps
 	var collectorWg sync.WaitGroup
@@ -111,7 +138,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		defer collectorWg.Done()
 		for entry := range entryChan {
 			// Maintain Top N Heap for entries
-			if entriesHeap.Len() < maxEntries {
+			if entriesHeap.Len() < opts.maxEntries {
 				heap.Push(entriesHeap, entry)
 			} else if entry.Size > (*entriesHeap)[0].Size {
 				heap.Pop(entriesHeap)
@@ -123,7 +150,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		defer collectorWg.Done()
 		for file := range largeFileChan {
 			// Maintain Top N Heap for large files
-			if largeFilesHeap.Len() < maxLargeFiles {
+			if largeFilesHeap.Len() < opts.maxLargeFiles {
 				heap.Push(largeFilesHeap, file)
 			} else if file.Size > (*largeFilesHeap)[0].Size {
 				heap.Pop(largeFilesHeap)
@@ -136,29 +163,77 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	home := os.Getenv("HOME")
 	isHomeDir := home != "" && root == home
 
-	for _, child := range children {
+	// Every child gets its own slot so workers can fill the tree without locking
+	tree := &treeNode{Name: filepath.Base(root), IsDir: true}
+	kids := make([]treeNode, len(children))
+	var tally typeTally
+
+	for i, child := range children {
+		if ctx.Err() != nil {
+			break
+		}
 		fullPath := filepath.Join(root, child.Name())
+		kid := &kids[i]
+
+		if opts.isSkipped(child.Name()) {
+			continue
+		}
 
 		// Skip symlinks to avoid following them into unexpected locations
 		// Use Type() instead of IsDir() to check without following symlinks
 		if child.Type()&fs.ModeSymlink != 0 {
 			// For symlinks, check if they point to a directory
-			targetInfo, err := os.Stat(fullPath)
+			targetInfo, err := opts.stat(fullPath)
 			isDir := false
 			if err == nil && targetInfo.IsDir() {
 				isDir = true
 			}
 
+			// When following links, a linked directory is scanned like a real one
+			followDir := false
+			if opts.followSymlinks && isDir {
+				followDir, _ = opts.admitDir(fullPath, targetInfo, opts.rootDev)
+			}
+			if followDir {
+				wg.Add(1)
+				go func(name, path string, kid *treeNode) {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+					if ctx.Err() != nil {
+						return
+					}
+
+					*kid = treeNode{Name: name, IsDir: true}
+					size := calculateDirSizeConcurrent(ctx, path, opts, prevChild(prevTree, name), largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath, kid)
+					atomic.AddInt64(&total, size)
+					atomic.AddInt64(dirsScanned, 1)
+
+					entryChan <- dirEntry{
+						Name:  name + " →",
+						Path:  path,
+						Size:  size,
+						IsDir: true,
+					}
+				}(child.Name(), fullPath, kid)
+				continue
+			}
+
 			// Get symlink size (we don't effectively count the target size towards parent to avoid double counting,
 			// or we just count the link size itself. Existing logic counts 'size' via getActualFileSize on the link info).
 			// Ideally we just want navigation.
 			// Re-fetching info for link itself if needed, but child.Info() does that.
-			info, err := child.Info()
+			info, err := opts.entryInfo(child, fullPath)
 			if err != nil {
 				continue
 			}
+			if opts.followSymlinks && targetInfo != nil && targetInfo.Mode().IsRegular() {
+				info = targetInfo
+			}
 			size := getActualFileSize(fullPath, info)
 			atomic.AddInt64(&total, size)
+			*kid = treeNode{Name: child.Name(), Size: size, Files: 1}
+			tally.add(fullPath, size)
 
 			entryChan <- dirEntry{
 				Name:       child.Name() + " →", // Add arrow to indicate symlink
@@ -181,14 +256,33 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 				continue
 			}
 
+			info, _ := opts.entryInfo(child, fullPath)
+			admitted, mount := opts.admitDir(fullPath, info, opts.rootDev)
+			if !admitted {
+				if mount != nil {
+					// Mount points left out are still listed, labelled and without size
+					*kid = mountPlaceholder(child.Name(), mount)
+					entryChan <- dirEntry{Name: child.Name(), Path: fullPath, IsDir: true, Mount: kid.Mount}
+				}
+				continue
+			}
+			mountName := ""
+			if mount != nil {
+				mountName = mountLabel(mount)
+			}
+
 			// Special handling for ~/Library - reuse cache to avoid duplicate scanning
-			// This is scanned separately in overview mode
-			if isHomeDir && child.Name() == "Library" {
+			// This is scanned separately in overview mode, with the default options,
+			// so other options scan it like any other directory
+			if isHomeDir && child.Name() == "Library" && opts.isDefault() {
 				wg.Add(1)
-				go func(name, path string) {
+				go func(name, path string, kid *treeNode) {
 					defer wg.Done()
 					sem <- struct{}{}
 					defer func() { <-sem }()
+					if ctx.Err() != nil {
+						return
+					}
 
 					var size int64
 					// Try overview cache first (from overview scan)
@@ -199,7 +293,12 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 						size = cached.TotalSize
 					} else {
 						// No cache available, scan normally
-						size = calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
+						*kid = treeNode{Name: name, IsDir: true}
+						size = calculateDirSizeConcurrent(ctx, path, opts, prevChild(prevTree, name), largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath, kid)
+					}
+					if kid.Name == "" {
+						// Sized from cache: drill-down scans it on demand
+						*kid = treeNode{Name: name, Size: size, IsDir: true, Collapsed: true, Types: unwalkedTypes(size)}
 					}
 					atomic.AddInt64(&total, size)
 					atomic.AddInt64(dirsScanned, 1)
@@ -211,24 +310,28 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 						IsDir:      true,
 						LastAccess: time.Time{},
 					}
-				}(child.Name(), fullPath)
+				}(child.Name(), fullPath, kid)
 				continue
 			}
 
 			// For folded directories, calculate size quickly without expanding
-			if shouldFoldDirWithPath(child.Name(), fullPath) {
+			if opts.foldsWithDu(child.Name(), fullPath) {
 				wg.Add(1)
-				go func(name, path string) {
+				go func(name, path string, kid *treeNode) {
 					defer wg.Done()
 					sem <- struct{}{}
 					defer func() { <-sem }()
+					if ctx.Err() != nil {
+						return
+					}
 
 					// Try du command first for folded dirs (much faster)
-					size, err := getDirectorySizeFromDu(path)
-					if err != nil || size <= 0 {
+					size, err := getDirectorySizeFromDu(ctx, path)
+					if (err != nil || size <= 0) && ctx.Err() == nil {
 						// Fallback to concurrent walk if du fails
-						size = calculateDirSizeFast(path, filesScanned, dirsScanned, bytesScanned, currentPath)
+						size = calculateDirSizeFast(ctx, path, filesScanned, dirsScanned, bytesScanned, currentPath)
 					}
+					*kid = treeNode{Name: name, Size: size, IsDir: true, Collapsed: true, Mount: mountName, ModTime: dirModTime(path), Types: unwalkedTypes(size)}
 					atomic.AddInt64(&total, size)
 					atomic.AddInt64(dirsScanned, 1)
 
@@ -238,19 +341,24 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 						Size:       size,
 						IsDir:      true,
 						LastAccess: time.Time{}, // Lazy load when displayed
+						Mount:      mountName,
 					}
-				}(child.Name(), fullPath)
+				}(child.Name(), fullPath, kid)
 				continue
 			}
 
 			// Normal directory: full scan with detail
 			wg.Add(1)
-			go func(name, path string) {
+			go func(name, path string, kid *treeNode) {
 				defer wg.Done()
 				sem <- struct{}{}
 				defer func() { <-sem }()
+				if ctx.Err() != nil {
+					return
+				}
 
-				size := calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
+				*kid = treeNode{Name: name, IsDir: true, Mount: mountName}
+				size := calculateDirSizeConcurrent(ctx, path, opts, prevChild(prevTree, name), largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath, kid)
 				atomic.AddInt64(&total, size)
 				atomic.AddInt64(dirsScanned, 1)
 
@@ -260,12 +368,13 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 					Size:       size,
 					IsDir:      true,
 					LastAccess: time.Time{}, // Lazy load when displayed
+					Mount:      mountName,
 				}
-			}(child.Name(), fullPath)
+			}(child.Name(), fullPath, kid)
 			continue
 		}
 
-		info, err := child.Info()
+		info, err := opts.entryInfo(child, fullPath)
 		if err != nil {
 			continue
 		}
@@ -274,6 +383,8 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		atomic.AddInt64(&total, size)
 		atomic.AddInt64(filesScanned, 1)
 		atomic.AddInt64(bytesScanned, size)
+		*kid = treeNode{Name: child.Name(), Size: size, Files: 1}
+		tally.add(fullPath, size)
 
 		entryChan <- dirEntry{
 			Name:       child.Name(),
@@ -283,7 +394,7 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 			LastAccess: getLastAccessTimeFromInfo(info),
 		}
 		// Only track large files that are not code/text files
-		if !shouldSkipFileForLargeTracking(fullPath) && size >= minLargeFileSize {
+		if !shouldSkipFileForLargeTracking(fullPath) && size >= opts.minLargeFileSize {
 			largeFileChan <- fileEntry{Name: child.Name(), Path: fullPath, Size: size}
 		}
 	}
@@ -295,6 +406,24 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	close(largeFileChan)
 	collectorWg.Wait()
 
+	// A partial walk must not be reported (or cached) as the directory's size
+	if err := ctx.Err(); err != nil {
+		return scanResult{}, err
+	}
+
+	if isAppBundle(root) && !isInsideAppBundle(root) {
+		tally.countBundle()
+	}
+	tree.Size = total
+	tree.ModTime = rootInfo.ModTime().UnixNano()
+	tree.Types = subtreeTypes(&tally, kids)
+	recordMountSizes(opts, root, kids)
+	finalizeTreeNode(tree, kids)
+	opts.types.merge(&tally)
+	if isAppBundle(root) && !isInsideAppBundle(root) {
+		opts.types.addBundle(root, total)
+	}
+
 	// Convert Heaps to sorted slices (Descending order)
 	entries := make([]dirEntry, entriesHeap.Len())
 	for i := len(entries) - 1; i >= 0; i-- {
@@ -306,11 +435,19 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 		largeFiles[i] = heap.Pop(largeFilesHeap).(fileEntry)
 	}
 
+	// Reused subtrees were not walked; carry their large and top files over
+	mounts := opts.mounts.result()
+	if prev != nil {
+		largeFiles = carryOverLargeFiles(largeFiles, prev.LargeFiles, opts)
+		carryOverTopFiles(opts.types, prev.Types, root, tree)
+		mounts = carryOverMounts(mounts, prev.Mounts)
+	}
+
 	// Try to use Spotlight (mdfind) for faster large file discovery
 	// This is a performance optimization that gracefully falls back to scan results
 	// if Spotlight is unavailable or fails. The fallback is intentionally silent
 	// because users only care about correct results, not the method used.
-	if spotlightFiles := findLargeFilesWithSpotlight(root, minLargeFileSize); len(spotlightFiles) > 0 {
+	if spotlightFiles := findLargeFilesWithSpotlight(ctx, root, opts); len(spotlightFiles) > 0 {
 		// Spotlight results are already sorted top N
 		// Use them in place of scanned large files
 		largeFiles = spotlightFiles
@@ -323,7 +460,10 @@ func scanPathConcurrent(root string, filesScanned, dirsScanned, bytesScanned *in
 	return scanResult{
 		Entries:    entries,
 		LargeFiles: largeFiles,
+		Types:      opts.types.result(),
 		TotalSize:  total,
+		Tree:       tree,
+		Mounts:     mounts,
 	}, nil
 }
 
@@ -358,12 +498,12 @@ func shouldSkipFileForLargeTracking(path string) bool {
 
 // calculateDirSizeFast performs concurrent directory size calculation using os.ReadDir
 // This is a faster fallback than filepath.WalkDir when du fails
-func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
+func calculateDirSizeFast(ctx context.Context, root string, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
 	var total int64
 	var wg sync.WaitGroup
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	// Bound the walk even when the caller never cancels
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
 	defer cancel()
 
 	// Limit total concurrency for this walk
@@ -432,11 +572,16 @@ func calculateDirSizeFast(root string, filesScanned, dirsScanned, bytesScanned *
 }
 
 // Use Spotlight (mdfind) to quickly find large files in a directory
-func findLargeFilesWithSpotlight(root string, minSize int64) []fileEntry {
+func findLargeFilesWithSpotlight(ctx context.Context, root string, opts *scanOptions) []fileEntry {
+	if opts.mounts.onRemote(root) {
+		// Network and FUSE mounts are not indexed, and mdfind can hang on them
+		return nil
+	}
+
 	// mdfind query: files >= minSize in the specified directory
-	query := fmt.Sprintf("kMDItemFSSize >= %d", minSize)
+	query := fmt.Sprintf("kMDItemFSSize >= %d", opts.minLargeFileSize)
 
-	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	ctx, cancel := context.WithTimeout(ctx, mdlsTimeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "mdfind", "-onlyin", root, query)
@@ -459,8 +604,8 @@ func findLargeFilesWithSpotlight(root string, minSize int64) []fileEntry {
 			continue
 		}
 
-		// Filter out files in folded directories (cheap string check)
-		if isInFoldedDir(line) {
+		// Filter out files in folded or skipped directories (cheap string check)
+		if opts.isExcludedPath(line) {
 			continue
 		}
 
@@ -474,6 +619,9 @@ func findLargeFilesWithSpotlight(root string, minSize int64) []fileEntry {
 		if info.IsDir() || info.Mode()&os.ModeSymlink != 0 {
 			continue
 		}
+		if opts.crossesFilesystem(info) {
+			continue
+		}
 
 		// Get actual disk usage for sparse files and cloud files
 		actualSize := getActualFileSize(line, info)
@@ -492,8 +640,8 @@ func findLargeFilesWithSpotlight(root string, minSize int64) []fileEntry {
 	})
 
 	// Return top N
-	if len(files) > maxLargeFiles {
-		files = files[:maxLargeFiles]
+	if len(files) > opts.maxLargeFiles {
+		files = files[:opts.maxLargeFiles]
 	}
 
 	return files
@@ -511,12 +659,32 @@ func isInFoldedDir(path string) bool {
 	return false
 }
 
-func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string) int64 {
-	// Read immediate children
-	children, err := os.ReadDir(root)
+// calculateDirSizeConcurrent returns the size of root and fills node, when given, with its subtree.
+// prev is root's node from an earlier scan, or nil; an unchanged directory is
+// not read again and its unchanged subdirectories are reused (see revalidate.go).
+func calculateDirSizeConcurrent(ctx context.Context, root string, opts *scanOptions, prev *treeNode, largeFileChan chan<- fileEntry, filesScanned, dirsScanned, bytesScanned *int64, currentPath *string, node *treeNode) int64 {
+	if ctx.Err() != nil {
+		return 0
+	}
+	if node == nil {
+		node = &treeNode{}
+	}
+
+	rootInfo, err := opts.stat(root)
 	if err != nil {
 		return 0
 	}
+	rootDev, _ := deviceID(rootInfo)
+	reuse := opts.unchanged(prev, root, rootInfo) && !prev.Collapsed && reusableChildren(prev) &&
+		opts.hiddenUnchanged(root, keptSubdirs(prev))
+
+	// Read immediate children
+	var children []fs.DirEntry
+	if !reuse {
+		if children, err = opts.readDir(root, rootInfo); err != nil {
+			return 0
+		}
+	}
 
 	var total int64
 	var wg sync.WaitGroup
@@ -526,85 +694,213 @@ func calculateDirSizeConcurrent(root string, largeFileChan chan<- fileEntry, fil
 	if maxConcurrent > maxDirWorkers {
 		maxConcurrent = maxDirWorkers
 	}
+	if maxConcurrent > opts.maxWorkers {
+		maxConcurrent = opts.maxWorkers
+	}
 	sem := make(chan struct{}, maxConcurrent)
 
-	for _, child := range children {
-		fullPath := filepath.Join(root, child.Name())
+	var tally typeTally
 
-		// Skip symlinks to avoid following them into unexpected locations
-		if child.Type()&fs.ModeSymlink != 0 {
-			// For symlinks, just count their size without following
-			info, err := child.Info()
-			if err != nil {
-				continue
-			}
-			size := getActualFileSize(fullPath, info)
-			total += size
-			atomic.AddInt64(filesScanned, 1)
-			atomic.AddInt64(bytesScanned, size)
-			continue
+	// sizeDir fills kid with a subdirectory: unchanged collapsed directories are
+	// reused, folded ones are sized with du, the rest are scanned recursively
+	sizeDir := func(name, path string, info fs.FileInfo, kid, prevKid *treeNode, mount *mountEntry) {
+		mountName := ""
+		if mount != nil {
+			mountName = mountLabel(mount)
 		}
 
-		if child.IsDir() {
-			// Check if this is a folded directory
-			if shouldFoldDirWithPath(child.Name(), fullPath) {
-				// Use du for folded directories (much faster)
-				wg.Add(1)
-				go func(path string) {
-					defer wg.Done()
-					size, err := getDirectorySizeFromDu(path)
-					if err == nil && size > 0 {
-						atomic.AddInt64(&total, size)
-						atomic.AddInt64(bytesScanned, size)
-						atomic.AddInt64(dirsScanned, 1)
-					}
-				}(fullPath)
-				continue
+		if prevKid != nil && prevKid.Collapsed && !opts.foldsWithDu(name, path) {
+			if info == nil {
+				info, _ = opts.stat(path)
 			}
+			if info != nil && opts.unchanged(prevKid, path, info) && opts.hiddenUnchanged(path, nil) {
+				opts.dirs.add(dirStamp{Path: path, ModTime: prevKid.ModTime})
+				*kid = *prevKid
+				kid.Mount = mountName
+				atomic.AddInt64(&total, kid.Size)
+				atomic.AddInt64(bytesScanned, kid.Size)
+				atomic.AddInt64(dirsScanned, 1)
+				return
+			}
+		}
 
-			// Recursively scan subdirectory in parallel
+		// Check if this is a folded directory
+		if opts.foldsWithDu(name, path) {
+			// Use du for folded directories (much faster)
 			wg.Add(1)
-			go func(path string) {
+			go func() {
 				defer wg.Done()
-				sem <- struct{}{}
-				defer func() { <-sem }()
-
-				size := calculateDirSizeConcurrent(path, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath)
-				atomic.AddInt64(&total, size)
-				atomic.AddInt64(dirsScanned, 1)
-			}(fullPath)
-			continue
+				size, err := getDirectorySizeFromDu(ctx, path)
+				if err == nil && size > 0 {
+					atomic.AddInt64(&total, size)
+					atomic.AddInt64(bytesScanned, size)
+					atomic.AddInt64(dirsScanned, 1)
+					*kid = treeNode{Name: name, Size: size, IsDir: true, Collapsed: true, Mount: mountName, ModTime: dirModTime(path), Types: unwalkedTypes(size)}
+				}
+			}()
+			return
 		}
 
-		// Handle files
-		info, err := child.Info()
-		if err != nil {
-			continue
-		}
+		// Recursively scan subdirectory in parallel
+		*kid = treeNode{Name: name, IsDir: true, Mount: mountName}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		size := getActualFileSize(fullPath, info)
-		total += size
-		atomic.AddInt64(filesScanned, 1)
-		atomic.AddInt64(bytesScanned, size)
+			size := calculateDirSizeConcurrent(ctx, path, opts, prevKid, largeFileChan, filesScanned, dirsScanned, bytesScanned, currentPath, kid)
+			atomic.AddInt64(&total, size)
+			atomic.AddInt64(dirsScanned, 1)
+		}()
+	}
 
-		// Track large files
-		if !shouldSkipFileForLargeTracking(fullPath) && size >= minLargeFileSize {
-			largeFileChan <- fileEntry{Name: child.Name(), Path: fullPath, Size: size}
+	var kids []treeNode
+	if reuse {
+		// Unchanged since the last scan: files and "other" are kept as they are
+		// and only the subdirectories are checked
+		kids = make([]treeNode, len(prev.Children))
+		tally.addVector(ownTypes(prev))
+		for i := range prev.Children {
+			if ctx.Err() != nil {
+				break
+			}
+			prevKid := &prev.Children[i]
+			kid := &kids[i]
+
+			if !prevKid.IsDir || prevKid.Other {
+				*kid = *prevKid
+				atomic.AddInt64(&total, kid.Size)
+				atomic.AddInt64(bytesScanned, kid.Size)
+				continue
+			}
+
+			fullPath := filepath.Join(root, prevKid.Name)
+			info, err := opts.stat(fullPath)
+			if err != nil || !info.IsDir() {
+				continue
+			}
+			admitted, mount := opts.admitDir(fullPath, info, rootDev)
+			if !admitted {
+				if mount != nil {
+					*kid = mountPlaceholder(prevKid.Name, mount)
+				}
+				continue
+			}
+			sizeDir(prevKid.Name, fullPath, info, kid, prevKid, mount)
 		}
+	} else {
+		kids = make([]treeNode, len(children))
+		for i, child := range children {
+			if ctx.Err() != nil {
+				break
+			}
+			fullPath := filepath.Join(root, child.Name())
+			kid := &kids[i]
 
-		// Update current path occasionally to prevent UI jitter
-		if currentPath != nil && atomic.LoadInt64(filesScanned)%int64(batchUpdateSize) == 0 {
-			*currentPath = fullPath
+			if opts.isSkipped(child.Name()) {
+				continue
+			}
+
+			isDir := child.IsDir()
+			var linkTarget, dirInfo fs.FileInfo
+			var mount *mountEntry
+
+			// Skip symlinks to avoid following them into unexpected locations
+			if child.Type()&fs.ModeSymlink != 0 {
+				if opts.followSymlinks {
+					linkTarget, _ = opts.stat(fullPath)
+				}
+				followDir := false
+				if linkTarget != nil && linkTarget.IsDir() {
+					followDir, mount = opts.admitDir(fullPath, linkTarget, rootDev)
+				}
+				switch {
+				case followDir:
+					isDir = true
+					dirInfo = linkTarget
+				case linkTarget != nil && linkTarget.Mode().IsRegular():
+					// Counted below like a regular file
+				default:
+					// For symlinks, just count their size without following
+					info, err := opts.entryInfo(child, fullPath)
+					if err != nil {
+						continue
+					}
+					size := getActualFileSize(fullPath, info)
+					atomic.AddInt64(&total, size)
+					atomic.AddInt64(filesScanned, 1)
+					atomic.AddInt64(bytesScanned, size)
+					*kid = treeNode{Name: child.Name(), Size: size, Files: 1}
+					tally.add(fullPath, size)
+					continue
+				}
+			} else if isDir {
+				dirInfo, _ = opts.entryInfo(child, fullPath)
+				admitted, dirMount := opts.admitDir(fullPath, dirInfo, rootDev)
+				if !admitted {
+					if dirMount != nil {
+						*kid = mountPlaceholder(child.Name(), dirMount)
+					}
+					continue
+				}
+				mount = dirMount
+			}
+
+			if isDir {
+				sizeDir(child.Name(), fullPath, dirInfo, kid, prevChild(prev, child.Name()), mount)
+				continue
+			}
+
+			// Handle files
+			info := linkTarget
+			if info == nil {
+				if info, err = opts.entryInfo(child, fullPath); err != nil {
+					continue
+				}
+			}
+
+			size := getActualFileSize(fullPath, info)
+			atomic.AddInt64(&total, size)
+			atomic.AddInt64(filesScanned, 1)
+			atomic.AddInt64(bytesScanned, size)
+			*kid = treeNode{Name: child.Name(), Size: size, Files: 1}
+			tally.add(fullPath, size)
+
+			// Track large files
+			if !shouldSkipFileForLargeTracking(fullPath) && size >= opts.minLargeFileSize {
+				largeFileChan <- fileEntry{Name: child.Name(), Path: fullPath, Size: size}
+			}
+
+			// Update current path occasionally to prevent UI jitter
+			if currentPath != nil && atomic.LoadInt64(filesScanned)%int64(batchUpdateSize) == 0 {
+				*currentPath = fullPath
+			}
+		}
+		if isAppBundle(root) && !isInsideAppBundle(root) {
+			tally.countBundle()
 		}
 	}
 
 	wg.Wait()
+
+	node.Size = total
+	node.ModTime = rootInfo.ModTime().UnixNano()
+	node.Types = subtreeTypes(&tally, kids)
+	recordMountSizes(opts, root, kids)
+	finalizeTreeNode(node, kids)
+	boundTreeNode(node, opts.nodeBudget)
+	opts.dirs.add(dirStamp{Path: root, ModTime: node.ModTime})
+	opts.types.merge(&tally)
+	if isAppBundle(root) && !isInsideAppBundle(root) {
+		opts.types.addBundle(root, total)
+	}
 	return total
 }
 
 // measureOverviewSize calculates the size of a directory using multiple strategies.
 // When scanning Home, it excludes ~/Library to avoid duplicate counting.
-func measureOverviewSize(path string) (int64, error) {
+func measureOverviewSize(ctx context.Context, path string) (int64, error) {
 	if path == "" {
 		return 0, fmt.Errorf("empty path")
 	}
@@ -618,23 +914,18 @@ func measureOverviewSize(path string) (int64, error) {
 		return 0, fmt.Errorf("cannot access path: %v", err)
 	}
 
-	// Determine if we should exclude ~/Library (when scanning Home)
-	home := os.Getenv("HOME")
-	excludePath := ""
-	if home != "" && path == home {
-		excludePath = filepath.Join(home, "Library")
-	}
+	excludePath := overviewExcludePath(path)
 
 	if cached, err := loadStoredOverviewSize(path); err == nil && cached > 0 {
 		return cached, nil
 	}
 
-	if duSize, err := getDirectorySizeFromDuWithExclude(path, excludePath); err == nil && duSize > 0 {
+	if duSize, err := getDirectorySizeFromDuWithExclude(ctx, path, excludePath); err == nil && duSize > 0 {
 		_ = storeOverviewSize(path, duSize)
 		return duSize, nil
 	}
 
-	if logicalSize, err := getDirectoryLogicalSizeWithExclude(path, excludePath); err == nil && logicalSize > 0 {
+	if logicalSize, err := getDirectoryLogicalSizeWithExclude(ctx, path, excludePath); err == nil && logicalSize > 0 {
 		_ = storeOverviewSize(path, logicalSize)
 		return logicalSize, nil
 	}
@@ -647,17 +938,26 @@ func measureOverviewSize(path string) (int64, error) {
 	return 0, fmt.Errorf("unable to measure directory size with fast methods")
 }
 
-func getDirectorySizeFromDu(path string) (int64, error) {
-	return getDirectorySizeFromDuWithExclude(path, "")
+// overviewExcludePath returns ~/Library when measuring Home, which the overview lists separately
+func overviewExcludePath(path string) string {
+	home := os.Getenv("HOME")
+	if home != "" && path == home {
+		return filepath.Join(home, "Library")
+	}
+	return ""
 }
 
-func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64, error) {
+func getDirectorySizeFromDu(ctx context.Context, path string) (int64, error) {
+	return getDirectorySizeFromDuWithExclude(ctx, path, "")
+}
+
+func getDirectorySizeFromDuWithExclude(parent context.Context, path string, excludePath string) (int64, error) {
 	runDuSize := func(target string) (int64, error) {
 		if _, err := os.Stat(target); err != nil {
 			return 0, err
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), duTimeout)
+		ctx, cancel := context.WithTimeout(parent, duTimeout)
 		defer cancel()
 
 		cmd := exec.CommandContext(ctx, "du", "-sk", target)
@@ -666,6 +966,9 @@ func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64,
 		cmd.Stderr = &stderr
 
 		if err := cmd.Run(); err != nil {
+			if parent.Err() != nil {
+				return 0, parent.Err()
+			}
 			if ctx.Err() == context.DeadlineExceeded {
 				return 0, fmt.Errorf("du timeout after %v", duTimeout)
 			}
@@ -710,9 +1013,12 @@ func getDirectorySizeFromDuWithExclude(path string, excludePath string) (int64,
 	return runDuSize(path)
 }
 
-func getDirectoryLogicalSizeWithExclude(path string, excludePath string) (int64, error) {
+func getDirectoryLogicalSizeWithExclude(ctx context.Context, path string, excludePath string) (int64, error) {
 	var total int64
 	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		if err != nil {
 			if os.IsPermission(err) {
 				return filepath.SkipDir
@@ -765,11 +1071,3 @@ func getLastAccessTime(path string) time.Time {
 	}
 	return getLastAccessTimeFromInfo(info)
 }
-
-func getLastAccessTimeFromInfo(info fs.FileInfo) time.Time {
-	stat, ok := info.Sys().(*syscall.Stat_t)
-	if !ok {
-		return time.Time{}
-	}
-	return time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec)
-}
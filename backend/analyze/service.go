@@ -2,10 +2,12 @@ package analyze
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -14,7 +16,7 @@ import (
 	"mole-wails/backend/models"
 )
 
-// cacheManager provides in-memory caching for scan results
+// cacheManager provides in-memory caching for scan results, keyed by scanOptions.cacheKey
 type cacheManager struct {
 	mu    sync.RWMutex
 	cache map[string]*scanResult
@@ -39,6 +41,45 @@ func (cm *cacheManager) set(path string, result *scanResult) {
 	cm.cache[path] = result
 }
 
+// findTreeNode looks path up in the trees of cached scans made with opts,
+// preferring the deepest scan root. Collapsed nodes are skipped so a dedicated scan can win.
+func (cm *cacheManager) findTreeNode(path string, opts *scanOptions) (*treeNode, bool) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	results := make([]*scanResult, 0, len(cm.cache))
+	for key, result := range cm.cache {
+		if result.Tree != nil && key == opts.cacheKey(result.Path) {
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return len(results[i].Path) > len(results[j].Path) })
+
+	for _, result := range results {
+		if node := findTreeNode(result.Tree, result.Path, path); node != nil && !node.Collapsed {
+			return node, true
+		}
+	}
+	return nil, false
+}
+
+// results returns the cached scans by key
+func (cm *cacheManager) results() map[string]*scanResult {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+	results := make(map[string]*scanResult, len(cm.cache))
+	for key, result := range cm.cache {
+		results[key] = result
+	}
+	return results
+}
+
+func (cm *cacheManager) clear() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.cache = make(map[string]*scanResult)
+}
+
 func (cm *cacheManager) invalidate(path string) {
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
@@ -46,22 +87,98 @@ func (cm *cacheManager) invalidate(path string) {
 }
 
 type Service struct {
-	cache *cacheManager
-	ctx   context.Context
+	cache      *cacheManager
+	ctx        context.Context
+	budgetMu   sync.Mutex
+	nodeBudget int
+
+	// scanCtx is shared by all in-flight scans so CancelScan can stop them together
+	scanMu     sync.Mutex
+	scanCtx    context.Context
+	scanCancel context.CancelFunc
+
+	// watcher keeps cached scans fresh while enabled, see WatchCaches
+	watchMu sync.Mutex
+	watcher *cacheWatcher
+
+	// refreshes are the watcher's pending refreshes by cache key
+	refreshMu sync.Mutex
+	refreshes map[string]*staleRefresh
 }
 
 func NewService() *Service {
 	return &Service{
-		cache: newCacheManager(),
+		cache:      newCacheManager(),
+		nodeBudget: defaultTreeNodeBudget,
 	}
 }
 
 func (s *Service) SetContext(ctx context.Context) {
 	s.ctx = ctx
+	if ctx != nil {
+		go s.sampleSizeHistory(ctx)
+	}
 }
 
-// ScanDirectory scans a directory and returns results
+func (s *Service) baseContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// SetNodeBudget sets how many nodes a scan tree keeps; 0 restores the default
+func (s *Service) SetNodeBudget(budget int) {
+	if budget <= 0 {
+		budget = defaultTreeNodeBudget
+	}
+	s.budgetMu.Lock()
+	defer s.budgetMu.Unlock()
+	s.nodeBudget = budget
+}
+
+// treeBudget returns the node budget new scans start with
+func (s *Service) treeBudget() int {
+	s.budgetMu.Lock()
+	defer s.budgetMu.Unlock()
+	return s.nodeBudget
+}
+
+// CancelScan stops every scan that is currently running
+func (s *Service) CancelScan() {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	if s.scanCancel != nil {
+		s.scanCancel()
+		s.scanCtx = nil
+		s.scanCancel = nil
+	}
+}
+
+// scanContext returns the context new scans run under
+func (s *Service) scanContext() context.Context {
+	s.scanMu.Lock()
+	defer s.scanMu.Unlock()
+	if s.scanCtx == nil {
+		s.scanCtx, s.scanCancel = context.WithCancel(s.baseContext())
+	}
+	return s.scanCtx
+}
+
+func (s *Service) emitProgress(progress models.ScanProgress) {
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, "analyze:progress", progress)
+	}
+}
+
+// ScanDirectory scans a directory with the default options and returns results
 func (s *Service) ScanDirectory(path string) (*models.ScanResult, error) {
+	return s.ScanDirectoryWithOptions(path, models.ScanOptions{})
+}
+
+// ScanDirectoryWithOptions scans a directory with per-request options.
+// Scans with different options are cached separately.
+func (s *Service) ScanDirectoryWithOptions(path string, options models.ScanOptions) (*models.ScanResult, error) {
 	// Validate path
 	if path == "" {
 		return nil, fmt.Errorf("path cannot be empty")
@@ -75,27 +192,110 @@ func (s *Service) ScanDirectory(path string) (*models.ScanResult, error) {
 		return nil, fmt.Errorf("cannot access path: %w", err)
 	}
 
-	// Check cache first
-	if cached, found := s.cache.get(path); found {
+	opts, err := newScanOptions(options, s.treeBudget())
+	if err != nil {
+		return nil, err
+	}
+	key := opts.cacheKey(path)
+
+	// Check cache first; older results are revalidated against the disk
+	cached, found := s.cache.get(key)
+	if found && time.Since(cached.Validated) < cacheRevalidateAfter {
 		result := s.convertToModelScanResult(cached)
 		result.Path = path
 		return result, nil
 	}
 
 	// Perform scan
-	result, err := scanDirectoryInternal(path, s.ctx)
+	result, err := scanDirectoryInternal(s.scanContext(), path, opts, cached, s.emitProgress)
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			if s.ctx != nil {
+				runtime.EventsEmit(s.ctx, "analyze:cancelled", path)
+			}
+			return nil, fmt.Errorf("scan cancelled: %s", path)
+		}
 		return nil, fmt.Errorf("scan failed: %w", err)
 	}
 
 	// Cache result
-	s.cache.set(path, result)
+	result.Options = options
+	s.cache.set(key, result)
+	s.watchResult(result)
 
 	modelResult := s.convertToModelScanResult(result)
 	modelResult.Path = path
 	return modelResult, nil
 }
 
+// GetScanTree returns the scan tree below path, depth levels deep. Paths
+// inside an earlier scan with the same options are served from memory;
+// anything else is scanned.
+func (s *Service) GetScanTree(path string, depth int, options models.ScanOptions) (*models.ScanNode, error) {
+	if depth <= 0 {
+		depth = 1
+	}
+
+	node, path, err := s.treeNodeFor(path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toModelScanNode(node, path, depth)
+	return &result, nil
+}
+
+// Treemap lays out the tree below path as squarified rectangles in a width x height viewport
+func (s *Service) Treemap(path string, width, height float64, depth int, minPixels float64, options models.ScanOptions) ([]models.TreemapRect, error) {
+	if depth <= 0 {
+		depth = defaultLayoutDepth
+	}
+
+	node, path, err := s.treeNodeFor(path, options)
+	if err != nil {
+		return nil, err
+	}
+	return layoutTreemap(node, path, width, height, depth, minPixels), nil
+}
+
+// Sunburst lays out the tree below path as rings of arcs within radius
+func (s *Service) Sunburst(path string, radius float64, depth int, minPixels float64, options models.ScanOptions) ([]models.SunburstArc, error) {
+	if depth <= 0 {
+		depth = defaultLayoutDepth
+	}
+
+	node, path, err := s.treeNodeFor(path, options)
+	if err != nil {
+		return nil, err
+	}
+	return layoutSunburst(node, path, radius, depth, minPixels), nil
+}
+
+// treeNodeFor returns the tree node for path, scanning it with options if no
+// cached scan with the same options covers it
+func (s *Service) treeNodeFor(path string, options models.ScanOptions) (*treeNode, string, error) {
+	if path == "" {
+		return nil, "", fmt.Errorf("path cannot be empty")
+	}
+	path = filepath.Clean(path)
+
+	opts, err := newScanOptions(options, s.treeBudget())
+	if err != nil {
+		return nil, "", err
+	}
+
+	if node, found := s.cache.findTreeNode(path, opts); found {
+		return node, path, nil
+	}
+	if _, err := s.ScanDirectoryWithOptions(path, options); err != nil {
+		return nil, "", err
+	}
+	if node, found := s.cache.findTreeNode(path, opts); found {
+		return node, path, nil
+	}
+	return nil, "", fmt.Errorf("no scan tree available for %s", path)
+}
+
 // GetLargeFiles returns the largest files in a directory
 func (s *Service) GetLargeFiles(path string, limit int) ([]models.FileEntry, error) {
 	fmt.Printf("[analyze] GetLargeFiles called: path=%s, limit=%d\n", path, limit)
@@ -151,6 +351,8 @@ func (s *Service) convertToModelScanResult(internal *scanResult) *models.ScanRes
 		return &models.ScanResult{
 			Entries:    []models.DirEntry{},
 			LargeFiles: []models.FileEntry{},
+			Types:      []models.FileTypeStat{},
+			Mounts:     []models.MountEntry{},
 			TotalSize:  0,
 			TotalItems: 0,
 			Path:       "",
@@ -160,6 +362,8 @@ func (s *Service) convertToModelScanResult(internal *scanResult) *models.ScanRes
 	result := &models.ScanResult{
 		Entries:    make([]models.DirEntry, len(internal.Entries)),
 		LargeFiles: make([]models.FileEntry, len(internal.LargeFiles)),
+		Types:      toModelFileTypes(internal.Types, internal.TotalSize),
+		Mounts:     toModelMounts(internal.Mounts),
 		TotalSize:  internal.TotalSize,
 		TotalItems: len(internal.Entries),
 		Path:       "", // Will be set by caller
@@ -178,6 +382,7 @@ func (s *Service) convertToModelScanResult(internal *scanResult) *models.ScanRes
 			IsDir:      entry.IsDir,
 			LastAccess: entry.LastAccess,
 			Percent:    percent,
+			Mount:      entry.Mount,
 		}
 	}
 
@@ -236,20 +441,35 @@ func safeDelete(path string) error {
 	return nil
 }
 
-// scanDirectoryInternal performs the actual directory scan with progress reporting
-func scanDirectoryInternal(path string, ctx context.Context) (*scanResult, error) {
-	// Check disk cache first
-	if cached, err := loadCacheFromDisk(path); err == nil {
-		result := &scanResult{
-			Entries:    cached.Entries,
-			LargeFiles: cached.LargeFiles,
-			TotalSize:  cached.TotalSize,
+// scanDirectoryInternal performs the actual directory scan with progress reporting.
+// prev is an earlier result for the same key, or nil to use the disk cache; either
+// way only the parts that changed since are walked (see revalidate.go).
+// A cancelled scan returns ctx.Err() and is never written to the disk cache.
+func scanDirectoryInternal(ctx context.Context, path string, opts *scanOptions, prev *scanResult, onProgress func(models.ScanProgress)) (*scanResult, error) {
+	key := opts.cacheKey(path)
+
+	if prev == nil {
+		if cached, err := loadCacheEntry(path, key); err == nil && cached.Tree != nil {
+			prev = &scanResult{
+				Path:       path,
+				Entries:    cached.Entries,
+				LargeFiles: cached.LargeFiles,
+				Types:      cached.Types,
+				TotalSize:  cached.TotalSize,
+				Tree:       cached.Tree,
+				Mounts:     cached.Mounts,
+				Hidden:     cached.Hidden,
+				Indexed:    cached.Indexed,
+				Scanned:    cached.ScanTime,
+			}
 		}
-		fmt.Printf("[analyze] Using cached scan result for path: %s (TotalSize: %d)\n", path, result.TotalSize)
-		return result, nil
 	}
 
-	fmt.Printf("[analyze] Starting fresh scan for path: %s\n", path)
+	if prev != nil {
+		fmt.Printf("[analyze] Revalidating cached scan for path: %s (TotalSize: %d)\n", path, prev.TotalSize)
+	} else {
+		fmt.Printf("[analyze] Starting fresh scan for path: %s\n", path)
+	}
 
 	// Initialize progress counters
 	var filesScanned, dirsScanned, bytesScanned int64
@@ -257,13 +477,12 @@ func scanDirectoryInternal(path string, ctx context.Context) (*scanResult, error
 
 	// Progress callback for emitting events to frontend
 	progressCallback := func() {
-		if ctx != nil {
-			progress := models.ScanProgress{
+		if onProgress != nil {
+			onProgress(models.ScanProgress{
 				Path:         path,
 				ItemsScanned: int(atomic.LoadInt64(&filesScanned) + atomic.LoadInt64(&dirsScanned)),
 				TotalSize:    atomic.LoadInt64(&bytesScanned),
-			}
-			runtime.EventsEmit(ctx, "analyze:progress", progress)
+			})
 		}
 	}
 
@@ -285,8 +504,13 @@ func scanDirectoryInternal(path string, ctx context.Context) (*scanResult, error
 	}()
 
 	// Perform the scan using the concurrent scanner
-	result, err := scanPathConcurrent(path, &filesScanned, &dirsScanned, &bytesScanned, &currentPath)
+	opts.setRoot(path)
+	result, err := scanPathConcurrent(ctx, path, opts, prev, &filesScanned, &dirsScanned, &bytesScanned, &currentPath)
 	if err != nil {
+		if ctx.Err() != nil {
+			fmt.Printf("[analyze] Scan cancelled for path: %s\n", path)
+			return nil, ctx.Err()
+		}
 		fmt.Printf("[analyze] Scan failed for path %s: %v\n", path, err)
 		return nil, fmt.Errorf("scan failed: %w", err)
 	}
@@ -297,8 +521,17 @@ func scanDirectoryInternal(path string, ctx context.Context) (*scanResult, error
 	// Final progress update
 	progressCallback()
 
+	result.Path = path
+	result.Validated = time.Now()
+	result.Scanned = result.Validated
+	if prev != nil && !prev.Scanned.IsZero() {
+		result.Scanned = prev.Scanned
+	}
+	pruneTree(result.Tree, opts.nodeBudget)
+	result.Hidden, result.Indexed = opts.dirs.hidden(result.Tree, path)
+
 	// Cache the result to disk
-	_ = saveCacheToDisk(path, result)
+	_ = saveCacheToDisk(path, key, result)
 
 	return &result, nil
 }
@@ -0,0 +1,51 @@
+package analyze
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCancelledScanCachesNothing(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(root, "a", "b"), "f", make([]byte, 4096))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	opts := defaultScanOptions()
+	if _, err := scanDirectoryInternal(ctx, root, opts, nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	// The same through the service, whose scans share a context CancelScan cancels
+	s := NewService()
+	s.scanCtx, s.scanCancel = ctx, cancel
+	if _, err := s.ScanDirectory(root); err == nil || !strings.Contains(err.Error(), "scan cancelled") {
+		t.Fatalf("expected a cancelled scan, got %v", err)
+	}
+
+	key := opts.cacheKey(root)
+	if _, found := s.cache.get(key); found {
+		t.Fatalf("cancelled scan left a result in memory")
+	}
+	if _, err := loadCacheEntry(root, key); err == nil {
+		t.Fatalf("cancelled scan left a result on disk")
+	}
+
+	// Later scans get a fresh context
+	s.CancelScan()
+	if _, err := s.ScanDirectory(root); err != nil {
+		t.Fatalf("scan after cancel: %v", err)
+	}
+	if _, err := loadCacheEntry(root, key); err != nil {
+		t.Fatalf("expected a completed scan on disk: %v", err)
+	}
+}
@@ -0,0 +1,472 @@
+package analyze
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+const (
+	snapshotDirName    = "scan_snapshots"
+	snapshotIndexFile  = "index.json"
+	defaultDiffEntries = 50
+	otherDiffKey       = "\x00other"
+)
+
+// Diff change kinds reported in models.ScanDiffEntry.Change
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeGrown   = "grown"
+	ChangeShrunk  = "shrunk"
+	// ChangeFolded marks a child listed on one side only while the other side
+	// has an "other" node it may be folded into, so its change is unknown
+	ChangeFolded = "folded"
+)
+
+// snapshotFile is the on-disk form of a snapshot
+// NOTE: Fields must be exported (capitalized) for gob encoding/decoding
+type snapshotFile struct {
+	Info models.ScanSnapshot
+	Tree *treeNode
+}
+
+var snapshotMu sync.Mutex
+
+// SaveSnapshot stores the scan tree of path made with options. An empty name
+// uses the scan time.
+func (s *Service) SaveSnapshot(path, name string, options models.ScanOptions) (models.ScanSnapshot, error) {
+	node, path, err := s.treeNodeFor(path, options)
+	if err != nil {
+		return models.ScanSnapshot{}, err
+	}
+
+	now := time.Now()
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = now.Format("2006-01-02 15:04")
+	}
+
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	index, err := loadSnapshotIndex()
+	if err != nil {
+		return models.ScanSnapshot{}, err
+	}
+
+	info := models.ScanSnapshot{
+		ID:        uniqueSnapshotID(index, now),
+		Name:      name,
+		Path:      path,
+		CreatedAt: now,
+		TotalSize: node.Size,
+		Files:     node.Files,
+		Options:   options,
+	}
+
+	// Snapshots are taken from the cached tree, which is never modified once pruned
+	root := *node
+	root.Name = filepath.Base(path)
+	if err := writeSnapshotFile(snapshotFile{Info: info, Tree: &root}); err != nil {
+		return models.ScanSnapshot{}, fmt.Errorf("failed to save snapshot: %w", err)
+	}
+
+	index = append(index, info)
+	if err := saveSnapshotIndex(index); err != nil {
+		return models.ScanSnapshot{}, fmt.Errorf("failed to save snapshot index: %w", err)
+	}
+	return info, nil
+}
+
+// ListSnapshots returns saved snapshots, newest first. A non-empty path only
+// returns snapshots of that directory.
+func (s *Service) ListSnapshots(path string) ([]models.ScanSnapshot, error) {
+	snapshotMu.Lock()
+	index, err := loadSnapshotIndex()
+	snapshotMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if path != "" {
+		path = filepath.Clean(path)
+	}
+	result := []models.ScanSnapshot{}
+	for _, info := range index {
+		if path == "" || info.Path == path {
+			result = append(result, info)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// DeleteSnapshot removes a saved snapshot
+func (s *Service) DeleteSnapshot(id string) error {
+	snapshotMu.Lock()
+	defer snapshotMu.Unlock()
+
+	index, err := loadSnapshotIndex()
+	if err != nil {
+		return err
+	}
+
+	kept := index[:0]
+	found := false
+	for _, info := range index {
+		if info.ID == id {
+			found = true
+			continue
+		}
+		kept = append(kept, info)
+	}
+	if !found {
+		return fmt.Errorf("snapshot not found: %s", id)
+	}
+
+	if filePath, err := getSnapshotFilePath(id); err == nil {
+		_ = os.Remove(filePath)
+	}
+	return saveSnapshotIndex(kept)
+}
+
+// DiffSnapshots compares the children of subPath between two snapshots of the
+// same directory taken with the same scan options. An empty toID compares
+// against a fresh scan with the options of fromID; an empty subPath compares
+// the snapshot roots.
+func (s *Service) DiffSnapshots(fromID, toID, subPath string, limit int) (*models.ScanDiff, error) {
+	from, err := readSnapshotFile(fromID)
+	if err != nil {
+		return nil, err
+	}
+
+	var to snapshotFile
+	if toID != "" {
+		if to, err = readSnapshotFile(toID); err != nil {
+			return nil, err
+		}
+		if to.Info.Path != from.Info.Path {
+			return nil, fmt.Errorf("snapshots cover different directories: %s and %s", from.Info.Path, to.Info.Path)
+		}
+		if !sameTreeOptions(from.Info.Options, to.Info.Options) {
+			return nil, fmt.Errorf("snapshots were taken with different scan options")
+		}
+	} else {
+		node, err := s.freshTreeNode(from.Info.Path, from.Info.Options)
+		if err != nil {
+			return nil, err
+		}
+		to = snapshotFile{
+			Info: models.ScanSnapshot{
+				Path:      from.Info.Path,
+				CreatedAt: time.Now(),
+				TotalSize: node.Size,
+				Files:     node.Files,
+				Options:   from.Info.Options,
+			},
+			Tree: node,
+		}
+	}
+
+	if subPath == "" {
+		subPath = from.Info.Path
+	}
+	subPath = filepath.Clean(subPath)
+
+	oldNode := findTreeNode(from.Tree, from.Info.Path, subPath)
+	newNode := findTreeNode(to.Tree, to.Info.Path, subPath)
+	if oldNode == nil && newNode == nil {
+		return nil, fmt.Errorf("path not found in either scan: %s", subPath)
+	}
+
+	diff := diffTrees(oldNode, newNode, subPath, limit)
+	diff.From = from.Info
+	diff.To = to.Info
+	return diff, nil
+}
+
+// freshTreeNode rescans path with options, bypassing both caches
+func (s *Service) freshTreeNode(path string, options models.ScanOptions) (*treeNode, error) {
+	opts, err := newScanOptions(options, s.treeBudget())
+	if err != nil {
+		return nil, err
+	}
+	key := opts.cacheKey(path)
+	s.cache.invalidate(key)
+	removeCacheEntry(key)
+	if _, err := s.ScanDirectoryWithOptions(path, options); err != nil {
+		return nil, err
+	}
+	if result, found := s.cache.get(key); found && result.Tree != nil {
+		return result.Tree, nil
+	}
+	return nil, fmt.Errorf("no scan tree available for %s", path)
+}
+
+// diffTrees compares the children of two nodes for the same path. Either
+// node may be nil when the path exists on one side only.
+func diffTrees(oldNode, newNode *treeNode, path string, limit int) *models.ScanDiff {
+	if limit <= 0 {
+		limit = defaultDiffEntries
+	}
+
+	diff := &models.ScanDiff{Path: path, Entries: []models.ScanDiffEntry{}}
+	if oldNode != nil {
+		diff.OldSize = oldNode.Size
+	}
+	if newNode != nil {
+		diff.NewSize = newNode.Size
+	}
+	diff.Delta = diff.NewSize - diff.OldSize
+
+	oldChildren := childrenByName(oldNode)
+	newChildren := childrenByName(newNode)
+
+	names := make(map[string]bool, len(oldChildren)+len(newChildren))
+	for name := range oldChildren {
+		names[name] = true
+	}
+	for name := range newChildren {
+		names[name] = true
+	}
+
+	for name := range names {
+		oldChild, newChild := oldChildren[name], newChildren[name]
+		entry := models.ScanDiffEntry{Name: name, Path: filepath.Join(path, name)}
+		if name == otherDiffKey {
+			entry.Name = otherNodeName
+		}
+
+		switch {
+		case oldChild == nil && oldChildren[otherDiffKey] != nil:
+			entry.Change = ChangeFolded
+		case newChild == nil && newChildren[otherDiffKey] != nil:
+			entry.Change = ChangeFolded
+		case oldChild == nil:
+			entry.Change = ChangeAdded
+		case newChild == nil:
+			entry.Change = ChangeRemoved
+		}
+		for _, child := range []*treeNode{oldChild, newChild} {
+			if child != nil {
+				entry.IsDir = child.IsDir
+				entry.IsOther = child.Other
+			}
+		}
+		if oldChild != nil {
+			entry.OldSize = oldChild.Size
+		}
+		if newChild != nil {
+			entry.NewSize = newChild.Size
+		}
+		entry.Delta = entry.NewSize - entry.OldSize
+		if entry.Change == ChangeFolded {
+			// The size on the folded side is part of "other" and unknown
+			entry.Delta = 0
+		}
+
+		if entry.Change == "" {
+			switch {
+			case entry.Delta > 0:
+				entry.Change = ChangeGrown
+			case entry.Delta < 0:
+				entry.Change = ChangeShrunk
+			default:
+				continue
+			}
+		}
+
+		if entry.IsOther {
+			entry.Path = ""
+		} else {
+			// Both sides need children to compare; otherwise the entry is the finest detail available
+			entry.Drillable = entry.Delta != 0 && hasChildren(oldChild) && hasChildren(newChild)
+		}
+		diff.Entries = append(diff.Entries, entry)
+	}
+
+	sort.Slice(diff.Entries, func(i, j int) bool {
+		a, b := absDelta(diff.Entries[i].Delta), absDelta(diff.Entries[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return diff.Entries[i].Name < diff.Entries[j].Name
+	})
+	if len(diff.Entries) > limit {
+		diff.Omitted = len(diff.Entries) - limit
+		diff.Entries = diff.Entries[:limit]
+	}
+	return diff
+}
+
+// childrenByName indexes children by name; "other" nodes get a key no real name can have
+func childrenByName(node *treeNode) map[string]*treeNode {
+	children := make(map[string]*treeNode)
+	if node == nil {
+		return children
+	}
+	for i := range node.Children {
+		name := node.Children[i].Name
+		if node.Children[i].Other {
+			name = otherDiffKey
+		}
+		children[name] = &node.Children[i]
+	}
+	return children
+}
+
+func hasChildren(node *treeNode) bool {
+	return node != nil && len(node.Children) > 0
+}
+
+func absDelta(delta int64) int64 {
+	if delta < 0 {
+		return -delta
+	}
+	return delta
+}
+
+// Storage
+
+func getSnapshotDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(home, ".config", "mole", snapshotDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func getSnapshotFilePath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid snapshot id: %q", id)
+	}
+	dir, err := getSnapshotDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, id+".snap"), nil
+}
+
+func uniqueSnapshotID(index []models.ScanSnapshot, now time.Time) string {
+	base := now.Format("20060102-150405")
+	taken := make(map[string]bool, len(index))
+	for _, info := range index {
+		taken[info.ID] = true
+	}
+	id := base
+	for n := 2; taken[id]; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
+
+func loadSnapshotIndex() ([]models.ScanSnapshot, error) {
+	dir, err := getSnapshotDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate snapshots: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, snapshotIndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.ScanSnapshot{}, nil
+		}
+		return nil, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+
+	var index []models.ScanSnapshot
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot index: %w", err)
+	}
+	return index, nil
+}
+
+func saveSnapshotIndex(index []models.ScanSnapshot) error {
+	dir, err := getSnapshotDir()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, snapshotIndexFile), func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+func writeSnapshotFile(snapshot snapshotFile) error {
+	filePath, err := getSnapshotFilePath(snapshot.Info.ID)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filePath, func(f *os.File) error {
+		return gob.NewEncoder(f).Encode(snapshot)
+	})
+}
+
+func readSnapshotFile(id string) (snapshotFile, error) {
+	filePath, err := getSnapshotFilePath(id)
+	if err != nil {
+		return snapshotFile{}, err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshotFile{}, fmt.Errorf("snapshot not found: %s", id)
+		}
+		return snapshotFile{}, fmt.Errorf("failed to open snapshot: %w", err)
+	}
+	defer file.Close()
+
+	var snapshot snapshotFile
+	if err := gob.NewDecoder(file).Decode(&snapshot); err != nil {
+		return snapshotFile{}, fmt.Errorf("failed to read snapshot %s: %w", id, err)
+	}
+	if snapshot.Tree == nil {
+		return snapshotFile{}, fmt.Errorf("snapshot %s has no scan tree", id)
+	}
+	return snapshot, nil
+}
+
+// writeFileAtomic writes through a temporary file so readers never see a partial file
+func writeFileAtomic(path string, write func(f *os.File) error) error {
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	// Flush before the rename, or a crash can leave path empty
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
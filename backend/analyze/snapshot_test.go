@@ -0,0 +1,180 @@
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"mole-wails/backend/models"
+)
+
+func diffEntriesByName(diff *models.ScanDiff) map[string]models.ScanDiffEntry {
+	entries := make(map[string]models.ScanDiffEntry, len(diff.Entries))
+	for _, entry := range diff.Entries {
+		entries[entry.Name] = entry
+	}
+	return entries
+}
+
+func TestDiffTreesClassifiesChanges(t *testing.T) {
+	oldNode := &treeNode{Name: "p", IsDir: true, Size: 100, Children: []treeNode{
+		{Name: "grown", IsDir: true, Size: 40, Children: []treeNode{{Name: "f", Size: 40}}},
+		{Name: "shrunk", Size: 30},
+		{Name: "same", Size: 20},
+		{Name: "removed", Size: 10},
+	}}
+	newNode := &treeNode{Name: "p", IsDir: true, Size: 145, Children: []treeNode{
+		{Name: "grown", IsDir: true, Size: 100, Children: []treeNode{{Name: "f", Size: 100}}},
+		{Name: "shrunk", Size: 5},
+		{Name: "same", Size: 20},
+		{Name: "added", Size: 20},
+	}}
+
+	diff := diffTrees(oldNode, newNode, "/p", 0)
+
+	if diff.OldSize != 100 || diff.NewSize != 145 || diff.Delta != 45 {
+		t.Fatalf("unexpected totals: %+v", diff)
+	}
+	want := []struct {
+		name, change string
+		delta        int64
+		drillable    bool
+	}{
+		{"grown", ChangeGrown, 60, true},
+		{"shrunk", ChangeShrunk, -25, false},
+		{"added", ChangeAdded, 20, false},
+		{"removed", ChangeRemoved, -10, false},
+	}
+	if len(diff.Entries) != len(want) {
+		t.Fatalf("expected %d entries, got %+v", len(want), diff.Entries)
+	}
+	for i, w := range want {
+		entry := diff.Entries[i]
+		if entry.Name != w.name || entry.Change != w.change || entry.Delta != w.delta || entry.Drillable != w.drillable {
+			t.Fatalf("entry %d: got %+v, want %+v", i, entry, w)
+		}
+		if entry.Path != "/p/"+w.name {
+			t.Fatalf("entry %d: got path %q", i, entry.Path)
+		}
+	}
+}
+
+func TestDiffTreesMarksChildrenFoldedIntoOther(t *testing.T) {
+	oldNode := &treeNode{Name: "p", IsDir: true, Size: 100, Children: []treeNode{
+		{Name: "big", Size: 80},
+		{Name: "moved", Size: 15},
+		{Name: otherNodeName, Other: true, Size: 5},
+	}}
+	newNode := &treeNode{Name: "p", IsDir: true, Size: 120, Children: []treeNode{
+		{Name: "big", Size: 80},
+		{Name: "rising", Size: 25},
+		{Name: otherNodeName, Other: true, Size: 15},
+	}}
+
+	entries := diffEntriesByName(diffTrees(oldNode, newNode, "/p", 0))
+
+	for _, name := range []string{"moved", "rising"} {
+		entry, ok := entries[name]
+		if !ok || entry.Change != ChangeFolded || entry.Delta != 0 || entry.Drillable {
+			t.Fatalf("%s: expected an unknown folded change, got %+v (found=%v)", name, entry, ok)
+		}
+	}
+	if entries["moved"].OldSize != 15 || entries["rising"].NewSize != 25 {
+		t.Fatalf("folded entries must keep the known side's size: %+v", entries)
+	}
+
+	other, ok := entries[otherNodeName]
+	if !ok || !other.IsOther || other.Path != "" || other.Change != ChangeGrown || other.Delta != 10 {
+		t.Fatalf("unexpected other entry: %+v (found=%v)", other, ok)
+	}
+}
+
+func TestDiffTreesWithoutOtherSide(t *testing.T) {
+	newNode := &treeNode{Name: "p", IsDir: true, Size: 10, Children: []treeNode{
+		{Name: "a", Size: 10},
+	}}
+
+	diff := diffTrees(nil, newNode, "/p", 0)
+
+	if diff.OldSize != 0 || diff.Delta != 10 || len(diff.Entries) != 1 || diff.Entries[0].Change != ChangeAdded {
+		t.Fatalf("unexpected diff against a missing node: %+v", diff)
+	}
+}
+
+func TestDiffTreesLimit(t *testing.T) {
+	oldNode := &treeNode{Name: "p", IsDir: true}
+	newNode := &treeNode{Name: "p", IsDir: true, Size: 6, Children: []treeNode{
+		{Name: "c", Size: 3},
+		{Name: "b", Size: 2},
+		{Name: "a", Size: 1},
+	}}
+
+	diff := diffTrees(oldNode, newNode, "/p", 2)
+
+	if len(diff.Entries) != 2 || diff.Omitted != 1 {
+		t.Fatalf("expected 2 entries and 1 omitted, got %+v", diff)
+	}
+	if diff.Entries[0].Name != "c" || diff.Entries[1].Name != "b" {
+		t.Fatalf("expected the largest changes first, got %+v", diff.Entries)
+	}
+}
+
+func TestSameTreeOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b models.ScanOptions
+		same bool
+	}{
+		{"defaults", models.ScanOptions{}, models.ScanOptions{}, true},
+		{"limits and budget", models.ScanOptions{}, models.ScanOptions{MaxEntries: 5, MaxWorkers: 2, NodeBudget: 10}, true},
+		{"pattern order", models.ScanOptions{SkipPatterns: []string{"a", "b"}}, models.ScanOptions{SkipPatterns: []string{"b", "a"}}, true},
+		{"skip patterns", models.ScanOptions{}, models.ScanOptions{SkipPatterns: []string{"build"}}, false},
+		{"follow symlinks", models.ScanOptions{}, models.ScanOptions{FollowSymlinks: true}, false},
+		{"stay on filesystem", models.ScanOptions{}, models.ScanOptions{StayOnFilesystem: true}, false},
+		{"bad pattern", models.ScanOptions{SkipPatterns: []string{"["}}, models.ScanOptions{SkipPatterns: []string{"["}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := sameTreeOptions(tt.a, tt.b); got != tt.same {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, tt.same)
+		}
+	}
+}
+
+func TestDiffSnapshotsUsesSnapshotOptions(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, filepath.Join(root, "build"), "out", make([]byte, 8192))
+	writeTestFile(t, root, "src", make([]byte, 4096))
+
+	s := NewService()
+	options := models.ScanOptions{SkipPatterns: []string{"build"}}
+	snapshot, err := s.SaveSnapshot(root, "skip build", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(snapshot.Options, options) {
+		t.Fatalf("expected the snapshot to keep its options, got %+v", snapshot.Options)
+	}
+
+	// The fresh side is scanned with the snapshot's options, so build stays out
+	diff, err := s.DiffSnapshots(snapshot.ID, "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Entries) != 0 || diff.Delta != 0 {
+		t.Fatalf("expected no changes, got %+v", diff)
+	}
+
+	other, err := s.SaveSnapshot(root, "everything", models.ScanOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.DiffSnapshots(snapshot.ID, other.ID, "", 0); err == nil {
+		t.Fatalf("expected snapshots with different options to be rejected")
+	}
+}
@@ -0,0 +1,222 @@
+package analyze
+
+import (
+	"container/heap"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mole-wails/backend/models"
+)
+
+// treeNode is a compact directory tree node built during a scan.
+// Paths are not stored; they are derived from the names on the way down.
+// NOTE: Fields must be exported (capitalized) for gob encoding/decoding in cache
+type treeNode struct {
+	Name      string
+	Size      int64
+	Files     int64
+	IsDir     bool
+	Other     bool       // Aggregate of children that did not fit the node budget
+	Collapsed bool       // Directory whose children were not kept
+	ModTime   int64      // Directory mtime (UnixNano) when scanned, see revalidate.go
+	Types     typeVector // Directory's subtree breakdown, see revalidate.go
+	Mount     string     // Label of a mount point, see mounts.go
+	Children  []treeNode
+
+	nodes int // Nodes in the subtree, counted during the walk; 0 counts as 1
+}
+
+// finalizeTreeNode attaches the filled slots of kids to node, largest first,
+// folding everything past maxTreeChildren into an "other" node
+func finalizeTreeNode(node *treeNode, kids []treeNode) {
+	children := kids[:0]
+	for _, kid := range kids {
+		if kid.Name == "" {
+			continue
+		}
+		node.Files += kid.Files
+		children = append(children, kid)
+	}
+	sortTreeNodes(children)
+
+	if len(children) > maxTreeChildren {
+		children = foldTreeChildren(children, maxTreeChildren-1)
+	}
+	if len(children) == 0 {
+		children = nil
+	}
+	node.Children = children
+
+	node.nodes = 1
+	for i := range children {
+		node.nodes += children[i].subtreeNodes()
+	}
+}
+
+// boundTreeNode prunes a finished directory's subtree to budget nodes as soon
+// as it grows past it, so the walk never holds much more than the final tree.
+// The final pruneTree keeps at most budget nodes of any subtree, picked largest
+// first as here, so pruning early only drops nodes it would drop anyway.
+func boundTreeNode(node *treeNode, budget int) {
+	if budget <= 0 || node.nodes <= budget {
+		return
+	}
+	pruneTree(node, budget)
+	node.nodes = budget
+}
+
+func (n *treeNode) subtreeNodes() int {
+	if n.nodes > 0 {
+		return n.nodes
+	}
+	return 1
+}
+
+// foldTreeChildren keeps the largest keep children and merges the rest,
+// including any existing "other" node, into a single "other" node
+func foldTreeChildren(children []treeNode, keep int) []treeNode {
+	kept := make([]treeNode, 0, keep+1)
+	other := treeNode{Name: otherNodeName, Other: true}
+	folded := false
+
+	for _, child := range children {
+		if !child.Other && len(kept) < keep {
+			kept = append(kept, child)
+			continue
+		}
+		other.Size += child.Size
+		other.Files += child.Files
+		folded = true
+	}
+
+	if folded {
+		kept = append(kept, other)
+		sortTreeNodes(kept)
+	}
+	return kept
+}
+
+// pruneTree bounds the tree to budget nodes. Directories are expanded
+// largest first, so the space goes to the subtrees that matter; the rest are
+// collapsed and the smallest siblings are folded into "other".
+func pruneTree(root *treeNode, budget int) {
+	if root == nil || budget <= 0 {
+		return
+	}
+
+	count := 1
+	pending := &treeNodeHeap{root}
+	for pending.Len() > 0 {
+		node := heap.Pop(pending).(*treeNode)
+		if len(node.Children) == 0 {
+			continue
+		}
+
+		room := budget - count
+		if room <= 0 {
+			node.Children = nil
+			node.Collapsed = true
+			continue
+		}
+		if len(node.Children) > room {
+			node.Children = foldTreeChildren(node.Children, room-1)
+		}
+
+		count += len(node.Children)
+		for i := range node.Children {
+			if len(node.Children[i].Children) > 0 {
+				heap.Push(pending, &node.Children[i])
+			}
+		}
+	}
+}
+
+// findTreeNode walks from root (scanned at rootPath) down to path
+func findTreeNode(root *treeNode, rootPath, path string) *treeNode {
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return nil
+	}
+	if rel == "." {
+		return root
+	}
+
+	node := root
+	for _, name := range strings.Split(rel, string(os.PathSeparator)) {
+		var next *treeNode
+		for i := range node.Children {
+			if !node.Children[i].Other && node.Children[i].Name == name {
+				next = &node.Children[i]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// toModelScanNode converts node and depth levels of its descendants
+func toModelScanNode(node *treeNode, path string, depth int) models.ScanNode {
+	result := models.ScanNode{
+		Name:       node.Name,
+		Path:       path,
+		Size:       node.Size,
+		Files:      node.Files,
+		IsDir:      node.IsDir,
+		IsOther:    node.Other,
+		Collapsed:  node.Collapsed,
+		ChildCount: len(node.Children),
+		Mount:      node.Mount,
+	}
+	if node.Other {
+		result.Path = ""
+	}
+
+	if depth > 0 && len(node.Children) > 0 {
+		result.Children = make([]models.ScanNode, len(node.Children))
+		for i := range node.Children {
+			child := &node.Children[i]
+			result.Children[i] = toModelScanNode(child, filepath.Join(path, child.Name), depth-1)
+		}
+	}
+	return result
+}
+
+// sortTreeNodes orders nodes by size, then name, so output is deterministic
+func sortTreeNodes(nodes []treeNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Size != nodes[j].Size {
+			return nodes[i].Size > nodes[j].Size
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+// treeNodeHeap is a max-heap of directory nodes by size
+type treeNodeHeap []*treeNode
+
+func (h treeNodeHeap) Len() int { return len(h) }
+func (h treeNodeHeap) Less(i, j int) bool {
+	if h[i].Size != h[j].Size {
+		return h[i].Size > h[j].Size
+	}
+	return h[i].Name < h[j].Name
+}
+func (h treeNodeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *treeNodeHeap) Push(x interface{}) {
+	*h = append(*h, x.(*treeNode))
+}
+
+func (h *treeNodeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
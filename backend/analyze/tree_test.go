@@ -0,0 +1,191 @@
+package analyze
+
+import (
+	"fmt"
+	"testing"
+)
+
+func countTreeNodes(node *treeNode) int {
+	count := 1
+	for i := range node.Children {
+		count += countTreeNodes(&node.Children[i])
+	}
+	return count
+}
+
+// wideTree builds a directory with width subdirectories of width files each
+func wideTree(width int) *treeNode {
+	root := &treeNode{Name: "root", IsDir: true}
+	for i := 0; i < width; i++ {
+		dir := treeNode{Name: fmt.Sprintf("d%02d", i), IsDir: true}
+		for j := 0; j < width; j++ {
+			dir.Children = append(dir.Children, treeNode{Name: fmt.Sprintf("f%02d", j), Size: int64(i*width + j + 1), Files: 1})
+			dir.Size += int64(i*width + j + 1)
+			dir.Files++
+		}
+		root.Children = append(root.Children, dir)
+		root.Size += dir.Size
+		root.Files += dir.Files
+	}
+	sortTreeNodes(root.Children)
+	return root
+}
+
+func TestPruneTreeRespectsBudget(t *testing.T) {
+	for _, budget := range []int{1, 2, 5, 11, 12, 50, 200} {
+		tree := wideTree(10)
+		size, files := tree.Size, tree.Files
+
+		pruneTree(tree, budget)
+
+		if got := countTreeNodes(tree); got > budget {
+			t.Fatalf("budget %d: kept %d nodes", budget, got)
+		}
+		if tree.Size != size || tree.Files != files {
+			t.Fatalf("budget %d: root changed to %d bytes, %d files", budget, tree.Size, tree.Files)
+		}
+		var childSize, childFiles int64
+		for _, child := range tree.Children {
+			childSize += child.Size
+			childFiles += child.Files
+		}
+		if len(tree.Children) > 0 && (childSize != size || childFiles != files) {
+			t.Fatalf("budget %d: children hold %d bytes, %d files; want %d, %d", budget, childSize, childFiles, size, files)
+		}
+	}
+}
+
+func TestPruneTreeCollapsesPastBudget(t *testing.T) {
+	tree := wideTree(10)
+	pruneTree(tree, 11)
+
+	// Root and its ten directories fit; none of their files do
+	if len(tree.Children) != 10 {
+		t.Fatalf("expected 10 children, got %d", len(tree.Children))
+	}
+	for _, child := range tree.Children {
+		if !child.Collapsed || child.Children != nil {
+			t.Fatalf("expected %s to be collapsed, got %+v", child.Name, child)
+		}
+	}
+
+	// The largest directory is expanded first
+	tree = wideTree(10)
+	pruneTree(tree, 21)
+	if tree.Children[0].Collapsed || len(tree.Children[0].Children) != 10 || !tree.Children[1].Collapsed {
+		t.Fatalf("expected only the largest directory expanded: %+v", tree.Children[:2])
+	}
+}
+
+func TestFoldTreeChildren(t *testing.T) {
+	tests := []struct {
+		name      string
+		children  []treeNode
+		keep      int
+		wantNames []string
+		other     treeNode
+	}{
+		{
+			name:      "nothing to fold",
+			children:  []treeNode{{Name: "a", Size: 3, Files: 1}, {Name: "b", Size: 2, Files: 1}},
+			keep:      2,
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name:      "sums sizes and files",
+			children:  []treeNode{{Name: "a", Size: 10, Files: 1}, {Name: "b", Size: 3, Files: 2}, {Name: "c", Size: 2, Files: 5}},
+			keep:      1,
+			wantNames: []string{"a", otherNodeName},
+			other:     treeNode{Size: 5, Files: 7},
+		},
+		{
+			name: "merges an existing other",
+			children: []treeNode{
+				{Name: "a", Size: 10, Files: 1},
+				{Name: otherNodeName, Other: true, Size: 8, Files: 4},
+				{Name: "b", Size: 3, Files: 2},
+			},
+			keep:      1,
+			wantNames: []string{otherNodeName, "a"},
+			other:     treeNode{Size: 11, Files: 6},
+		},
+		{
+			name: "other is never kept by name",
+			children: []treeNode{
+				{Name: otherNodeName, Other: true, Size: 50, Files: 9},
+				{Name: "a", Size: 10, Files: 1},
+			},
+			keep:      2,
+			wantNames: []string{otherNodeName, "a"},
+			other:     treeNode{Size: 50, Files: 9},
+		},
+	}
+
+	for _, tt := range tests {
+		got := foldTreeChildren(tt.children, tt.keep)
+		if len(got) != len(tt.wantNames) {
+			t.Fatalf("%s: got %+v", tt.name, got)
+		}
+		for i, name := range tt.wantNames {
+			if got[i].Name != name {
+				t.Fatalf("%s: child %d is %s, want %s", tt.name, i, got[i].Name, name)
+			}
+			if got[i].Other && (got[i].Size != tt.other.Size || got[i].Files != tt.other.Files) {
+				t.Fatalf("%s: other holds %d bytes, %d files; want %d, %d", tt.name, got[i].Size, got[i].Files, tt.other.Size, tt.other.Files)
+			}
+		}
+	}
+}
+
+func TestFinalizeTreeNode(t *testing.T) {
+	kids := make([]treeNode, maxTreeChildren+5)
+	for i := range kids {
+		kids[i] = treeNode{Name: fmt.Sprintf("f%03d", i), Size: int64(i + 1), Files: 1}
+	}
+	kids[3] = treeNode{} // Slot of an entry that was skipped
+
+	node := &treeNode{Name: "d", IsDir: true}
+	finalizeTreeNode(node, kids)
+
+	if len(node.Children) != maxTreeChildren {
+		t.Fatalf("expected %d children, got %d", maxTreeChildren, len(node.Children))
+	}
+	if node.Files != int64(len(kids)-1) {
+		t.Fatalf("expected %d files, got %d", len(kids)-1, node.Files)
+	}
+	var other treeNode
+	for _, child := range node.Children {
+		if child.Other {
+			other = child
+		}
+	}
+	if !other.Other || other.Files != 5 {
+		t.Fatalf("expected the 5 smallest files in other, got %+v", other)
+	}
+	if node.nodes != 1+maxTreeChildren {
+		t.Fatalf("expected %d nodes, got %d", 1+maxTreeChildren, node.nodes)
+	}
+}
+
+func TestBoundTreeNode(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget int
+		want   int
+	}{
+		{"under budget", 500, 111},
+		{"over budget", 30, 30},
+		{"no budget", 0, 111},
+	}
+
+	for _, tt := range tests {
+		tree := wideTree(10)
+		tree.nodes = countTreeNodes(tree)
+
+		boundTreeNode(tree, tt.budget)
+
+		if got := countTreeNodes(tree); got != tt.want || tree.nodes != tt.want {
+			t.Fatalf("%s: got %d nodes (counted %d), want %d", tt.name, got, tree.nodes, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+//go:build linux
+
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	maxWatchedDirs = 8192            // Stays well below the default fs.inotify.max_user_watches
+	watchBatchTime = 2 * time.Second // Changes are collected this long before a refresh
+	watchMask      = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO |
+		syscall.IN_CLOSE_WRITE | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+)
+
+// cacheWatcher reports directories of cached scans that changed, using inotify.
+// Unlike directory mtimes it also sees files written in place.
+type cacheWatcher struct {
+	fd       int
+	file     *os.File
+	onChange func(dirs map[string]bool)
+	ignore   map[string]bool // Our own cache and config directories
+
+	mu      sync.Mutex
+	dirs    map[int32]string // Watch descriptor to directory
+	watched map[string]int32
+	pending map[string]bool
+	timer   *time.Timer
+}
+
+func newCacheWatcher(onChange func(dirs map[string]bool)) (*cacheWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &cacheWatcher{
+		fd:       fd,
+		file:     os.NewFile(uintptr(fd), "inotify"), // Non-blocking, so Close ends a pending Read
+		onChange: onChange,
+		ignore:   make(map[string]bool),
+		dirs:     make(map[int32]string),
+		watched:  make(map[string]int32),
+		pending:  make(map[string]bool),
+	}
+	if cacheDir, err := getCacheDir(); err == nil {
+		w.ignore[cacheDir] = true
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		w.ignore[filepath.Join(home, ".config", "mole")] = true
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// watchTree watches the directories kept in a scan tree, up to maxWatchedDirs in total
+func (w *cacheWatcher) watchTree(root string, tree *treeNode) {
+	if tree == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var visit func(node *treeNode, path string)
+	visit = func(node *treeNode, path string) {
+		if !node.IsDir || node.Other || w.ignore[path] || len(w.watched) >= maxWatchedDirs {
+			return
+		}
+		if _, ok := w.watched[path]; !ok {
+			wd, err := syscall.InotifyAddWatch(w.fd, path, watchMask)
+			if err != nil {
+				return
+			}
+			w.dirs[int32(wd)] = path
+			w.watched[path] = int32(wd)
+		}
+		for i := range node.Children {
+			visit(&node.Children[i], filepath.Join(path, node.Children[i].Name))
+		}
+	}
+	visit(tree, root)
+}
+
+func (w *cacheWatcher) close() {
+	w.mu.Lock()
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	w.mu.Unlock()
+	_ = w.file.Close()
+}
+
+func (w *cacheWatcher) run() {
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+		w.handle(buf[:n])
+	}
+}
+
+// handle marks the directories of a batch of events as pending
+func (w *cacheWatcher) handle(buf []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for offset := 0; offset+syscall.SizeofInotifyEvent <= len(buf); {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+		if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+			// Events were dropped; everything may have changed
+			for dir := range w.watched {
+				w.pending[dir] = true
+			}
+			continue
+		}
+
+		dir, ok := w.dirs[event.Wd]
+		if !ok {
+			continue
+		}
+		if event.Mask&syscall.IN_IGNORED != 0 {
+			// The directory is gone; its parent reports the removal
+			delete(w.dirs, event.Wd)
+			delete(w.watched, dir)
+			continue
+		}
+		w.pending[dir] = true
+	}
+
+	if len(w.pending) > 0 && w.timer == nil {
+		w.timer = time.AfterFunc(watchBatchTime, w.flush)
+	}
+}
+
+func (w *cacheWatcher) flush() {
+	w.mu.Lock()
+	dirs := w.pending
+	w.pending = make(map[string]bool)
+	w.timer = nil
+	w.mu.Unlock()
+
+	if len(dirs) > 0 {
+		w.onChange(dirs)
+	}
+}
@@ -0,0 +1,44 @@
+//go:build linux
+
+package analyze
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheWatcherReportsChangedDirs(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := make(chan map[string]bool, 1)
+	w, err := newCacheWatcher(func(dirs map[string]bool) { changes <- dirs })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.close()
+
+	w.watchTree(root, &treeNode{Name: "root", IsDir: true, Children: []treeNode{
+		{Name: "sub", IsDir: true},
+		{Name: otherNodeName, IsDir: true, Other: true},
+	}})
+	if len(w.watched) != 2 {
+		t.Fatalf("expected root and sub to be watched, got %v", w.watched)
+	}
+
+	writeTestFile(t, sub, "a", []byte("a"))
+
+	select {
+	case dirs := <-changes:
+		if !dirs[sub] || dirs[root] {
+			t.Fatalf("expected only %s to change, got %v", sub, dirs)
+		}
+	case <-time.After(watchBatchTime + 3*time.Second):
+		t.Fatalf("no change reported")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package analyze
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// cacheWatcher is only implemented on Linux, see watcher_linux.go
+type cacheWatcher struct{}
+
+func newCacheWatcher(onChange func(dirs map[string]bool)) (*cacheWatcher, error) {
+	return nil, fmt.Errorf("not supported on %s", runtime.GOOS)
+}
+
+func (w *cacheWatcher) watchTree(root string, tree *treeNode) {}
+
+func (w *cacheWatcher) close() {}
@@ -10,6 +10,7 @@ type CleanCategory struct {
 	Description string `json:"description"`
 	Enabled     bool   `json:"enabled"`
 	EstimatedMB int64  `json:"estimatedMB"`
+	RequiresSudo bool  `json:"requiresSudo"`
 }
 
 type CleanProgress struct {
@@ -33,16 +34,54 @@ type CleanResult struct {
 type Application struct {
 	Name         string    `json:"name"`
 	BundleID     string    `json:"bundleId"`
+	Version      string    `json:"version,omitempty"`
 	Path         string    `json:"path"`
 	Size         int64     `json:"size"`
 	LastModified time.Time `json:"lastModified"`
+	LastUsed     time.Time `json:"lastUsed"`
 	Age          string    `json:"age"`
 	Icon         string    `json:"icon,omitempty"`
+	Source       string    `json:"source,omitempty"`
+	CaskToken    string    `json:"caskToken,omitempty"`
+	CaskTap      string    `json:"caskTap,omitempty"`
+}
+
+// UnusedApp is one entry of the unused apps report. Entries without
+// UsageKnown have no usage data; they are not ranked and carry no score.
+type UnusedApp struct {
+	App          Application `json:"app"`
+	LastUsed     time.Time   `json:"lastUsed"`
+	UseCount     int         `json:"useCount"`
+	UsageKnown   bool        `json:"usageKnown"`
+	MonthsUnused float64     `json:"monthsUnused"`
+	Score        float64     `json:"score"`
+}
+
+type AppScanProgress struct {
+	App     Application `json:"app"`
+	Scanned int         `json:"scanned"`
+	Total   int         `json:"total"`
+}
+
+type DuplicateAppCopy struct {
+	App      Application `json:"app"`
+	LastUsed time.Time   `json:"lastUsed"`
+	Keep     bool        `json:"keep"`
+	Reason   string      `json:"reason"`
+}
+
+type DuplicateAppGroup struct {
+	Name        string             `json:"name"`
+	BundleID    string             `json:"bundleId"`
+	Copies      []DuplicateAppCopy `json:"copies"`
+	Reclaimable int64              `json:"reclaimable"`
 }
 
 type UninstallProgress struct {
 	App           string `json:"app"`
 	Message       string `json:"message"`
+	Step          string `json:"step,omitempty"`
+	Level         string `json:"level,omitempty"`
 	Percent       int    `json:"percent"`
 	FilesRemoved  int    `json:"filesRemoved"`
 	TotalFiles    int    `json:"totalFiles"`
@@ -56,6 +95,45 @@ type UninstallResult struct {
 	Errors       []string `json:"errors"`
 }
 
+type OrphanedItem struct {
+	Path         string    `json:"path"`
+	BundleID     string    `json:"bundleId"`
+	Kind         string    `json:"kind"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+type OrphanAppGroup struct {
+	App      string         `json:"app"`
+	BundleID string         `json:"bundleId"`
+	Size     int64          `json:"size"`
+	Items    []OrphanedItem `json:"items"`
+}
+
+type OrphanVendorGroup struct {
+	Vendor string           `json:"vendor"`
+	Size   int64            `json:"size"`
+	Apps   []OrphanAppGroup `json:"apps"`
+}
+
+// Startup service types
+
+type StartupItem struct {
+	ID            string   `json:"id"`
+	Label         string   `json:"label"`
+	Kind          string   `json:"kind"`
+	Scope         string   `json:"scope"`
+	Path          string   `json:"path"`
+	Program       string   `json:"program"`
+	Arguments     []string `json:"arguments"`
+	RunAtLoad     bool     `json:"runAtLoad"`
+	KeepAlive     bool     `json:"keepAlive"`
+	Enabled       bool     `json:"enabled"`
+	ProgramExists bool     `json:"programExists"`
+	OwnerApp      string   `json:"ownerApp,omitempty"`
+	Editable      bool     `json:"editable"`
+}
+
 // Optimize service types
 
 type OptimizationTask struct {
@@ -64,6 +142,7 @@ type OptimizationTask struct {
 	Description string `json:"description"`
 	Enabled     bool   `json:"enabled"`
 	RequiresSudo bool  `json:"requiresSudo"`
+	DisabledReason string `json:"disabledReason,omitempty"`
 }
 
 type OptimizeProgress struct {
@@ -72,9 +151,60 @@ type OptimizeProgress struct {
 	Percent int    `json:"percent"`
 }
 
+type OptimizeTaskEvent struct {
+	TaskID     string   `json:"taskId"`
+	Name       string   `json:"name"`
+	Status     string   `json:"status"`
+	DurationMs int64    `json:"durationMs"`
+	Output     []string `json:"output"`
+	Error      string   `json:"error,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
 type OptimizeResult struct {
-	TasksCompleted int      `json:"tasksCompleted"`
-	Errors         []string `json:"errors"`
+	TasksCompleted int                 `json:"tasksCompleted"`
+	Tasks          []OptimizeTaskEvent `json:"tasks"`
+	Skipped        []string            `json:"skipped"`
+	Errors         []string            `json:"errors"`
+	Impact         *OptimizeImpact     `json:"impact,omitempty"`
+}
+
+// OptimizeMeasurement is a set of system readings taken before or after a run
+type OptimizeMeasurement struct {
+	TakenAt      time.Time          `json:"takenAt"`
+	MemoryUsed   uint64             `json:"memoryUsed"`
+	SwapUsed     uint64             `json:"swapUsed"`
+	DiskFree     uint64             `json:"diskFree"`
+	Load1        float64            `json:"load1"`
+	DNSResolveMs float64            `json:"dnsResolveMs"`
+	Probes       map[string]float64 `json:"probes"`
+}
+
+// OptimizeImpactDelta compares one reading before and after a run
+type OptimizeImpactDelta struct {
+	Metric   string  `json:"metric"`
+	Label    string  `json:"label"`
+	Unit     string  `json:"unit"`
+	Before   float64 `json:"before"`
+	After    float64 `json:"after"`
+	Delta    float64 `json:"delta"`
+	Improved bool    `json:"improved"`
+}
+
+type OptimizeImpact struct {
+	Before OptimizeMeasurement   `json:"before"`
+	After  OptimizeMeasurement   `json:"after"`
+	Deltas []OptimizeImpactDelta `json:"deltas"`
+}
+
+// OptimizeHistoryEntry records one optimization run for later comparison
+type OptimizeHistoryEntry struct {
+	StartedAt      time.Time       `json:"startedAt"`
+	TaskIDs        []string        `json:"taskIds"`
+	TasksCompleted int             `json:"tasksCompleted"`
+	Skipped        []string        `json:"skipped"`
+	Errors         []string        `json:"errors"`
+	Impact         *OptimizeImpact `json:"impact,omitempty"`
 }
 
 // Analyze service types
@@ -92,14 +222,63 @@ type DirEntry struct {
 	IsDir      bool      `json:"isDir"`
 	LastAccess time.Time `json:"lastAccess"`
 	Percent    float64   `json:"percent"`
+	Mount      string    `json:"mount,omitempty"` // Label when the entry is a mount point
 }
 
 type ScanResult struct {
-	Entries    []DirEntry  `json:"entries"`
-	LargeFiles []FileEntry `json:"largeFiles"`
-	TotalSize  int64       `json:"totalSize"`
-	TotalItems int         `json:"totalItems"`
-	Path       string      `json:"path"`
+	Entries    []DirEntry     `json:"entries"`
+	LargeFiles []FileEntry    `json:"largeFiles"`
+	Types      []FileTypeStat `json:"types"`
+	Mounts     []MountEntry   `json:"mounts"`
+	TotalSize  int64          `json:"totalSize"`
+	TotalItems int            `json:"totalItems"`
+	Path       string         `json:"path"`
+}
+
+// MountEntry is a mount point met during a scan. Kind is "local", "network"
+// or "fuse"; skipped mounts are listed without size. TimedOut counts the
+// directory listings on the mount that were given up on.
+type MountEntry struct {
+	Path     string `json:"path"`
+	Label    string `json:"label"`
+	FSType   string `json:"fsType"`
+	Kind     string `json:"kind"`
+	Size     int64  `json:"size"`
+	Skipped  bool   `json:"skipped"`
+	TimedOut int64  `json:"timedOut"`
+}
+
+// FileTypeStat is the share of one file category in a scan. Count is files,
+// except for app bundles where it is bundles. Bytes of directories sized
+// without being walked (folded ones, and ~/Library in a Home scan, which is
+// sized from the overview cache) are reported under "unscanned", not "other".
+type FileTypeStat struct {
+	Category string      `json:"category"`
+	Label    string      `json:"label"`
+	Size     int64       `json:"size"`
+	Count    int64       `json:"count"`
+	Percent  float64     `json:"percent"`
+	TopFiles []FileEntry `json:"topFiles"`
+}
+
+// ScanOptions tunes a single analyze scan. Zero values use the defaults.
+// FoldPatterns and SkipPatterns are shell globs matched against file and
+// directory names: folded directories are sized without being expanded,
+// skipped ones are left out entirely. Mount points are listed even when
+// StayOnFilesystem or SkipRemoteMounts leaves them out; directory listings on
+// network and FUSE mounts give up after RemoteDirTimeoutMs.
+type ScanOptions struct {
+	MaxEntries         int      `json:"maxEntries"`
+	MaxLargeFiles      int      `json:"maxLargeFiles"`
+	MinLargeFileSize   int64    `json:"minLargeFileSize"`
+	FollowSymlinks     bool     `json:"followSymlinks"`
+	StayOnFilesystem   bool     `json:"stayOnFilesystem"`
+	SkipRemoteMounts   bool     `json:"skipRemoteMounts"`
+	RemoteDirTimeoutMs int      `json:"remoteDirTimeoutMs"`
+	FoldPatterns       []string `json:"foldPatterns"`
+	SkipPatterns       []string `json:"skipPatterns"`
+	MaxWorkers         int      `json:"maxWorkers"`
+	NodeBudget         int      `json:"nodeBudget"`
 }
 
 type ScanProgress struct {
@@ -108,6 +287,149 @@ type ScanProgress struct {
 	TotalSize    int64  `json:"totalSize"`
 }
 
+// ScanNode is one directory tree node. Collapsed nodes were pruned from the
+// scan tree and need their own scan; ChildCount is set even when Children
+// was cut off by the requested depth.
+type ScanNode struct {
+	Name       string     `json:"name"`
+	Path       string     `json:"path"`
+	Size       int64      `json:"size"`
+	Files      int64      `json:"files"`
+	IsDir      bool       `json:"isDir"`
+	IsOther    bool       `json:"isOther"`
+	Collapsed  bool       `json:"collapsed"`
+	ChildCount int        `json:"childCount"`
+	Mount      string     `json:"mount,omitempty"`
+	Children   []ScanNode `json:"children,omitempty"`
+}
+
+// ScanSnapshot describes a saved scan tree
+type ScanSnapshot struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Path      string      `json:"path"`
+	CreatedAt time.Time   `json:"createdAt"`
+	TotalSize int64       `json:"totalSize"`
+	Files     int64       `json:"files"`
+	Options   ScanOptions `json:"options"` // What the scan ran with; diffs need matching options
+}
+
+// ScanDiffEntry is one child whose size changed between two scans.
+// Change is "added", "removed", "grown" or "shrunk", or "folded" when the entry
+// is missing on one side that folded small children into "other" and its
+// change is unknown. Drillable entries have changed children that a diff of Path can show.
+type ScanDiffEntry struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	IsDir     bool   `json:"isDir"`
+	IsOther   bool   `json:"isOther"`
+	Change    string `json:"change"`
+	OldSize   int64  `json:"oldSize"`
+	NewSize   int64  `json:"newSize"`
+	Delta     int64  `json:"delta"`
+	Drillable bool   `json:"drillable"`
+}
+
+// ScanDiff compares the children of Path in two scans, largest change first.
+// A To without an ID is a fresh scan.
+type ScanDiff struct {
+	Path    string          `json:"path"`
+	From    ScanSnapshot    `json:"from"`
+	To      ScanSnapshot    `json:"to"`
+	OldSize int64           `json:"oldSize"`
+	NewSize int64           `json:"newSize"`
+	Delta   int64           `json:"delta"`
+	Entries []ScanDiffEntry `json:"entries"`
+	Omitted int             `json:"omitted"`
+}
+
+// SizeSample is the last size recorded for a location on one day
+type SizeSample struct {
+	Date string `json:"date"` // YYYY-MM-DD, local time
+	Size int64  `json:"size"`
+}
+
+// SizeTrend is the recorded history of one overview location.
+// BytesPerDay is the least-squares slope over the requested window.
+type SizeTrend struct {
+	Name        string       `json:"name"`
+	Path        string       `json:"path"`
+	CurrentSize int64        `json:"currentSize"`
+	Change7d    int64        `json:"change7d"`
+	Change30d   int64        `json:"change30d"`
+	BytesPerDay float64      `json:"bytesPerDay"`
+	Samples     []SizeSample `json:"samples"`
+}
+
+// VolumeSample is the primary volume usage recorded on one day
+type VolumeSample struct {
+	Date  string `json:"date"`
+	Used  uint64 `json:"used"`
+	Total uint64 `json:"total"`
+}
+
+// VolumeForecast projects when the primary volume fills up. DaysUntilFull
+// is -1 and FullAt nil when usage is flat or shrinking, or history is too short.
+// FullAt is also nil when the volume would fill up more than a century from now.
+type VolumeForecast struct {
+	Mount         string         `json:"mount"`
+	Total         uint64         `json:"total"`
+	Used          uint64         `json:"used"`
+	Free          uint64         `json:"free"`
+	BytesPerDay   float64        `json:"bytesPerDay"`
+	DaysUntilFull float64        `json:"daysUntilFull"`
+	FullAt        *time.Time     `json:"fullAt,omitempty"`
+	Samples       []VolumeSample `json:"samples"`
+}
+
+// ScanCacheEntry is one scan kept in the analyze disk cache
+type ScanCacheEntry struct {
+	Path     string    `json:"path"`
+	Bytes    int64     `json:"bytes"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"lastUsed"`
+}
+
+// ScanCacheInfo describes the analyze disk cache. It is kept under Budget
+// bytes by evicting the least recently used entries.
+type ScanCacheInfo struct {
+	Dir     string           `json:"dir"`
+	Bytes   int64            `json:"bytes"`
+	Budget  int64            `json:"budget"`
+	Entries []ScanCacheEntry `json:"entries"`
+}
+
+// TreemapRect is one treemap rectangle in viewport pixels
+type TreemapRect struct {
+	Name      string  `json:"name"`
+	Path      string  `json:"path"`
+	Size      int64   `json:"size"`
+	Depth     int     `json:"depth"`
+	X         float64 `json:"x"`
+	Y         float64 `json:"y"`
+	Width     float64 `json:"width"`
+	Height    float64 `json:"height"`
+	IsDir     bool    `json:"isDir"`
+	IsOther   bool    `json:"isOther"`
+	Collapsed bool    `json:"collapsed"`
+}
+
+// SunburstArc is one ring segment; angles are radians clockwise from 12 o'clock
+// and radii are pixels from the centre
+type SunburstArc struct {
+	Name        string  `json:"name"`
+	Path        string  `json:"path"`
+	Size        int64   `json:"size"`
+	Depth       int     `json:"depth"`
+	StartAngle  float64 `json:"startAngle"`
+	EndAngle    float64 `json:"endAngle"`
+	InnerRadius float64 `json:"innerRadius"`
+	OuterRadius float64 `json:"outerRadius"`
+	IsDir       bool    `json:"isDir"`
+	IsOther     bool    `json:"isOther"`
+	Collapsed   bool    `json:"collapsed"`
+}
+
 // Status service types
 
 type MetricsSnapshot struct {
@@ -219,6 +541,70 @@ type TouchIDStatus struct {
 	ConfigPath    string `json:"configPath"`
 }
 
+// Preference service types
+
+type BrokenPreference struct {
+	Path      string    `json:"path"`
+	Domain    string    `json:"domain"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"modTime"`
+	Reason    string    `json:"reason"` // empty, corrupt
+	Error     string    `json:"error,omitempty"`
+	Container string    `json:"container,omitempty"`
+	OwnerApp  string    `json:"ownerApp,omitempty"`
+	OwnerPath string    `json:"ownerPath,omitempty"`
+}
+
+type PreferenceRepair struct {
+	ID         string     `json:"id"`
+	Path       string     `json:"path"`
+	BackupPath string     `json:"backupPath"`
+	Reason     string     `json:"reason"`
+	OwnerApp   string     `json:"ownerApp,omitempty"`
+	RemovedAt  time.Time  `json:"removedAt"`
+	RestoredAt *time.Time `json:"restoredAt,omitempty"`
+}
+
+// Check service types
+
+type CheckFix struct {
+	Description  string `json:"description"`
+	RequiresSudo bool   `json:"requiresSudo"`
+}
+
+type CheckResult struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Category   string    `json:"category"`
+	Status     string    `json:"status"`   // pass, warn, fail, skipped, error
+	Severity   string    `json:"severity"` // info, low, medium, high, critical
+	Message    string    `json:"message"`
+	Evidence   []string  `json:"evidence"`
+	Fix        *CheckFix `json:"fix,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+}
+
+type CheckProgress struct {
+	Completed int         `json:"completed"`
+	Total     int         `json:"total"`
+	Percent   int         `json:"percent"`
+	Result    CheckResult `json:"result"`
+}
+
+// CheckFixProgress is one step or output line of a running fix
+type CheckFixProgress struct {
+	CheckID string `json:"checkId"`
+	Message string `json:"message"`
+}
+
+type CheckFixResult struct {
+	CheckID string      `json:"checkId"`
+	Applied bool        `json:"applied"`
+	Output  []string    `json:"output"`
+	Error   string      `json:"error,omitempty"`
+	Result  CheckResult `json:"result"`
+}
+
 // Common types
 
 type ErrorResponse struct {
@@ -0,0 +1,27 @@
+//go:build darwin
+
+package privileged
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const askpassScript = `#!/bin/sh
+/usr/bin/osascript \
+  -e 'display dialog "Mole needs administrator privileges to run system maintenance." default answer "" with hidden answer with title "Mole" with icon caution' \
+  -e 'text returned of result'
+`
+
+// writeAskpass returns the program sudo -A uses to ask for the password
+func writeAskpass(dir string) (string, error) {
+	if existing := os.Getenv("SUDO_ASKPASS"); existing != "" {
+		return existing, nil
+	}
+
+	path := filepath.Join(dir, "askpass.sh")
+	if err := os.WriteFile(path, []byte(askpassScript), 0700); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package privileged
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const askpassPrompt = "Mole needs administrator privileges to run system maintenance."
+
+// writeAskpass returns the program sudo -A uses to ask for the password
+func writeAskpass(dir string) (string, error) {
+	if existing := os.Getenv("SUDO_ASKPASS"); existing != "" {
+		return existing, nil
+	}
+
+	var script string
+	switch {
+	case lookPath("zenity"):
+		script = "#!/bin/sh\nexec zenity --password --title=Mole --text=\"" + askpassPrompt + "\"\n"
+	case lookPath("kdialog"):
+		script = "#!/bin/sh\nexec kdialog --title Mole --password \"" + askpassPrompt + "\"\n"
+	case lookPath("ssh-askpass"):
+		script = "#!/bin/sh\nexec ssh-askpass \"" + askpassPrompt + "\"\n"
+	default:
+		return "", errors.New("no graphical password prompt found (install zenity, kdialog or ssh-askpass)")
+	}
+
+	path := filepath.Join(dir, "askpass.sh")
+	if err := os.WriteFile(path, []byte(script), 0700); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func lookPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,240 @@
+package privileged
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// authTimeout bounds how long the user has to answer the password prompt
+	authTimeout    = 2 * time.Minute
+	requestTimeout = 10 * time.Minute
+)
+
+// ErrCancelled is returned when the user dismisses the password prompt
+var ErrCancelled = errors.New("administrator authentication was cancelled")
+
+// Broker owns the root helper for the current session. The helper is started
+// on first use, so the user authenticates once and later requests reuse it.
+type Broker struct {
+	mu     sync.Mutex
+	dir    string
+	cmd    *exec.Cmd
+	conn   net.Conn
+	enc    *json.Encoder
+	dec    *json.Decoder
+	nextID uint64
+}
+
+func NewBroker() *Broker {
+	return &Broker{}
+}
+
+// Running reports whether an authenticated helper is connected
+func (b *Broker) Running() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.conn != nil
+}
+
+// Start launches the helper if it is not running yet, prompting for a password
+func (b *Broker) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.start(ctx)
+}
+
+// Run sends one allow-listed command to the helper, starting it if needed.
+// Transport failures are returned as errors; command failures are reported
+// in the Result.
+func (b *Broker) Run(ctx context.Context, req Request) (Result, error) {
+	if !Allowed(req.Command) {
+		return Result{}, fmt.Errorf("command not allowed: %s", req.Command)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := b.start(ctx); err != nil {
+		return Result{}, err
+	}
+
+	b.nextID++
+	req.ID = b.nextID
+
+	deadline := time.Now().Add(requestTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = b.conn.SetDeadline(deadline)
+
+	var res Result
+	if err := b.enc.Encode(req); err != nil {
+		b.stop()
+		return Result{}, fmt.Errorf("failed to send request to helper: %w", err)
+	}
+	if err := b.dec.Decode(&res); err != nil {
+		b.stop()
+		return Result{}, fmt.Errorf("failed to read helper result: %w", err)
+	}
+	if res.ID != req.ID {
+		b.stop()
+		return Result{}, fmt.Errorf("helper answered request %d, expected %d", res.ID, req.ID)
+	}
+
+	return res, nil
+}
+
+// Close stops the helper and removes the session socket
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stop()
+	return nil
+}
+
+// start must be called with b.mu held
+func (b *Broker) start(ctx context.Context) error {
+	if b.conn != nil {
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate helper executable: %w", err)
+	}
+
+	// The socket lives in a 0700 directory so only this user (and root) can reach it
+	dir, err := os.MkdirTemp("", "mole-helper-")
+	if err != nil {
+		return fmt.Errorf("failed to create helper directory: %w", err)
+	}
+	b.dir = dir
+
+	socketPath := filepath.Join(dir, "helper.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		b.stop()
+		return fmt.Errorf("failed to listen on helper socket: %w", err)
+	}
+	defer listener.Close()
+
+	askpass, err := writeAskpass(dir)
+	if err != nil {
+		b.stop()
+		return fmt.Errorf("failed to prepare password prompt: %w", err)
+	}
+
+	token, err := newToken()
+	if err != nil {
+		b.stop()
+		return err
+	}
+
+	cmd := exec.Command("sudo", "-A", "--", exe, HelperFlag, socketPath)
+	cmd.Env = append(os.Environ(), "SUDO_ASKPASS="+askpass)
+	cmd.Stdin = strings.NewReader(token + "\n")
+	if err := cmd.Start(); err != nil {
+		b.stop()
+		return fmt.Errorf("failed to start helper: %w", err)
+	}
+	b.cmd = cmd
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	type accepted struct {
+		conn net.Conn
+		err  error
+	}
+	acceptCh := make(chan accepted, 1)
+	go func() {
+		conn, err := listener.Accept()
+		acceptCh <- accepted{conn, err}
+	}()
+
+	var conn net.Conn
+	select {
+	case a := <-acceptCh:
+		if a.err != nil {
+			b.stop()
+			return fmt.Errorf("failed to accept helper connection: %w", a.err)
+		}
+		conn = a.conn
+	case err := <-exited:
+		b.cmd = nil
+		b.stop()
+		if err != nil {
+			return ErrCancelled
+		}
+		return errors.New("helper exited before connecting")
+	case <-time.After(authTimeout):
+		b.stop()
+		return ErrCancelled
+	case <-ctx.Done():
+		b.stop()
+		return ctx.Err()
+	}
+
+	_ = os.Remove(askpass)
+
+	b.conn = conn
+	b.enc = json.NewEncoder(conn)
+	b.dec = json.NewDecoder(conn)
+
+	_ = conn.SetDeadline(time.Now().Add(authTimeout))
+	var greeting hello
+	if err := b.dec.Decode(&greeting); err != nil {
+		b.stop()
+		return fmt.Errorf("failed to read helper hello: %w", err)
+	}
+	if greeting.Token != token {
+		b.stop()
+		return errors.New("helper presented an invalid session token")
+	}
+	if greeting.Error != "" {
+		b.stop()
+		return fmt.Errorf("helper failed to start: %s", greeting.Error)
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	return nil
+}
+
+// stop must be called with b.mu held
+func (b *Broker) stop() {
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+	if b.cmd != nil && b.cmd.Process != nil {
+		// Closing the connection ends the helper; kill only covers a stuck prompt
+		_ = b.cmd.Process.Kill()
+	}
+	b.cmd = nil
+	b.enc = nil
+	b.dec = nil
+	if b.dir != "" {
+		os.RemoveAll(b.dir)
+		b.dir = ""
+	}
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
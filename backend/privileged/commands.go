@@ -0,0 +1,275 @@
+package privileged
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"howett.net/plist"
+)
+
+const stepTimeout = 2 * time.Minute
+
+const dynamicPagerPlist = "/System/Library/LaunchDaemons/com.apple.dynamic_pager.plist"
+
+// launchDaemonDir holds the daemons CmdEnableDaemon and CmdDisableDaemon may
+// change; a variable so tests can point it elsewhere
+var launchDaemonDir = "/Library/LaunchDaemons"
+
+// handler executes one allow-listed command and fills in res
+type handler func(ctx context.Context, req Request, res *Result) error
+
+// handlers is the allow-list. Every entry runs fixed binaries with fixed arguments
+// or removes files below fixed roots; requests only choose between them.
+var handlers = map[string]handler{
+	CmdPing: func(ctx context.Context, req Request, res *Result) error {
+		res.Output = append(res.Output, fmt.Sprintf("pong from uid %d", os.Geteuid()))
+		return nil
+	},
+	CmdFlushDNS: steps(
+		[]string{"/usr/bin/dscacheutil", "-flushcache"},
+		[]string{"/usr/bin/killall", "-HUP", "mDNSResponder"},
+	),
+	CmdFlushARP:   steps([]string{"/usr/sbin/arp", "-d", "-a"}),
+	CmdRotateLogs: steps([]string{"/usr/sbin/newsyslog"}),
+	CmdRestartPager: steps(
+		[]string{"/bin/launchctl", "unload", dynamicPagerPlist},
+		[]string{"/bin/launchctl", "load", dynamicPagerPlist},
+	),
+	CmdReindexSpotlight:   steps([]string{"/usr/bin/mdutil", "-E", "/"}),
+	CmdCleanDiagnostics:   removeOldFiles("/Library/Logs/DiagnosticReports"),
+	CmdCleanSystemTemp:    removeOldFiles("/private/tmp", "/private/var/tmp"),
+	CmdCleanSystemCaches:  removeOldFiles("/Library/Caches"),
+	CmdEnableFirewall:     steps([]string{"/usr/libexec/ApplicationFirewall/socketfilterfw", "--setglobalstate", "on"}),
+	CmdEnableGatekeeper:   steps([]string{"/usr/sbin/spctl", "--master-enable"}),
+	CmdEnableTouchIDSudo:  enableTouchIDSudo,
+	CmdDisableTouchIDSudo: disableTouchIDSudo,
+	CmdInstallRosetta:     steps([]string{"/usr/sbin/softwareupdate", "--install-rosetta", "--agree-to-license"}),
+	CmdEnableDaemon:       setLaunchDaemonEnabled(true),
+	CmdDisableDaemon:      setLaunchDaemonEnabled(false),
+}
+
+// Execute runs a request against the allow-list and reports the outcome
+func Execute(ctx context.Context, req Request) Result {
+	res := Result{ID: req.ID, Command: req.Command, Output: []string{}}
+	start := time.Now()
+
+	run, ok := handlers[req.Command]
+	if !ok {
+		res.Error = fmt.Sprintf("command not allowed: %s", req.Command)
+		res.ExitCode = -1
+		return res
+	}
+
+	if err := run(ctx, req, &res); err != nil {
+		res.Error = err.Error()
+		if res.ExitCode == 0 {
+			res.ExitCode = -1
+		}
+	} else {
+		res.OK = true
+	}
+
+	res.DurationMs = time.Since(start).Milliseconds()
+	return res
+}
+
+// steps runs fixed command lines in order, stopping at the first failure
+func steps(commands ...[]string) handler {
+	return func(ctx context.Context, req Request, res *Result) error {
+		for _, argv := range commands {
+			if req.DryRun {
+				res.Output = append(res.Output, "would run: "+strings.Join(argv, " "))
+				continue
+			}
+			if err := runStep(ctx, argv, res); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func runStep(ctx context.Context, argv []string, res *Result) error {
+	ctx, cancel := context.WithTimeout(ctx, stepTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, argv[0], argv[1:]...).CombinedOutput()
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			res.Output = append(res.Output, line)
+		}
+	}
+
+	name := filepath.Base(argv[0])
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			res.ExitCode = exitErr.ExitCode()
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s timed out after %v", name, stepTimeout)
+		}
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+	return nil
+}
+
+// setLaunchDaemonEnabled enables or disables a third-party launch daemon.
+// The plist is re-read here, so the caller only picks which daemon.
+func setLaunchDaemonEnabled(enabled bool) handler {
+	return func(ctx context.Context, req Request, res *Result) error {
+		label, err := launchDaemonLabel(req.Path)
+		if err != nil {
+			return err
+		}
+
+		var argv [][]string
+		if enabled {
+			argv = [][]string{
+				{"/bin/launchctl", "enable", "system/" + label},
+				{"/bin/launchctl", "bootstrap", "system", req.Path},
+			}
+		} else {
+			argv = [][]string{
+				{"/bin/launchctl", "bootout", "system", req.Path},
+				{"/bin/launchctl", "disable", "system/" + label},
+			}
+		}
+
+		for i, step := range argv {
+			if req.DryRun {
+				res.Output = append(res.Output, "would run: "+strings.Join(step, " "))
+				continue
+			}
+			// Loading an already loaded job, or unloading a stopped one, fails harmlessly
+			optional := (enabled && i == 1) || (!enabled && i == 0)
+			if err := runStep(ctx, step, res); err != nil && !optional {
+				return err
+			}
+		}
+		res.ExitCode = 0
+		return nil
+	}
+}
+
+// launchDaemonLabel validates path as a root-owned, non-Apple daemon plist
+// directly in /Library/LaunchDaemons and returns its label
+func launchDaemonLabel(path string) (string, error) {
+	if filepath.Dir(path) != launchDaemonDir || filepath.Ext(path) != ".plist" || filepath.Clean(path) != path {
+		return "", fmt.Errorf("not a launch daemon: %s", path)
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return "", err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !info.Mode().IsRegular() || !ok || stat.Uid != 0 {
+		return "", fmt.Errorf("launch daemon plist must be a regular file owned by root: %s", path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var job struct {
+		Label string `plist:"Label"`
+	}
+	if _, err := plist.Unmarshal(data, &job); err != nil || job.Label == "" {
+		return "", fmt.Errorf("invalid launch daemon plist: %s", path)
+	}
+	if strings.HasPrefix(job.Label, "com.apple.") {
+		return "", fmt.Errorf("refusing to modify Apple launch daemon: %s", job.Label)
+	}
+	return job.Label, nil
+}
+
+// removeOldFiles deletes root-owned regular files older than the request's age
+// limit. Files of other users are left to them; the roots themselves are kept.
+func removeOldFiles(roots ...string) handler {
+	return func(ctx context.Context, req Request, res *Result) error {
+		maxAge := req.MaxAgeDays
+		if maxAge <= 0 {
+			maxAge = DefaultMaxAgeDays
+		}
+		cutoff := time.Now().AddDate(0, 0, -maxAge)
+
+		for _, root := range roots {
+			fd, err := unix.Open(root, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+			if err != nil {
+				if err == unix.ENOENT {
+					continue
+				}
+				return fmt.Errorf("failed to open %s: %w", root, err)
+			}
+			err = removeOldFilesAt(ctx, fd, root, cutoff, req.DryRun, res)
+			if err != nil {
+				return fmt.Errorf("failed to clean %s: %w", root, err)
+			}
+		}
+
+		res.Output = append(res.Output, fmt.Sprintf("Removed %d files older than %d days", res.FilesRemoved, maxAge))
+		return nil
+	}
+}
+
+// removeOldFilesAt removes root-owned regular files older than cutoff below the
+// directory open at fd, and closes fd. Entries are examined and unlinked relative
+// to descriptors opened with O_NOFOLLOW, and only root-owned directories are
+// entered, so no other user can swap a path component for a symlink mid-walk.
+func removeOldFilesAt(ctx context.Context, fd int, path string, cutoff time.Time, dryRun bool, res *Result) error {
+	dir := os.NewFile(uintptr(fd), path)
+	defer dir.Close()
+
+	names, err := dir.Readdirnames(-1)
+	if err != nil {
+		return nil
+	}
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var st unix.Stat_t
+		if err := unix.Fstatat(fd, name, &st, unix.AT_SYMLINK_NOFOLLOW); err != nil || st.Uid != 0 {
+			continue
+		}
+
+		switch uint32(st.Mode) & unix.S_IFMT {
+		case unix.S_IFDIR:
+			child, err := unix.Openat(fd, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+			if err != nil {
+				continue
+			}
+			var opened unix.Stat_t
+			if unix.Fstat(child, &opened) != nil || opened.Dev != st.Dev || opened.Ino != st.Ino || opened.Uid != 0 {
+				unix.Close(child)
+				continue
+			}
+			if err := removeOldFilesAt(ctx, child, filepath.Join(path, name), cutoff, dryRun, res); err != nil {
+				return err
+			}
+		case unix.S_IFREG:
+			if time.Unix(st.Mtim.Unix()).After(cutoff) {
+				continue
+			}
+			if !dryRun {
+				if err := unix.Unlinkat(fd, name, 0); err != nil {
+					continue
+				}
+			}
+			res.FilesRemoved++
+			res.BytesFreed += st.Size
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package privileged
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const nobodyUID = 65534
+
+func requireRoot(t *testing.T) {
+	t.Helper()
+	if os.Geteuid() != 0 {
+		t.Skip("needs root to create files owned by other users")
+	}
+}
+
+// writeAged writes a file with its mtime days in the past
+func writeAged(t *testing.T, path string, size, days int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().AddDate(0, 0, -days)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func chown(t *testing.T, path string, uid int) {
+	t.Helper()
+	if err := os.Lchown(path, uid, uid); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemoveOldFiles(t *testing.T) {
+	requireRoot(t)
+
+	root := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "outside")
+	writeAged(t, outside, 10, 90)
+
+	writeAged(t, filepath.Join(root, "old"), 100, 60)
+	writeAged(t, filepath.Join(root, "new"), 100, 1)
+	writeAged(t, filepath.Join(root, "sub", "old"), 200, 60)
+	writeAged(t, filepath.Join(root, "user-file"), 100, 60)
+	chown(t, filepath.Join(root, "user-file"), nobodyUID)
+	writeAged(t, filepath.Join(root, "user-dir", "old"), 100, 60)
+	chown(t, filepath.Join(root, "user-dir"), nobodyUID)
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Dir(outside), filepath.Join(root, "dir-link")); err != nil {
+		t.Fatal(err)
+	}
+
+	removed := []string{"old", "sub/old"}
+	kept := []string{"new", "sub", "user-file", "user-dir/old", "link", "dir-link"}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{"dry run", true},
+		{"real run", false},
+	}
+	for _, tt := range tests {
+		var res Result
+		err := removeOldFiles(root)(context.Background(), Request{MaxAgeDays: 30, DryRun: tt.dryRun}, &res)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if res.FilesRemoved != 2 || res.BytesFreed != 300 {
+			t.Fatalf("%s: counted %d files, %d bytes; want 2, 300", tt.name, res.FilesRemoved, res.BytesFreed)
+		}
+
+		for _, name := range removed {
+			_, err := os.Lstat(filepath.Join(root, name))
+			if exists := err == nil; exists != tt.dryRun {
+				t.Fatalf("%s: %s exists=%v", tt.name, name, exists)
+			}
+		}
+		for _, name := range append(kept, "../"+filepath.Base(root)) {
+			if _, err := os.Lstat(filepath.Join(root, name)); err != nil {
+				t.Fatalf("%s: expected %s to be kept: %v", tt.name, name, err)
+			}
+		}
+		if _, err := os.Stat(outside); err != nil {
+			t.Fatalf("%s: a symlink target was removed: %v", tt.name, err)
+		}
+	}
+}
+
+func TestRemoveOldFilesSkipsMissingAndSymlinkedRoots(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	writeAged(t, filepath.Join(target, "old"), 10, 60)
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	var res Result
+	if err := removeOldFiles(filepath.Join(dir, "missing"))(context.Background(), Request{}, &res); err != nil {
+		t.Fatalf("missing root: %v", err)
+	}
+	if err := removeOldFiles(link)(context.Background(), Request{}, &res); err == nil {
+		t.Fatalf("expected a symlinked root to be refused")
+	}
+	if _, err := os.Stat(filepath.Join(target, "old")); err != nil {
+		t.Fatalf("file behind a symlinked root was removed: %v", err)
+	}
+}
+
+func TestLaunchDaemonLabel(t *testing.T) {
+	requireRoot(t)
+
+	dir := t.TempDir()
+	saved := launchDaemonDir
+	launchDaemonDir = dir
+	t.Cleanup(func() { launchDaemonDir = saved })
+
+	writePlist := func(name, label string) string {
+		path := filepath.Join(dir, name)
+		content := `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0"><dict><key>Label</key><string>` + label + `</string></dict></plist>`
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	valid := writePlist("com.vendor.agent.plist", "com.vendor.agent")
+	apple := writePlist("com.apple.thing.plist", "com.apple.thing")
+	userOwned := writePlist("com.vendor.user.plist", "com.vendor.user")
+	chown(t, userOwned, nobodyUID)
+	noLabel := writePlist("com.vendor.empty.plist", "")
+	link := filepath.Join(dir, "com.vendor.link.plist")
+	if err := os.Symlink(valid, link); err != nil {
+		t.Fatal(err)
+	}
+	elsewhere := filepath.Join(t.TempDir(), "com.vendor.agent.plist")
+	if err := os.WriteFile(elsewhere, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name, path, label string
+	}{
+		{"valid", valid, "com.vendor.agent"},
+		{"dot dot", dir + "/sub/../com.vendor.agent.plist", ""},
+		{"escaping dot dot", dir + "/../" + filepath.Base(dir) + "/com.vendor.agent.plist", ""},
+		{"wrong directory", elsewhere, ""},
+		{"not a plist", filepath.Join(dir, "com.vendor.agent.txt"), ""},
+		{"symlink", link, ""},
+		{"apple label", apple, ""},
+		{"not owned by root", userOwned, ""},
+		{"no label", noLabel, ""},
+		{"missing", filepath.Join(dir, "com.vendor.missing.plist"), ""},
+	}
+
+	for _, tt := range tests {
+		label, err := launchDaemonLabel(tt.path)
+		if tt.label == "" {
+			if err == nil {
+				t.Fatalf("%s: expected %s to be refused, got label %q", tt.name, tt.path, label)
+			}
+			continue
+		}
+		if err != nil || label != tt.label {
+			t.Fatalf("%s: got %q, %v; want %q", tt.name, label, err, tt.label)
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	res := Execute(context.Background(), Request{ID: 1, Command: "rm_rf_slash"})
+	if res.OK || res.ExitCode != -1 || !strings.Contains(res.Error, "not allowed") || res.ID != 1 {
+		t.Fatalf("expected an unknown command to be refused, got %+v", res)
+	}
+
+	res = Execute(context.Background(), Request{Command: CmdPing})
+	if !res.OK || len(res.Output) != 1 || !strings.HasPrefix(res.Output[0], "pong") {
+		t.Fatalf("unexpected ping result: %+v", res)
+	}
+
+	res = Execute(context.Background(), Request{Command: CmdFlushDNS, DryRun: true})
+	if !res.OK || len(res.Output) != 2 || !strings.HasPrefix(res.Output[0], "would run: /usr/bin/dscacheutil") {
+		t.Fatalf("expected a dry run to only list its steps, got %+v", res)
+	}
+}
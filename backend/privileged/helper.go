@@ -0,0 +1,61 @@
+package privileged
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// Serve runs the root side of the broker. It reads the session token from
+// tokenSource, connects back to the app's socket and answers requests until
+// the app closes the connection.
+func Serve(socketPath string, tokenSource io.Reader) error {
+	token, err := bufio.NewReader(tokenSource).ReadString('\n')
+	if err != nil && token == "" {
+		return fmt.Errorf("failed to read session token: %w", err)
+	}
+	token = strings.TrimSpace(token)
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", socketPath, err)
+	}
+	defer conn.Close()
+
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+
+	greeting := hello{Token: token, PID: os.Getpid()}
+	if os.Geteuid() != 0 {
+		greeting.Error = "helper is not running as root"
+	}
+	if err := enc.Encode(greeting); err != nil {
+		return fmt.Errorf("failed to send hello: %w", err)
+	}
+	if greeting.Error != "" {
+		return errors.New(greeting.Error)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for {
+		var req Request
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+
+		if err := enc.Encode(Execute(ctx, req)); err != nil {
+			return fmt.Errorf("failed to send result: %w", err)
+		}
+	}
+}
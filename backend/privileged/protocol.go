@@ -0,0 +1,65 @@
+package privileged
+
+// HelperFlag is the command line flag that starts the binary as the root helper
+const HelperFlag = "--privileged-helper"
+
+// Commands accepted by the helper. Anything else is rejected before execution.
+const (
+	CmdPing               = "ping"
+	CmdFlushDNS           = "flush_dns"
+	CmdFlushARP           = "flush_arp"
+	CmdRotateLogs         = "rotate_logs"
+	CmdCleanDiagnostics   = "clean_diagnostic_reports"
+	CmdRestartPager       = "restart_dynamic_pager"
+	CmdReindexSpotlight   = "reindex_spotlight"
+	CmdCleanSystemTemp    = "clean_system_temp"
+	CmdCleanSystemCaches  = "clean_system_caches"
+	CmdEnableFirewall     = "enable_firewall"
+	CmdEnableGatekeeper   = "enable_gatekeeper"
+	CmdEnableTouchIDSudo  = "enable_touchid_sudo"
+	CmdDisableTouchIDSudo = "disable_touchid_sudo"
+	CmdInstallRosetta     = "install_rosetta"
+	CmdEnableDaemon       = "enable_launch_daemon"
+	CmdDisableDaemon      = "disable_launch_daemon"
+)
+
+// DefaultMaxAgeDays keeps recently written temp and cache files in place
+const DefaultMaxAgeDays = 3
+
+// hello is the first message the helper sends after connecting
+type hello struct {
+	Token string `json:"token"`
+	PID   int    `json:"pid"`
+	Error string `json:"error,omitempty"`
+}
+
+// Request is a single typed command sent to the helper
+type Request struct {
+	ID      uint64 `json:"id"`
+	Command string `json:"command"`
+	// MaxAgeDays limits cleanup commands to files older than this; 0 uses the default
+	MaxAgeDays int  `json:"maxAgeDays,omitempty"`
+	DryRun     bool `json:"dryRun,omitempty"`
+	// Path is the plist of the launch daemon commands; it must lie directly in
+	// /Library/LaunchDaemons
+	Path string `json:"path,omitempty"`
+}
+
+// Result is the structured outcome of a Request
+type Result struct {
+	ID           uint64   `json:"id"`
+	Command      string   `json:"command"`
+	OK           bool     `json:"ok"`
+	Output       []string `json:"output"`
+	Error        string   `json:"error,omitempty"`
+	ExitCode     int      `json:"exitCode"`
+	FilesRemoved int      `json:"filesRemoved"`
+	BytesFreed   int64    `json:"bytesFreed"`
+	DurationMs   int64    `json:"durationMs"`
+}
+
+// Allowed reports whether command is on the helper allow-list
+func Allowed(command string) bool {
+	_, ok := handlers[command]
+	return ok
+}
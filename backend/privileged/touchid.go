@@ -0,0 +1,234 @@
+package privileged
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Touch ID for sudo
+//
+// Since macOS 14 /etc/pam.d/sudo includes /etc/pam.d/sudo_local, which system
+// updates leave alone, so pam_tid.so is enabled there. Older versions have no
+// sudo_local and the line goes into /etc/pam.d/sudo itself, as touchid.sh did.
+// Either file is copied to <file>.mole-backup before it is first changed.
+
+const (
+	// TouchIDPamModule is the PAM module that authenticates with Touch ID
+	TouchIDPamModule = "/usr/lib/pam/pam_tid.so.2"
+	// TouchIDConfigPath is the PAM file Touch ID is enabled in when sudo includes it
+	TouchIDConfigPath = "/etc/pam.d/sudo_local"
+
+	sudoPamFile     = "/etc/pam.d/sudo"
+	touchIDPamLine  = "auth       sufficient     pam_tid.so"
+	pamBackupSuffix = ".mole-backup"
+)
+
+// TouchIDConfigFile returns the PAM file Touch ID is enabled in: sudo_local
+// when /etc/pam.d/sudo includes it, /etc/pam.d/sudo otherwise
+func TouchIDConfigFile() string {
+	if data, err := os.ReadFile(sudoPamFile); err == nil && !includesSudoLocal(string(data)) {
+		return sudoPamFile
+	}
+	return TouchIDConfigPath
+}
+
+// includesSudoLocal reports whether sudo's PAM config includes sudo_local
+func includesSudoLocal(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "#") && strings.Contains(line, "sudo_local") {
+			return true
+		}
+	}
+	return false
+}
+
+// TouchIDSudoEnabled reports whether sudo loads pam_tid.so, and from which file
+func TouchIDSudoEnabled() (bool, string) {
+	for _, path := range []string{TouchIDConfigPath, sudoPamFile} {
+		data, err := os.ReadFile(path)
+		if err == nil && loadsTouchID(string(data)) {
+			return true, path
+		}
+	}
+	return false, ""
+}
+
+func loadsTouchID(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if isTouchIDLine(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTouchIDLine reports whether line is an active pam_tid.so entry
+func isTouchIDLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return !strings.HasPrefix(line, "#") && strings.Contains(line, "pam_tid.so")
+}
+
+// enableTouchIDSudo turns on pam_tid.so in sudo_local, or in /etc/pam.d/sudo
+// where sudo_local is not included
+func enableTouchIDSudo(ctx context.Context, req Request, res *Result) error {
+	if enabled, path := TouchIDSudoEnabled(); enabled {
+		res.Output = append(res.Output, "Touch ID is already enabled for sudo in "+path)
+		return nil
+	}
+
+	path := TouchIDConfigFile()
+	data, err := os.ReadFile(path)
+	if err != nil && !(os.IsNotExist(err) && path == TouchIDConfigPath) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if req.DryRun {
+		res.Output = append(res.Output, "would add pam_tid.so to "+path)
+		return nil
+	}
+
+	if err := replacePamFile(path, withTouchID(string(data))); err != nil {
+		return err
+	}
+	res.Output = append(res.Output, "Touch ID enabled for sudo in "+path)
+	return nil
+}
+
+// disableTouchIDSudo comments pam_tid.so out of sudo_local and drops a line
+// an older setup added to /etc/pam.d/sudo
+func disableTouchIDSudo(ctx context.Context, req Request, res *Result) error {
+	for _, path := range []string{TouchIDConfigPath, sudoPamFile} {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if !loadsTouchID(string(data)) {
+			continue
+		}
+		if req.DryRun {
+			res.Output = append(res.Output, "would remove pam_tid.so from "+path)
+			continue
+		}
+		if err := replacePamFile(path, withoutTouchID(string(data), path == TouchIDConfigPath)); err != nil {
+			return err
+		}
+		res.Output = append(res.Output, "Touch ID disabled for sudo in "+path)
+	}
+	return nil
+}
+
+// withTouchID returns PAM content with pam_tid.so active: the commented line
+// of Apple's sudo_local template is uncommented, otherwise the line goes
+// before the first entry, since a sufficient module only helps ahead of the
+// others, or at the end of a file with no entries
+func withTouchID(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "#") && strings.Contains(trimmed, "pam_tid.so") {
+			lines[i] = touchIDPamLine
+			return strings.Join(lines, "\n")
+		}
+	}
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			lines = append(lines[:i], append([]string{touchIDPamLine}, lines[i:]...)...)
+			return strings.Join(lines, "\n")
+		}
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content + touchIDPamLine + "\n"
+}
+
+// withoutTouchID comments out, or drops, every active pam_tid.so line
+func withoutTouchID(content string, comment bool) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if isTouchIDLine(line) {
+			if !comment {
+				continue
+			}
+			line = "#" + line
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
+
+// replacePamFile atomically replaces a PAM file, keeping its permissions.
+// An existing file is backed up first. New files get the read-only mode
+// Apple ships them with.
+func replacePamFile(path, content string) error {
+	perm := os.FileMode(0444)
+	if info, err := os.Lstat(path); err == nil {
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+		perm = info.Mode().Perm()
+		if err := backupPamFile(path, perm); err != nil {
+			return err
+		}
+	}
+
+	tmpPath := path + ".mole.tmp"
+	os.Remove(tmpPath)
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", tmpPath, err)
+	}
+	_, err = f.WriteString(content)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// backupPamFile copies path to path.mole-backup unless a backup exists, so
+// the backup keeps the file as it was before it was first changed
+func backupPamFile(path string, perm os.FileMode) error {
+	backupPath := path + pamBackupSuffix
+	if _, err := os.Lstat(backupPath); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	f, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(backupPath)
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	return nil
+}
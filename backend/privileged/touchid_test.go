@@ -0,0 +1,134 @@
+package privileged
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	sudoLocalTemplate = "# sudo_local: local config file which survives system update and is included for sudo\n" +
+		"# uncomment following line to enable Touch ID for sudo\n" +
+		"#auth       sufficient     pam_tid.so\n"
+	legacySudo = "# sudo: auth account password session\n" +
+		"auth       sufficient     pam_smartcard.so\n" +
+		"auth       required       pam_opendirectory.so\n"
+	modernSudo = "# sudo: auth account password session\n" +
+		"auth       include        sudo_local\n" +
+		"auth       sufficient     pam_smartcard.so\n"
+)
+
+func TestWithTouchID(t *testing.T) {
+	tests := []struct {
+		name, content, want string
+	}{
+		{
+			name:    "uncomments the template line",
+			content: sudoLocalTemplate,
+			want: "# sudo_local: local config file which survives system update and is included for sudo\n" +
+				"# uncomment following line to enable Touch ID for sudo\n" +
+				touchIDPamLine + "\n",
+		},
+		{
+			name:    "goes before the first entry",
+			content: legacySudo,
+			want: "# sudo: auth account password session\n" +
+				touchIDPamLine + "\n" +
+				"auth       sufficient     pam_smartcard.so\n" +
+				"auth       required       pam_opendirectory.so\n",
+		},
+		{name: "empty file", content: "", want: touchIDPamLine + "\n"},
+		{name: "comments only, no newline", content: "# local", want: "# local\n" + touchIDPamLine + "\n"},
+	}
+
+	for _, tt := range tests {
+		got := withTouchID(tt.content)
+		if got != tt.want {
+			t.Fatalf("%s: got\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+		if !loadsTouchID(got) {
+			t.Fatalf("%s: result does not load pam_tid.so", tt.name)
+		}
+	}
+}
+
+func TestWithoutTouchID(t *testing.T) {
+	enabled := withTouchID(legacySudo)
+
+	if got := withoutTouchID(enabled, false); got != legacySudo {
+		t.Fatalf("dropping: got\n%q\nwant\n%q", got, legacySudo)
+	}
+
+	commented := withoutTouchID(withTouchID(sudoLocalTemplate), true)
+	if loadsTouchID(commented) {
+		t.Fatalf("commenting left pam_tid.so active:\n%s", commented)
+	}
+	if withTouchID(commented) != withTouchID(sudoLocalTemplate) {
+		t.Fatalf("a commented line must be re-enabled in place:\n%s", withTouchID(commented))
+	}
+}
+
+func TestLoadsTouchID(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"template", sudoLocalTemplate, false},
+		{"active", touchIDPamLine + "\n", true},
+		{"indented", "  " + touchIDPamLine, true},
+		{"commented with spaces", "  # " + touchIDPamLine, false},
+		{"other modules", legacySudo, false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := loadsTouchID(tt.content); got != tt.want {
+			t.Fatalf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIncludesSudoLocal(t *testing.T) {
+	if !includesSudoLocal(modernSudo) {
+		t.Fatalf("expected sudo_local to be included")
+	}
+	if includesSudoLocal(legacySudo) || includesSudoLocal("# auth include sudo_local\n") {
+		t.Fatalf("expected no sudo_local include")
+	}
+}
+
+func TestReplacePamFileKeepsFirstBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sudo")
+	if err := os.WriteFile(path, []byte(legacySudo), 0o444); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replacePamFile(path, withTouchID(legacySudo)); err != nil {
+		t.Fatal(err)
+	}
+	if err := replacePamFile(path, legacySudo+"# second change\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	backup, err := os.ReadFile(path + pamBackupSuffix)
+	if err != nil || string(backup) != legacySudo {
+		t.Fatalf("expected the original in the backup, got %q, %v", backup, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0o444 {
+		t.Fatalf("expected mode 0444 to be kept, got %v, %v", info.Mode(), err)
+	}
+	if _, err := os.Stat(path + ".mole.tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temporary file left behind: %v", err)
+	}
+
+	// New files have nothing to back up
+	fresh := filepath.Join(t.TempDir(), "sudo_local")
+	if err := replacePamFile(fresh, touchIDPamLine+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(fresh + pamBackupSuffix); !os.IsNotExist(err) {
+		t.Fatalf("unexpected backup of a new file: %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+//go:build darwin
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mole-wails/backend/models"
+)
+
+// findAppCandidates lists .app bundles in the Applications folders
+func (s *UninstallService) findAppCandidates() []appCandidate {
+	appDirs := []string{
+		"/Applications",
+		filepath.Join(os.Getenv("HOME"), "Applications"),
+	}
+
+	var candidates []appCandidate
+	for _, dir := range appDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !strings.HasSuffix(entry.Name(), ".app") {
+				continue
+			}
+
+			appPath := filepath.Join(dir, entry.Name())
+			candidates = append(candidates, appCandidate{
+				path:     appPath,
+				metaPath: filepath.Join(appPath, "Contents", "Info.plist"),
+				measure:  func() models.Application { return s.measureBundle(appPath) },
+			})
+		}
+	}
+
+	return candidates
+}
+
+func (s *UninstallService) measureBundle(appPath string) models.Application {
+	// Get app size
+	size, _ := s.getDirSize(appPath)
+	bundleID, version := s.readBundleInfo(appPath)
+
+	// Mac App Store installs carry a purchase receipt inside the bundle
+	source := AppSourceManual
+	if _, err := os.Stat(filepath.Join(appPath, "Contents", "_MASReceipt", "receipt")); err == nil {
+		source = AppSourceAppStore
+	}
+
+	return models.Application{
+		Name:     strings.TrimSuffix(filepath.Base(appPath), ".app"),
+		BundleID: bundleID,
+		Version:  version,
+		Path:     appPath,
+		Size:     size,
+		Source:   source,
+	}
+}
+
+func relatedFileSearchPaths() []string {
+	library := filepath.Join(os.Getenv("HOME"), "Library")
+
+	return []string{
+		filepath.Join(library, "Application Support"),
+		filepath.Join(library, "Caches"),
+		filepath.Join(library, "Preferences"),
+		filepath.Join(library, "Logs"),
+		filepath.Join(library, "Cookies"),
+	}
+}
@@ -0,0 +1,416 @@
+//go:build linux
+
+package services
+
+import (
+	"bufio"
+	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+const packageQueryTimeout = 15 * time.Second
+
+// desktopEntry holds the [Desktop Entry] keys needed to list an application
+type desktopEntry struct {
+	Name      string
+	Exec      string
+	Type      string
+	NoDisplay bool
+	Hidden    bool
+	Flatpak   string
+	Snap      string
+}
+
+type flatpakApp struct {
+	ID           string
+	Name         string
+	Version      string
+	Installation string
+}
+
+type snapApp struct {
+	Name     string
+	Version  string
+	Revision string
+}
+
+// findAppCandidates lists Flatpak and Snap packages, .desktop applications and AppImages
+func (s *UninstallService) findAppCandidates() []appCandidate {
+	var candidates []appCandidate
+	candidates = append(candidates, s.findFlatpakApps()...)
+	candidates = append(candidates, s.findSnapApps()...)
+	candidates = append(candidates, s.findDesktopApps()...)
+	candidates = append(candidates, s.findAppImages()...)
+	return candidates
+}
+
+func relatedFileSearchPaths() []string {
+	home := os.Getenv("HOME")
+
+	return []string{
+		filepath.Join(home, ".config"),
+		filepath.Join(home, ".cache"),
+		filepath.Join(home, ".local", "share"),
+		filepath.Join(home, ".var", "app"),
+	}
+}
+
+// Desktop entries
+
+func xdgDataDirs() []string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(os.Getenv("HOME"), ".local", "share")
+	}
+
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+
+	return append([]string{dataHome}, filepath.SplitList(dataDirs)...)
+}
+
+func (s *UninstallService) findDesktopApps() []appCandidate {
+	seen := make(map[string]bool)
+	var candidates []appCandidate
+
+	for _, dataDir := range xdgDataDirs() {
+		appsDir := filepath.Join(dataDir, "applications")
+
+		// Flatpak and Snap exports are reported by their own sources
+		if strings.Contains(appsDir, "flatpak/exports") || strings.Contains(appsDir, "snapd/desktop") {
+			continue
+		}
+
+		_ = filepath.WalkDir(appsDir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(d.Name(), ".desktop") {
+				return nil
+			}
+
+			// Desktop file IDs use "-" for subdirectories; earlier data dirs take precedence
+			rel, _ := filepath.Rel(appsDir, path)
+			id := strings.TrimSuffix(strings.ReplaceAll(rel, string(filepath.Separator), "-"), ".desktop")
+			if seen[id] {
+				return nil
+			}
+			seen[id] = true
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil
+			}
+			entry := parseDesktopEntry(string(data))
+			if entry.Type != "Application" || entry.NoDisplay || entry.Hidden || entry.Flatpak != "" || entry.Snap != "" {
+				return nil
+			}
+
+			desktopPath := path
+			candidates = append(candidates, appCandidate{
+				path:     desktopPath,
+				metaPath: desktopPath,
+				measure: func() models.Application {
+					return s.measureDesktopApp(desktopPath, id, entry)
+				},
+				lastUsed: func() time.Time {
+					return latestAccessTime(desktopPath, desktopExecBinary(desktopPath))
+				},
+			})
+			return nil
+		})
+	}
+
+	return candidates
+}
+
+func (s *UninstallService) measureDesktopApp(desktopPath, id string, entry desktopEntry) models.Application {
+	name := entry.Name
+	if name == "" {
+		name = id
+	}
+
+	var size int64
+	if binary := desktopExecBinary(desktopPath); binary != "" {
+		// Apps installed under /opt own their whole folder
+		if rel, ok := strings.CutPrefix(binary, "/opt/"); ok {
+			size, _ = s.getDirSize(filepath.Join("/opt", strings.Split(rel, "/")[0]))
+		} else if info, err := os.Stat(binary); err == nil {
+			size = info.Size()
+		}
+	}
+
+	return models.Application{
+		Name:     name,
+		BundleID: id,
+		Path:     desktopPath,
+		Size:     size,
+		Source:   AppSourceDesktop,
+	}
+}
+
+// parseDesktopEntry parses the [Desktop Entry] group of a .desktop file
+func parseDesktopEntry(data string) desktopEntry {
+	var entry desktopEntry
+	inEntry := false
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if !inEntry {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "Name":
+			entry.Name = strings.TrimSpace(value)
+		case "Exec":
+			entry.Exec = strings.TrimSpace(value)
+		case "Type":
+			entry.Type = strings.TrimSpace(value)
+		case "NoDisplay":
+			entry.NoDisplay = strings.TrimSpace(value) == "true"
+		case "Hidden":
+			entry.Hidden = strings.TrimSpace(value) == "true"
+		case "X-Flatpak":
+			entry.Flatpak = strings.TrimSpace(value)
+		case "X-SnapInstanceName":
+			entry.Snap = strings.TrimSpace(value)
+		}
+	}
+
+	return entry
+}
+
+// Flatpak
+
+func (s *UninstallService) findFlatpakApps() []appCandidate {
+	output, err := runPackageQuery("flatpak", "list", "--app", "--columns=application,name,version,installation")
+	if err != nil {
+		return nil
+	}
+
+	home := os.Getenv("HOME")
+	var candidates []appCandidate
+
+	for _, app := range parseFlatpakList(output) {
+		var appDir string
+		switch app.Installation {
+		case "user":
+			appDir = filepath.Join(home, ".local", "share", "flatpak", "app", app.ID)
+		case "system":
+			appDir = filepath.Join("/var/lib/flatpak", "app", app.ID)
+		default:
+			continue
+		}
+
+		flatpak := app
+		dataDir := filepath.Join(home, ".var", "app", app.ID)
+		candidates = append(candidates, appCandidate{
+			path:     appDir,
+			metaPath: filepath.Join(appDir, "current", "active", "metadata"),
+			measure: func() models.Application {
+				var size int64
+				if active, err := filepath.EvalSymlinks(filepath.Join(appDir, "current", "active")); err == nil {
+					size, _ = s.getDirSize(active)
+				}
+				return models.Application{
+					Name:     flatpak.Name,
+					BundleID: flatpak.ID,
+					Version:  flatpak.Version,
+					Path:     appDir,
+					Size:     size,
+					Source:   AppSourceFlatpak,
+				}
+			},
+			lastUsed: func() time.Time { return latestAccessTime(dataDir) },
+		})
+	}
+
+	return candidates
+}
+
+// parseFlatpakList parses `flatpak list --app --columns=application,name,version,installation`
+func parseFlatpakList(output string) []flatpakApp {
+	var apps []flatpakApp
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 4 || fields[0] == "" || fields[0] == "Application ID" {
+			continue
+		}
+		apps = append(apps, flatpakApp{
+			ID:           strings.TrimSpace(fields[0]),
+			Name:         strings.TrimSpace(fields[1]),
+			Version:      strings.TrimSpace(fields[2]),
+			Installation: strings.TrimSpace(fields[3]),
+		})
+	}
+
+	return apps
+}
+
+// Snap
+
+func (s *UninstallService) findSnapApps() []appCandidate {
+	output, err := runPackageQuery("snap", "list")
+	if err != nil {
+		return nil
+	}
+
+	home := os.Getenv("HOME")
+	var candidates []appCandidate
+
+	for _, app := range parseSnapList(output) {
+		snap := app
+		snapDir := filepath.Join("/snap", snap.Name)
+		dataDir := filepath.Join(home, "snap", snap.Name)
+		candidates = append(candidates, appCandidate{
+			path:     snapDir,
+			metaPath: filepath.Join(snapDir, "current", "meta", "snap.yaml"),
+			measure: func() models.Application {
+				var size int64
+				image := filepath.Join("/var/lib/snapd/snaps", snap.Name+"_"+snap.Revision+".snap")
+				if info, err := os.Stat(image); err == nil {
+					size = info.Size()
+				}
+				return models.Application{
+					Name:     snap.Name,
+					BundleID: snap.Name,
+					Version:  snap.Version,
+					Path:     snapDir,
+					Size:     size,
+					Source:   AppSourceSnap,
+				}
+			},
+			lastUsed: func() time.Time { return latestAccessTime(dataDir) },
+		})
+	}
+
+	return candidates
+}
+
+// parseSnapList parses `snap list`, skipping bases, kernels and snapd itself
+func parseSnapList(output string) []snapApp {
+	var apps []snapApp
+
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 3 {
+			continue
+		}
+
+		notes := ""
+		if len(fields) >= 6 {
+			notes = fields[5]
+		}
+		if fields[0] == "snapd" || strings.Contains(notes, "base") || strings.Contains(notes, "core") ||
+			strings.Contains(notes, "kernel") || strings.Contains(notes, "gadget") || strings.Contains(notes, "snapd") {
+			continue
+		}
+
+		apps = append(apps, snapApp{
+			Name:     fields[0],
+			Version:  fields[1],
+			Revision: fields[2],
+		})
+	}
+
+	return apps
+}
+
+// AppImages
+
+func (s *UninstallService) findAppImages() []appCandidate {
+	home := os.Getenv("HOME")
+	dirs := []string{
+		filepath.Join(home, "Applications"),
+		filepath.Join(home, "AppImages"),
+		filepath.Join(home, ".local", "bin"),
+		filepath.Join(home, "Downloads"),
+		filepath.Join(home, "Desktop"),
+		"/opt",
+	}
+
+	var candidates []appCandidate
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".appimage") {
+				continue
+			}
+
+			imagePath := filepath.Join(dir, entry.Name())
+			name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+			candidates = append(candidates, appCandidate{
+				path: imagePath,
+				measure: func() models.Application {
+					var size int64
+					if info, err := os.Stat(imagePath); err == nil {
+						size = info.Size()
+					}
+					return models.Application{
+						Name:     name,
+						BundleID: name,
+						Path:     imagePath,
+						Size:     size,
+						Source:   AppSourceAppImage,
+					}
+				},
+				lastUsed: func() time.Time { return latestAccessTime(imagePath) },
+			})
+		}
+	}
+
+	return candidates
+}
+
+// Helper functions
+
+func runPackageQuery(name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), packageQueryTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+func latestAccessTime(paths ...string) time.Time {
+	var latest time.Time
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if atime := accessTime(path); atime.After(latest) {
+			latest = atime
+		}
+	}
+	return latest
+}
@@ -0,0 +1,126 @@
+//go:build linux
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func readLinuxFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "linux", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return string(data)
+}
+
+func TestParseDesktopEntry(t *testing.T) {
+	data := `# Comment
+[Desktop Entry]
+Type=Application
+Name=Text Editor
+Name[de]=Texteditor
+Exec=/usr/bin/editor --new-window %F
+NoDisplay=false
+X-Flatpak=org.example.Editor
+
+[Desktop Action new-window]
+Name=New Window
+Exec=/usr/bin/editor --other
+Hidden=true
+`
+	want := desktopEntry{
+		Name:    "Text Editor",
+		Exec:    "/usr/bin/editor --new-window %F",
+		Type:    "Application",
+		Flatpak: "org.example.Editor",
+	}
+	if got := parseDesktopEntry(data); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	snap := parseDesktopEntry("[Desktop Entry]\nType=Application\nNoDisplay=true\nHidden=true\nX-SnapInstanceName=spotify\n")
+	if !snap.NoDisplay || !snap.Hidden || snap.Snap != "spotify" {
+		t.Fatalf("unexpected entry: %+v", snap)
+	}
+}
+
+func TestParseFlatpakList(t *testing.T) {
+	want := []flatpakApp{
+		{ID: "org.mozilla.firefox", Name: "Firefox", Version: "128.0.3", Installation: "system"},
+		{ID: "com.spotify.Client", Name: "Spotify", Version: "1.2.31.1205", Installation: "user"},
+		{ID: "org.gnome.Calculator", Name: "Calculator", Version: "", Installation: "system"},
+	}
+	if got := parseFlatpakList(readLinuxFixture(t, "flatpak_list.txt")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSnapList(t *testing.T) {
+	// Bases, kernels and snapd itself are not applications
+	want := []snapApp{
+		{Name: "firefox", Version: "128.0.3-1", Revision: "4650"},
+		{Name: "spotify", Version: "1.2.31.1205", Revision: "76"},
+		{Name: "vlc", Version: "3.0.20", Revision: "3777"},
+	}
+	if got := parseSnapList(readLinuxFixture(t, "snap_list.txt")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestFindDesktopApps(t *testing.T) {
+	root := t.TempDir()
+	dataHome := filepath.Join(root, "home")
+	dataDir := filepath.Join(root, "usr")
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	t.Setenv("XDG_DATA_DIRS", dataDir+string(os.PathListSeparator)+filepath.Join(root, "flatpak", "exports"))
+
+	write := func(dir, name, data string) {
+		path := filepath.Join(dir, "applications", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	app := func(name string) string {
+		return "[Desktop Entry]\nType=Application\nName=" + name + "\nExec=/nonexistent/bin/" + name + "\n"
+	}
+
+	write(dataDir, "editor.desktop", app("Editor"))
+	write(dataHome, "editor.desktop", app("My Editor")) // Shadows the system entry
+	write(dataDir, "vendor/tool.desktop", app("Tool"))
+	write(dataDir, "hidden.desktop", app("Hidden")+"NoDisplay=true\n")
+	write(dataDir, "removed.desktop", app("Removed")+"Hidden=true\n")
+	write(dataDir, "flatpak.desktop", app("Flatpak")+"X-Flatpak=org.example.App\n")
+	write(dataDir, "snap.desktop", app("Snap")+"X-SnapInstanceName=snap\n")
+	write(dataDir, "link.desktop", "[Desktop Entry]\nType=Link\nName=Link\nURL=https://example.com\n")
+	write(dataDir, "notes.txt", app("Notes"))
+	write(filepath.Join(root, "flatpak", "exports"), "exported.desktop", app("Exported"))
+
+	s := &UninstallService{}
+	got := make(map[string]string)
+	for _, candidate := range s.findDesktopApps() {
+		if candidate.path != candidate.metaPath {
+			t.Fatalf("desktop candidate %s should be its own metadata", candidate.path)
+		}
+		measured := candidate.measure()
+		if measured.Source != AppSourceDesktop || measured.Path != candidate.path {
+			t.Fatalf("unexpected application: %+v", measured)
+		}
+		got[measured.BundleID] = measured.Name
+	}
+
+	want := map[string]string{
+		"editor":      "My Editor",
+		"vendor-tool": "Tool",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
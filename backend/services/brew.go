@@ -0,0 +1,379 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"mole-wails/backend/models"
+)
+
+const (
+	brewQueryTimeout     = 30 * time.Second
+	brewUninstallTimeout = 10 * time.Minute
+)
+
+// officialCaskTap is the tap of casks that need no `brew tap`
+const officialCaskTap = "homebrew/cask"
+
+var brewPaths = []string{
+	"/opt/homebrew/bin/brew",
+	"/usr/local/bin/brew",
+	"/home/linuxbrew/.linuxbrew/bin/brew",
+}
+
+var caskroomPaths = []string{
+	"/opt/homebrew/Caskroom",
+	"/usr/local/Caskroom",
+}
+
+var caskAppStanza = regexp.MustCompile(`(?m)^\s*app\s+"([^"]+)"(?:\s*,\s*target:\s*"([^"]+)")?`)
+
+// caskInfo describes an installed Homebrew cask and the app bundles it owns
+type caskInfo struct {
+	Token   string   `json:"token"`
+	Tap     string   `json:"tap"`
+	Version string   `json:"version"`
+	Apps    []string `json:"apps"`
+}
+
+type brewInfoJSON struct {
+	Casks []brewCaskJSON `json:"casks"`
+}
+
+type brewCaskJSON struct {
+	Token     string                       `json:"token"`
+	Tap       string                       `json:"tap"`
+	Version   string                       `json:"version"`
+	Installed string                       `json:"installed"`
+	Artifacts []map[string]json.RawMessage `json:"artifacts"`
+}
+
+// findBrew locates the brew binary; GUI apps don't inherit the shell PATH
+func findBrew() string {
+	if path, err := exec.LookPath("brew"); err == nil {
+		return path
+	}
+	for _, path := range brewPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+func runBrew(brew string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), brewQueryTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, brew, args...)
+	cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1", "HOMEBREW_NO_ANALYTICS=1")
+	return cmd.Output()
+}
+
+// loadInstalledCasks maps app bundle names (e.g. "Firefox.app") to the cask that installed them
+func loadInstalledCasks() map[string]caskInfo {
+	casks := make(map[string]caskInfo)
+
+	if brew := findBrew(); brew != "" {
+		versions := map[string]string{}
+		if output, err := runBrew(brew, "list", "--cask", "--versions"); err == nil {
+			versions = parseBrewListVersions(string(output))
+		}
+		if output, err := runBrew(brew, "info", "--json=v2", "--installed"); err == nil {
+			if parsed, err := parseBrewInfoCasks(output); err == nil {
+				for _, cask := range parsed {
+					if version, ok := versions[cask.Token]; ok {
+						cask.Version = version
+					}
+					addCask(casks, cask)
+				}
+			}
+		}
+	}
+
+	// Caskroom metadata covers casks brew info could not report
+	for _, caskroom := range caskroomPaths {
+		for _, cask := range readCaskroom(caskroom) {
+			addCask(casks, cask)
+		}
+	}
+
+	return casks
+}
+
+func addCask(casks map[string]caskInfo, cask caskInfo) {
+	for _, app := range cask.Apps {
+		if _, exists := casks[app]; !exists {
+			casks[app] = cask
+		}
+	}
+}
+
+// parseBrewListVersions parses `brew list --cask --versions` output
+func parseBrewListVersions(output string) map[string]string {
+	versions := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		versions[fields[0]] = fields[len(fields)-1]
+	}
+	return versions
+}
+
+// parseBrewInfoCasks parses `brew info --json=v2 --installed` output
+func parseBrewInfoCasks(data []byte) ([]caskInfo, error) {
+	var info brewInfoJSON
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse brew info: %w", err)
+	}
+
+	var casks []caskInfo
+	for _, cask := range info.Casks {
+		version := cask.Installed
+		if version == "" {
+			version = cask.Version
+		}
+		casks = append(casks, caskInfo{
+			Token:   cask.Token,
+			Tap:     cask.Tap,
+			Version: version,
+			Apps:    caskAppArtifacts(cask.Artifacts),
+		})
+	}
+	return casks, nil
+}
+
+// caskAppArtifacts extracts app bundle names from cask artifacts, honouring
+// renamed targets such as ["Foo.app", {"target": "Bar.app"}]
+func caskAppArtifacts(artifacts []map[string]json.RawMessage) []string {
+	var apps []string
+	for _, artifact := range artifacts {
+		raw, ok := artifact["app"]
+		if !ok {
+			continue
+		}
+
+		var values []interface{}
+		if err := json.Unmarshal(raw, &values); err != nil {
+			continue
+		}
+
+		for _, value := range values {
+			switch v := value.(type) {
+			case string:
+				apps = append(apps, filepath.Base(v))
+			case map[string]interface{}:
+				target, ok := v["target"].(string)
+				if ok && len(apps) > 0 {
+					apps[len(apps)-1] = filepath.Base(target)
+				}
+			}
+		}
+	}
+	return apps
+}
+
+// readCaskroom reads cask definitions stored under Caskroom/<token>/.metadata
+func readCaskroom(caskroom string) []caskInfo {
+	tokens, err := os.ReadDir(caskroom)
+	if err != nil {
+		return nil
+	}
+
+	var casks []caskInfo
+	for _, token := range tokens {
+		if !token.IsDir() {
+			continue
+		}
+
+		pattern := filepath.Join(caskroom, token.Name(), ".metadata", "*", "*", "Casks", token.Name()+".*")
+		matches, _ := filepath.Glob(pattern)
+		if len(matches) == 0 {
+			continue
+		}
+
+		// Version folders do not sort by name ("10.0" < "9.0"); the newest
+		// definition is the installed one
+		definition := newestFile(matches)
+		version := filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(definition))))
+
+		data, err := os.ReadFile(definition)
+		if err != nil {
+			continue
+		}
+
+		cask := caskInfo{Token: token.Name(), Version: version}
+		switch filepath.Ext(definition) {
+		case ".json":
+			var parsed brewCaskJSON
+			if err := json.Unmarshal(data, &parsed); err != nil {
+				continue
+			}
+			cask.Tap = parsed.Tap
+			cask.Apps = caskAppArtifacts(parsed.Artifacts)
+		case ".rb":
+			cask.Apps = parseCaskRubyApps(string(data))
+		}
+
+		if len(cask.Apps) > 0 {
+			casks = append(casks, cask)
+		}
+	}
+	return casks
+}
+
+// newestFile returns the most recently modified of paths, the lexically last on ties
+func newestFile(paths []string) string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	newest := sorted[len(sorted)-1]
+	var newestTime time.Time
+	for _, path := range sorted {
+		if t := modTime(path); !t.Before(newestTime) {
+			newest, newestTime = path, t
+		}
+	}
+	return newest
+}
+
+func parseCaskRubyApps(source string) []string {
+	var apps []string
+	for _, match := range caskAppStanza.FindAllStringSubmatch(source, -1) {
+		name := match[1]
+		if match[2] != "" {
+			name = match[2]
+		}
+		apps = append(apps, filepath.Base(name))
+	}
+	return apps
+}
+
+// caskroomStamp records the mtime of each Caskroom folder, which changes
+// whenever a cask is installed or removed
+func caskroomStamp() map[string]time.Time {
+	stamp := make(map[string]time.Time)
+	for _, caskroom := range caskroomPaths {
+		if t := modTime(caskroom); !t.IsZero() {
+			stamp[caskroom] = t
+		}
+	}
+	return stamp
+}
+
+func sameCaskroomStamp(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for path, t := range a {
+		if other, ok := b[path]; !ok || !other.Equal(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// markCaskApps flags applications installed through Homebrew casks
+func markCaskApps(apps []models.Application, casks map[string]caskInfo) {
+	if len(casks) == 0 {
+		return
+	}
+
+	for i := range apps {
+		if cask, ok := casks[filepath.Base(apps[i].Path)]; ok {
+			apps[i].Source = AppSourceCask
+			apps[i].CaskToken = cask.Token
+			apps[i].CaskTap = cask.Tap
+		}
+	}
+}
+
+// uninstallCask removes a cask-managed app through brew so Homebrew's records stay consistent.
+// Progress is keyed by bundle ID, like the uninstall script's.
+func (s *UninstallService) uninstallCask(app models.Application, percent int) error {
+	brew := findBrew()
+	if brew == "" {
+		return fmt.Errorf("brew not found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), brewUninstallTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, brew, "uninstall", "--cask", "--zap", app.CaskToken)
+	cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1", "HOMEBREW_NO_ANALYTICS=1", "NONINTERACTIVE=1")
+
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start brew: %w", err)
+	}
+
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+		writer.Close()
+	}()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	step := ""
+	filesRemoved := 0
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		level := "info"
+		switch {
+		case strings.HasPrefix(line, "==> "):
+			step = strings.TrimPrefix(line, "==> ")
+			if strings.HasPrefix(step, "Removing") || strings.HasPrefix(step, "Trashing") {
+				filesRemoved++
+			}
+		case strings.HasPrefix(line, "Error:"):
+			level = "error"
+		case strings.HasPrefix(line, "Warning:"):
+			level = "warning"
+		}
+
+		if s.ctx != nil {
+			runtime.EventsEmit(s.ctx, "uninstall:progress", models.UninstallProgress{
+				App:          app.BundleID,
+				Message:      line,
+				Step:         step,
+				Level:        level,
+				Percent:      percent,
+				FilesRemoved: filesRemoved,
+			})
+		}
+	}
+	// The scanner stops early on an oversized line; brew blocks writing to the
+	// pipe and cmd.Wait never returns unless the rest is consumed
+	_, _ = io.Copy(io.Discard, reader)
+
+	if err := <-waitErr; err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("brew uninstall timed out for %s", app.CaskToken)
+		}
+		return fmt.Errorf("brew uninstall failed for %s: %w", app.CaskToken, err)
+	}
+
+	return nil
+}
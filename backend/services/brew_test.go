@@ -0,0 +1,150 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "brew", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return data
+}
+
+func TestParseBrewListVersions(t *testing.T) {
+	versions := parseBrewListVersions(string(readFixture(t, "list_cask_versions.txt")))
+
+	want := map[string]string{
+		"firefox":            "128.0.3",
+		"google-chrome":      "127.0.6533.89",
+		"iterm2":             "3.5.4", // Several installed versions: the last is current
+		"visual-studio-code": "1.92.0",
+	}
+	if !reflect.DeepEqual(versions, want) {
+		t.Fatalf("got %v, want %v", versions, want)
+	}
+}
+
+func TestParseBrewInfoCasks(t *testing.T) {
+	casks, err := parseBrewInfoCasks(readFixture(t, "info_installed.json"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		token   string
+		tap     string
+		version string
+		apps    []string
+	}{
+		{"firefox", "homebrew/cask", "128.0.2", []string{"Firefox.app"}},
+		{"visual-studio-code", "homebrew/cask", "1.92.0", []string{"Visual Studio Code.app"}},
+		{"docker", "homebrew/cask", "4.33.0,160616", []string{"Docker Desktop.app"}},
+		{"font-fira-code", "homebrew/cask", "6.2", nil},
+		{"hammerspoon", "example/tools", "1.0.0", []string{"Hammerspoon.app"}},
+	}
+	if len(casks) != len(tests) {
+		t.Fatalf("expected %d casks, got %d", len(tests), len(casks))
+	}
+	for i, tt := range tests {
+		cask := casks[i]
+		if cask.Token != tt.token || cask.Tap != tt.tap || cask.Version != tt.version || !reflect.DeepEqual(cask.Apps, tt.apps) {
+			t.Fatalf("cask %d: got %+v, want %s %s %s %v", i, cask, tt.token, tt.tap, tt.version, tt.apps)
+		}
+	}
+}
+
+func TestParseBrewInfoCasksInvalid(t *testing.T) {
+	if _, err := parseBrewInfoCasks([]byte("Error: no casks")); err == nil {
+		t.Fatal("expected an error for non-JSON output")
+	}
+}
+
+func TestParseCaskRubyApps(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []string
+	}{
+		{"iterm2.rb", []string{"iTerm.app"}},
+		{"parallels.rb", []string{"Parallels Desktop.app", "Parallels Toolbox.app"}},
+	}
+	for _, tt := range tests {
+		if got := parseCaskRubyApps(string(readFixture(t, tt.fixture))); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("%s: got %v, want %v", tt.fixture, got, tt.want)
+		}
+	}
+}
+
+func TestReadCaskroomPicksNewestVersion(t *testing.T) {
+	caskroom := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+
+	install := func(token, version, fixture, file string, mtime time.Time) {
+		dir := filepath.Join(caskroom, token, ".metadata", version, "20240101000000.000", "Casks")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, file)
+		if err := os.WriteFile(path, readFixture(t, fixture), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// "9.0" sorts after "10.0" by name, but 10.0 was installed last
+	install("iterm2", "9.0", "iterm2.rb", "iterm2.rb", old)
+	install("iterm2", "10.0", "iterm2.rb", "iterm2.rb", time.Now())
+	install("firefox", "129.0", "firefox.json", "firefox.json", time.Now())
+
+	casks := readCaskroom(caskroom)
+
+	got := make(map[string]caskInfo)
+	for _, cask := range casks {
+		got[cask.Token] = cask
+	}
+	if cask := got["iterm2"]; cask.Version != "10.0" || !reflect.DeepEqual(cask.Apps, []string{"iTerm.app"}) {
+		t.Fatalf("iterm2: got %+v", cask)
+	}
+	if cask := got["firefox"]; cask.Version != "129.0" || !reflect.DeepEqual(cask.Apps, []string{"Firefox.app"}) {
+		t.Fatalf("firefox: got %+v", cask)
+	}
+}
+
+func TestUninstallCaskSurvivesLongLines(t *testing.T) {
+	// A fake brew whose output has a line longer than the scanner accepts
+	bin := t.TempDir()
+	script := "#!/bin/sh\n" +
+		"echo '==> Removing App'\n" +
+		"head -c 2000000 /dev/zero | tr '\\0' x\n" +
+		"echo\n" +
+		"head -c 200000 /dev/zero | tr '\\0' y\n" +
+		"echo\n"
+	if err := os.WriteFile(filepath.Join(bin, "brew"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	s := &UninstallService{}
+	done := make(chan error, 1)
+	go func() {
+		done <- s.uninstallCask(models.Application{BundleID: "com.example.app", CaskToken: "example"}, 100)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("uninstallCask did not return")
+	}
+}
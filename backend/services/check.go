@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
+)
+
+// Check statuses reported in models.CheckResult.Status
+const (
+	CheckStatusPass    = "pass"
+	CheckStatusWarn    = "warn"
+	CheckStatusFail    = "fail"
+	CheckStatusSkipped = "skipped"
+	CheckStatusError   = "error"
+)
+
+const (
+	defaultCheckTimeout = 15 * time.Second
+	// Fixes include the password prompt of the privileged helper
+	defaultFixTimeout = 5 * time.Minute
+)
+
+type CheckService struct {
+	ctx    context.Context
+	broker *privileged.Broker
+}
+
+func NewCheckService(broker *privileged.Broker) *CheckService {
+	return &CheckService{
+		broker: broker,
+	}
+}
+
+func (s *CheckService) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// RunAll runs every check concurrently and returns the results in registry order
+func (s *CheckService) RunAll() ([]models.CheckResult, error) {
+	whitelist, err := loadOptimizeWhitelistSet()
+	if err != nil {
+		return nil, err
+	}
+
+	defs := checkRegistry()
+	results := make([]models.CheckResult, len(defs))
+
+	var mu sync.Mutex
+	completed := 0
+
+	var wg sync.WaitGroup
+	for i, def := range defs {
+		wg.Add(1)
+		go func(i int, def checkDef) {
+			defer wg.Done()
+
+			result := s.runCheck(def, whitelist[def.id])
+
+			mu.Lock()
+			results[i] = result
+			completed++
+			progress := models.CheckProgress{
+				Completed: completed,
+				Total:     len(defs),
+				Percent:   completed * 100 / len(defs),
+				Result:    result,
+			}
+			mu.Unlock()
+
+			s.emit("check:progress", progress)
+		}(i, def)
+	}
+	wg.Wait()
+
+	s.emit("check:complete", results)
+	return results, nil
+}
+
+// RunCheck runs a single check by ID
+func (s *CheckService) RunCheck(id string) (models.CheckResult, error) {
+	def, ok := findCheck(id)
+	if !ok {
+		return models.CheckResult{}, fmt.Errorf("unknown check: %s", id)
+	}
+
+	whitelist, err := loadOptimizeWhitelistSet()
+	if err != nil {
+		return models.CheckResult{}, err
+	}
+
+	result := s.runCheck(def, whitelist[id])
+	s.emit("check:progress", models.CheckProgress{Completed: 1, Total: 1, Percent: 100, Result: result})
+	return result, nil
+}
+
+// ApplyFix runs a check's auto-fix and re-runs the check to confirm it
+func (s *CheckService) ApplyFix(id string) (models.CheckFixResult, error) {
+	def, ok := findCheck(id)
+	if !ok {
+		return models.CheckFixResult{}, fmt.Errorf("unknown check: %s", id)
+	}
+	if def.fix == nil {
+		return models.CheckFixResult{}, fmt.Errorf("check %s has no automatic fix", id)
+	}
+
+	ctx := s.baseContext()
+	timeout := def.fix.timeout
+	if timeout == 0 {
+		timeout = defaultFixTimeout
+	}
+	fixCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	progress := func(message string) {
+		s.emit("check:fix-progress", models.CheckFixProgress{CheckID: id, Message: message})
+	}
+	progress(fmt.Sprintf("Applying fix: %s", def.fix.description))
+
+	fixResult := models.CheckFixResult{CheckID: id, Output: []string{}}
+	output, err := def.fix.apply(fixCtx, s, progress)
+	if output != nil {
+		fixResult.Output = output
+	}
+	if err != nil {
+		fixResult.Error = err.Error()
+	} else {
+		fixResult.Applied = true
+	}
+
+	progress(fmt.Sprintf("Re-checking %s...", def.name))
+	fixResult.Result = s.runCheck(def, false)
+	s.emit("check:fix-complete", fixResult)
+
+	if err != nil {
+		return fixResult, fmt.Errorf("failed to fix %s: %w", def.name, err)
+	}
+	return fixResult, nil
+}
+
+// runCheck executes one check under its timeout
+func (s *CheckService) runCheck(def checkDef, whitelisted bool) models.CheckResult {
+	result := models.CheckResult{
+		ID:       def.id,
+		Name:     def.name,
+		Category: def.category,
+		Severity: "info",
+		Evidence: []string{},
+	}
+
+	if whitelisted {
+		result.Status = CheckStatusSkipped
+		result.Message = whitelistedReason
+		return result
+	}
+
+	timeout := def.timeout
+	if timeout == 0 {
+		timeout = defaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(s.baseContext(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	outcome := def.run(ctx)
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		outcome = checkOutcome{status: CheckStatusError, message: fmt.Sprintf("Timed out after %v", timeout)}
+	}
+
+	result.Status = outcome.status
+	result.Message = outcome.message
+	if outcome.evidence != nil {
+		result.Evidence = outcome.evidence
+	}
+
+	// Severity only applies to problems; passing checks stay informational
+	if outcome.status == CheckStatusWarn || outcome.status == CheckStatusFail {
+		result.Severity = def.severity
+		if def.fix != nil {
+			result.Fix = &models.CheckFix{
+				Description:  def.fix.description,
+				RequiresSudo: def.fix.requiresSudo,
+			}
+		}
+	}
+
+	return result
+}
+
+// privileged runs an allow-listed helper command for a fix
+func (s *CheckService) privileged(ctx context.Context, command string) ([]string, error) {
+	if s.broker == nil {
+		return nil, fmt.Errorf("administrator privileges are not available")
+	}
+
+	res, err := s.broker.Run(ctx, privileged.Request{Command: command})
+	if err != nil {
+		return nil, err
+	}
+	if !res.OK {
+		return res.Output, fmt.Errorf("%s", res.Error)
+	}
+	return res.Output, nil
+}
+
+func (s *CheckService) baseContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+func (s *CheckService) emit(name string, data interface{}) {
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, name, data)
+	}
+}
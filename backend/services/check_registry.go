@@ -0,0 +1,356 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+	"mole-wails/backend/privileged"
+)
+
+const (
+	brewCheckTimeout = 60 * time.Second
+	brewFixTimeout   = 30 * time.Minute
+	maxEvidenceLines = 20
+)
+
+// checkDef binds a check's identity to the code that evaluates and fixes it.
+// IDs match the optimize whitelist patterns used by scripts/lib/check/all.sh.
+type checkDef struct {
+	id       string
+	name     string
+	category string
+	severity string // reported when the check warns or fails
+	timeout  time.Duration
+	run      func(ctx context.Context) checkOutcome
+	fix      *checkFixDef
+}
+
+type checkOutcome struct {
+	status   string
+	message  string
+	evidence []string
+}
+
+type checkFixDef struct {
+	description  string
+	requiresSudo bool
+	timeout      time.Duration
+	// apply reports each line of output through progress as it happens
+	apply func(ctx context.Context, s *CheckService, progress func(string)) ([]string, error)
+}
+
+func checkRegistry() []checkDef {
+	return []checkDef{
+		{
+			id:       "check_filevault",
+			name:     "FileVault",
+			category: "security",
+			severity: "high",
+			run:      checkFileVault,
+		},
+		{
+			id:       "firewall",
+			name:     "Firewall",
+			category: "security",
+			severity: "medium",
+			run:      checkFirewall,
+			fix:      privilegedFix("Turn on the application firewall", privileged.CmdEnableFirewall),
+		},
+		{
+			id:       "gatekeeper",
+			name:     "Gatekeeper",
+			category: "security",
+			severity: "medium",
+			run:      checkGatekeeper,
+			fix:      privilegedFix("Re-enable Gatekeeper assessments", privileged.CmdEnableGatekeeper),
+		},
+		{
+			id:       "check_sip",
+			name:     "System Integrity Protection",
+			category: "security",
+			severity: "high",
+			run:      checkSIP,
+		},
+		{
+			id:       "check_touchid",
+			name:     "Touch ID for sudo",
+			category: "config",
+			severity: "low",
+			run:      checkTouchIDSudo,
+			fix:      privilegedFix("Allow Touch ID to authorize sudo", privileged.CmdEnableTouchIDSudo),
+		},
+		{
+			id:       "check_rosetta",
+			name:     "Rosetta 2",
+			category: "config",
+			severity: "low",
+			run:      checkRosetta,
+			fix:      privilegedFix("Install Rosetta 2", privileged.CmdInstallRosetta),
+		},
+		{
+			id:       "check_git_config",
+			name:     "Git identity",
+			category: "config",
+			severity: "info",
+			run:      checkGitConfig,
+		},
+		{
+			id:       "check_brew_updates",
+			name:     "Homebrew packages",
+			category: "updates",
+			severity: "low",
+			timeout:  brewCheckTimeout,
+			run:      checkBrewOutdated,
+			fix: &checkFixDef{
+				description: "Upgrade outdated Homebrew packages",
+				timeout:     brewFixTimeout,
+				apply:       upgradeBrewPackages,
+			},
+		},
+		{
+			id:       "check_disk_space",
+			name:     "Disk space",
+			category: "health",
+			severity: "high",
+			run:      checkDiskSpace,
+		},
+	}
+}
+
+func findCheck(id string) (checkDef, bool) {
+	for _, def := range checkRegistry() {
+		if def.id == id {
+			return def, true
+		}
+	}
+	return checkDef{}, false
+}
+
+func privilegedFix(description, command string) *checkFixDef {
+	return &checkFixDef{
+		description:  description,
+		requiresSudo: true,
+		apply: func(ctx context.Context, s *CheckService, progress func(string)) ([]string, error) {
+			progress("Waiting for administrator approval...")
+			return s.privileged(ctx, command)
+		},
+	}
+}
+
+// Checks, ported from scripts/lib/check/all.sh
+
+func checkFileVault(ctx context.Context) checkOutcome {
+	output, err := checkCommand(ctx, "fdesetup", "status")
+	if err != nil {
+		return unavailable(err)
+	}
+	if strings.Contains(output, "FileVault is On") {
+		return checkOutcome{status: CheckStatusPass, message: "Disk encryption active", evidence: evidenceLines(output)}
+	}
+	return checkOutcome{status: CheckStatusFail, message: "Disk encryption disabled", evidence: evidenceLines(output)}
+}
+
+func checkFirewall(ctx context.Context) checkOutcome {
+	output, err := checkCommand(ctx, "/usr/libexec/ApplicationFirewall/socketfilterfw", "--getglobalstate")
+	if err != nil {
+		return unavailable(err)
+	}
+	if strings.Contains(output, "State = 1") || strings.Contains(output, "State = 2") || strings.Contains(output, "enabled") {
+		return checkOutcome{status: CheckStatusPass, message: "Network protection enabled", evidence: evidenceLines(output)}
+	}
+	return checkOutcome{status: CheckStatusWarn, message: "Network protection disabled", evidence: evidenceLines(output)}
+}
+
+func checkGatekeeper(ctx context.Context) checkOutcome {
+	output, err := checkCommand(ctx, "spctl", "--status")
+	if err != nil && output == "" {
+		return unavailable(err)
+	}
+	if strings.Contains(output, "enabled") {
+		return checkOutcome{status: CheckStatusPass, message: "App download protection active", evidence: evidenceLines(output)}
+	}
+	return checkOutcome{status: CheckStatusWarn, message: "App security disabled", evidence: evidenceLines(output)}
+}
+
+func checkSIP(ctx context.Context) checkOutcome {
+	output, err := checkCommand(ctx, "csrutil", "status")
+	if err != nil {
+		return unavailable(err)
+	}
+	if strings.Contains(output, "enabled") {
+		return checkOutcome{status: CheckStatusPass, message: "System integrity protected", evidence: evidenceLines(output)}
+	}
+	return checkOutcome{status: CheckStatusWarn, message: "System protection disabled; re-enable it from Recovery", evidence: evidenceLines(output)}
+}
+
+func checkTouchIDSudo(ctx context.Context) checkOutcome {
+	if enabled, pamFile := privileged.TouchIDSudoEnabled(); enabled {
+		return checkOutcome{status: CheckStatusPass, message: "Biometric authentication enabled", evidence: []string{pamFile + " loads pam_tid.so"}}
+	}
+
+	supported := runtime.GOOS == "darwin" && runtime.GOARCH == "arm64"
+	if output, err := checkCommand(ctx, "bioutil", "-r"); err == nil {
+		supported = strings.Contains(output, "Touch ID")
+	}
+	if !supported {
+		return checkOutcome{status: CheckStatusSkipped, message: "Touch ID is not available on this Mac"}
+	}
+
+	return checkOutcome{status: CheckStatusWarn, message: "Not configured for sudo", evidence: []string{privileged.TouchIDConfigFile() + " does not load pam_tid.so"}}
+}
+
+func checkRosetta(ctx context.Context) checkOutcome {
+	if runtime.GOOS != "darwin" || runtime.GOARCH != "arm64" {
+		return checkOutcome{status: CheckStatusSkipped, message: "Only needed on Apple Silicon"}
+	}
+
+	const rosetta = "/Library/Apple/usr/share/rosetta/rosetta"
+	if _, err := os.Stat(rosetta); err == nil {
+		return checkOutcome{status: CheckStatusPass, message: "Intel app translation ready", evidence: []string{rosetta}}
+	}
+	return checkOutcome{status: CheckStatusWarn, message: "Intel app support missing"}
+}
+
+func checkGitConfig(ctx context.Context) checkOutcome {
+	if _, err := exec.LookPath("git"); err != nil {
+		return checkOutcome{status: CheckStatusSkipped, message: "Git is not installed"}
+	}
+
+	name, _ := checkCommand(ctx, "git", "config", "--global", "user.name")
+	email, _ := checkCommand(ctx, "git", "config", "--global", "user.email")
+
+	var missing []string
+	if strings.TrimSpace(name) == "" {
+		missing = append(missing, "user.name is not set")
+	}
+	if strings.TrimSpace(email) == "" {
+		missing = append(missing, "user.email is not set")
+	}
+	if len(missing) > 0 {
+		return checkOutcome{status: CheckStatusWarn, message: "User identity not set", evidence: missing}
+	}
+	return checkOutcome{status: CheckStatusPass, message: "Global identity configured"}
+}
+
+func checkBrewOutdated(ctx context.Context) checkOutcome {
+	brew := findBrew()
+	if brew == "" {
+		return checkOutcome{status: CheckStatusSkipped, message: "Homebrew is not installed"}
+	}
+
+	cmd := exec.CommandContext(ctx, brew, "outdated", "--quiet")
+	cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1", "HOMEBREW_NO_ANALYTICS=1")
+	output, err := cmd.Output()
+	if err != nil {
+		return checkOutcome{status: CheckStatusError, message: fmt.Sprintf("brew outdated failed: %v", err)}
+	}
+
+	count := len(strings.Fields(string(output)))
+	if count == 0 {
+		return checkOutcome{status: CheckStatusPass, message: "All packages up to date"}
+	}
+	return checkOutcome{
+		status:   CheckStatusWarn,
+		message:  fmt.Sprintf("%d outdated packages", count),
+		evidence: evidenceLines(string(output)),
+	}
+}
+
+// upgradeBrewPackages runs brew upgrade, streaming its output line by line
+func upgradeBrewPackages(ctx context.Context, s *CheckService, progress func(string)) ([]string, error) {
+	brew := findBrew()
+	if brew == "" {
+		return nil, fmt.Errorf("brew not found")
+	}
+
+	cmd := exec.CommandContext(ctx, brew, "upgrade")
+	cmd.Env = append(os.Environ(), "HOMEBREW_NO_AUTO_UPDATE=1", "HOMEBREW_NO_ANALYTICS=1", "NONINTERACTIVE=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start brew upgrade: %w", err)
+	}
+
+	var output strings.Builder
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		output.WriteString(line + "\n")
+		if strings.TrimSpace(line) != "" {
+			progress(line)
+		}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return evidenceLines(output.String()), fmt.Errorf("brew upgrade failed: %w", err)
+	}
+	return evidenceLines(output.String()), nil
+}
+
+func checkDiskSpace(ctx context.Context) checkOutcome {
+	const gb = 1000 * 1000 * 1000
+
+	var usage *disk.UsageStat
+	for _, mount := range []string{"/System/Volumes/Data", "/"} {
+		if u, err := disk.UsageWithContext(ctx, mount); err == nil {
+			usage = u
+			break
+		}
+	}
+	if usage == nil {
+		return checkOutcome{status: CheckStatusError, message: "Unable to read disk usage"}
+	}
+
+	freeGB := usage.Free / gb
+	evidence := []string{fmt.Sprintf("%s: %d GB free of %d GB", usage.Path, freeGB, usage.Total/gb)}
+	switch {
+	case freeGB < 20:
+		return checkOutcome{status: CheckStatusFail, message: fmt.Sprintf("%d GB free (critical)", freeGB), evidence: evidence}
+	case freeGB < 50:
+		return checkOutcome{status: CheckStatusWarn, message: fmt.Sprintf("%d GB free (low)", freeGB), evidence: evidence}
+	default:
+		return checkOutcome{status: CheckStatusPass, message: fmt.Sprintf("%d GB free", freeGB), evidence: evidence}
+	}
+}
+
+// Helper functions
+
+// checkCommand runs a read-only probe and returns its combined output
+func checkCommand(ctx context.Context, name string, args ...string) (string, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return "", err
+	}
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	return string(output), err
+}
+
+func unavailable(err error) checkOutcome {
+	if _, ok := err.(*exec.Error); ok {
+		return checkOutcome{status: CheckStatusSkipped, message: "Not available on this system"}
+	}
+	return checkOutcome{status: CheckStatusError, message: err.Error()}
+}
+
+func evidenceLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+		if len(lines) == maxEvidenceLines {
+			break
+		}
+	}
+	return lines
+}
@@ -8,9 +8,11 @@ import (
 	"path/filepath"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
 )
 
 // cleanCategory represents a category of files to clean
@@ -20,19 +22,23 @@ type cleanCategory struct {
 	description string
 	paths       []string
 	getSizeFn   func(string) (int64, error) // Custom size calculation if needed
+	privileged  string                      // Helper command for root-owned paths
+	userFiles   bool                        // Paths also hold user files, cleaned without the helper
 }
 
 type CleanService struct {
 	ctx        context.Context
 	categories []cleanCategory
 	whitelist  map[string]bool
+	broker     *privileged.Broker
 }
 
-func NewCleanService(scriptsPath string) *CleanService {
+func NewCleanService(scriptsPath string, broker *privileged.Broker) *CleanService {
 	homeDir := os.Getenv("HOME")
 
 	return &CleanService{
-		whitelist:  make(map[string]bool),
+		broker:    broker,
+		whitelist: make(map[string]bool),
 		categories: []cleanCategory{
 			{
 				id:          "system-caches",
@@ -53,11 +59,13 @@ func NewCleanService(scriptsPath string) *CleanService {
 			{
 				id:          "temp-files",
 				name:        "Temporary Files",
-				description: "System temporary files",
+				description: "Temporary files; old system-owned ones need administrator access",
 				paths: []string{
 					"/tmp",
 					"/private/var/tmp",
 				},
+				privileged: privileged.CmdCleanSystemTemp,
+				userFiles:  true,
 			},
 			{
 				id:          "browser-caches",
@@ -108,6 +116,15 @@ func NewCleanService(scriptsPath string) *CleanService {
 					filepath.Join(homeDir, "Library", "Caches", "com.apple.mail"),
 				},
 			},
+			{
+				id:          "system-library-caches",
+				name:        "Shared Library Caches",
+				description: "Old cache files in /Library/Caches",
+				paths: []string{
+					"/Library/Caches",
+				},
+				privileged: privileged.CmdCleanSystemCaches,
+			},
 		},
 	}
 }
@@ -165,11 +182,12 @@ func (s *CleanService) ScanTargets() ([]models.CleanCategory, error) {
 		estimatedMB := estimatedSize / (1024 * 1024)
 
 		results = append(results, models.CleanCategory{
-			ID:          cat.id,
-			Name:        cat.name,
-			Description: cat.description,
-			Enabled:     true,
-			EstimatedMB: estimatedMB,
+			ID:           cat.id,
+			Name:         cat.name,
+			Description:  cat.description,
+			Enabled:      true,
+			EstimatedMB:  estimatedMB,
+			RequiresSudo: cat.privileged != "" && !cat.userFiles,
 		})
 	}
 
@@ -219,6 +237,19 @@ func (s *CleanService) ExecuteClean(categoryIDs []string, dryRun bool) error {
 			runtime.EventsEmit(s.ctx, "clean:progress", progress)
 		}
 
+		// Root-owned locations are cleaned by the privileged helper
+		if cat.privileged != "" && !cat.userFiles {
+			spaceFreed, filesRemoved, err := s.cleanPrivileged(cat, dryRun)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", cat.name, err))
+			} else if filesRemoved > 0 {
+				totalSpaceFreed += spaceFreed
+				totalFilesRemoved += filesRemoved
+				cleanedCategories = append(cleanedCategories, cat.name)
+			}
+			continue
+		}
+
 		// Clean each path in category
 		for _, path := range cat.paths {
 			// Check if path exists
@@ -242,6 +273,17 @@ func (s *CleanService) ExecuteClean(categoryIDs []string, dryRun bool) error {
 			categoryFilesRemoved += filesRemoved
 		}
 
+		// System-owned files next to the user's are removed once authenticated
+		if cat.privileged != "" && s.broker != nil && s.broker.Running() {
+			spaceFreed, filesRemoved, err := s.cleanPrivileged(cat, dryRun)
+			if err != nil {
+				errors = append(errors, fmt.Sprintf("%s: %v", cat.name, err))
+			} else {
+				categorySpaceFreed += spaceFreed
+				categoryFilesRemoved += filesRemoved
+			}
+		}
+
 		if categoryFilesRemoved > 0 {
 			totalSpaceFreed += categorySpaceFreed
 			totalFilesRemoved += categoryFilesRemoved
@@ -393,6 +435,77 @@ func (s *CleanService) calculateDirSize(path string) (int64, error) {
 	return size, err
 }
 
+// cleanPrivileged asks the root helper to clean a category's fixed locations.
+// Dry runs are estimated without the helper, so they never prompt for a password.
+func (s *CleanService) cleanPrivileged(cat cleanCategory, dryRun bool) (int64, int, error) {
+	for _, path := range cat.paths {
+		if s.isWhitelisted(path) {
+			return 0, 0, nil
+		}
+	}
+
+	if dryRun {
+		size, files := estimatePrivilegedClean(cat.paths)
+		return size, files, nil
+	}
+
+	if s.broker == nil {
+		return 0, 0, fmt.Errorf("administrator privileges are not available")
+	}
+
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	res, err := s.broker.Run(ctx, privileged.Request{Command: cat.privileged, DryRun: dryRun})
+	if err != nil {
+		return 0, 0, err
+	}
+	if !res.OK {
+		return 0, 0, fmt.Errorf("%s", res.Error)
+	}
+
+	return res.BytesFreed, res.FilesRemoved, nil
+}
+
+// estimatePrivilegedClean sizes what the helper would remove: root-owned files
+// older than its age limit, in the root-owned directories the user can list
+func estimatePrivilegedClean(paths []string) (int64, int) {
+	cutoff := time.Now().AddDate(0, 0, -privileged.DefaultMaxAgeDays)
+
+	var size int64
+	var files int
+	for _, path := range paths {
+		root, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			continue
+		}
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			stat, ok := info.Sys().(*syscall.Stat_t)
+			if !ok || stat.Uid != 0 {
+				if d.IsDir() && path != root {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() && !info.ModTime().After(cutoff) {
+				size += info.Size()
+				files++
+			}
+			return nil
+		})
+	}
+	return size, files
+}
+
 // cleanPath removes files from a path
 func (s *CleanService) cleanPath(path string, dryRun bool) (int64, int, error) {
 	var spaceFreed int64
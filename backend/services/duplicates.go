@@ -0,0 +1,179 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// duplicateNameSuffix matches copy markers such as "-beta", " copy 2" or " old".
+// Plain trailing numbers are kept: "Foo 2" is usually another product.
+var duplicateNameSuffix = regexp.MustCompile(`(?i)([ _-]+(beta|copy( \d+)?|old|backup))+$`)
+
+// prereleaseMarker matches pre-release names and versions such as
+// "Xcode-beta", "Foo RC2" or "16.0b3"
+var prereleaseMarker = regexp.MustCompile(`(?i)\b(alpha|beta|preview|rc)\d*\b|\d(a|b|rc)\d+$`)
+
+// GetDuplicateApps groups apps installed more than once under the same bundle ID
+// (or the same name, for apps without one) and marks the copy worth keeping
+func (s *UninstallService) GetDuplicateApps() ([]models.DuplicateAppGroup, error) {
+	apps, err := s.ScanApplications(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []models.DuplicateAppGroup
+	for _, members := range groupDuplicateApps(apps) {
+		copies := make([]models.DuplicateAppCopy, len(members))
+		for i, app := range members {
+			lastUsed := app.LastUsed
+			if s.usage != nil {
+				if usage, err := s.usage.Usage(app); err == nil && !usage.LastUsed.IsZero() {
+					lastUsed = usage.LastUsed
+				}
+			}
+			copies[i] = models.DuplicateAppCopy{App: app, LastUsed: lastUsed}
+		}
+		groups = append(groups, recommendDuplicateCopies(copies))
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Reclaimable > groups[j].Reclaimable
+	})
+
+	return groups, nil
+}
+
+// Helper functions
+
+// groupDuplicateApps joins apps sharing a bundle ID. Names only join apps
+// that have no bundle ID, since similar names often belong to different products.
+func groupDuplicateApps(apps []models.Application) [][]models.Application {
+	members := make(map[string][]models.Application)
+	var keys []string
+	for _, app := range apps {
+		key := "name:" + normalizeAppName(app.Name)
+		if app.BundleID != "" && app.BundleID != "unknown" {
+			key = "id:" + strings.ToLower(app.BundleID)
+		}
+		if _, ok := members[key]; !ok {
+			keys = append(keys, key)
+		}
+		members[key] = append(members[key], app)
+	}
+
+	var groups [][]models.Application
+	for _, key := range keys {
+		if len(members[key]) > 1 {
+			groups = append(groups, members[key])
+		}
+	}
+	return groups
+}
+
+func normalizeAppName(name string) string {
+	return strings.ToLower(strings.TrimSpace(duplicateNameSuffix.ReplaceAllString(name, "")))
+}
+
+// isPrerelease reports whether a copy is a beta, release candidate or preview
+func isPrerelease(app models.Application) bool {
+	return prereleaseMarker.MatchString(app.Name) ||
+		prereleaseMarker.MatchString(strings.TrimSuffix(filepath.Base(app.Path), ".app")) ||
+		prereleaseMarker.MatchString(app.Version)
+}
+
+// recommendDuplicateCopies keeps the newest stable version, preferring the most
+// recently used copy and the shared /Applications folder on ties
+func recommendDuplicateCopies(copies []models.DuplicateAppCopy) models.DuplicateAppGroup {
+	sort.SliceStable(copies, func(i, j int) bool {
+		a, b := copies[i], copies[j]
+		if pa, pb := isPrerelease(a.App), isPrerelease(b.App); pa != pb {
+			return pb
+		}
+		if cmp := compareVersions(a.App.Version, b.App.Version); cmp != 0 {
+			return cmp > 0
+		}
+		if !a.LastUsed.Equal(b.LastUsed) {
+			return a.LastUsed.After(b.LastUsed)
+		}
+		return filepath.Dir(a.App.Path) == "/Applications" && filepath.Dir(b.App.Path) != "/Applications"
+	})
+
+	keeper := copies[0]
+	group := models.DuplicateAppGroup{
+		Name:     keeper.App.Name,
+		BundleID: keeper.App.BundleID,
+	}
+
+	for i := range copies {
+		if i == 0 {
+			copies[i].Keep = true
+			copies[i].Reason = "Newest stable or most recently used copy"
+			continue
+		}
+
+		copies[i].Reason = duplicateReason(copies[i], keeper)
+		group.Reclaimable += copies[i].App.Size
+	}
+
+	group.Copies = copies
+	return group
+}
+
+func duplicateReason(dup, keeper models.DuplicateAppCopy) string {
+	switch {
+	case isPrerelease(dup.App) && !isPrerelease(keeper.App):
+		return "Pre-release copy; the stable release is installed at " + keeper.App.Path
+	case compareVersions(dup.App.Version, keeper.App.Version) < 0:
+		return fmt.Sprintf("Older version %s (newest is %s)", dup.App.Version, keeper.App.Version)
+	case !dup.LastUsed.IsZero() && time.Since(dup.LastUsed) > 90*24*time.Hour:
+		return fmt.Sprintf("Same version, not used since %s", dup.LastUsed.Format("2006-01-02"))
+	default:
+		return "Same version installed at " + keeper.App.Path
+	}
+}
+
+// compareVersions compares dotted versions numerically; missing parts count as zero
+func compareVersions(a, b string) int {
+	pa := versionParts(a)
+	pb := versionParts(b)
+
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) []int {
+	fields := strings.FieldsFunc(version, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+
+	parts := make([]int, 0, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
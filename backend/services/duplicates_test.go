@@ -0,0 +1,110 @@
+package services
+
+import (
+	"testing"
+
+	"mole-wails/backend/models"
+)
+
+func TestRecommendDuplicateCopiesKeepsStableOverBeta(t *testing.T) {
+	tests := []struct {
+		name   string
+		copies []models.Application
+		keep   string
+	}{
+		{
+			name: "beta bundle name",
+			copies: []models.Application{
+				{Name: "Xcode-beta", Path: "/Applications/Xcode-beta.app", Version: "16.1", BundleID: "com.apple.dt.Xcode"},
+				{Name: "Xcode", Path: "/Applications/Xcode.app", Version: "16.0", BundleID: "com.apple.dt.Xcode"},
+			},
+			keep: "/Applications/Xcode.app",
+		},
+		{
+			name: "release candidate version",
+			copies: []models.Application{
+				{Name: "Foo", Path: "/Applications/Foo.app", Version: "2.0 RC1"},
+				{Name: "Foo", Path: "/Users/u/Applications/Foo.app", Version: "1.9"},
+			},
+			keep: "/Users/u/Applications/Foo.app",
+		},
+		{
+			name: "newest stable wins",
+			copies: []models.Application{
+				{Name: "Bar", Path: "/Users/u/Applications/Bar.app", Version: "1.2"},
+				{Name: "Bar", Path: "/Applications/Bar.app", Version: "1.10"},
+			},
+			keep: "/Applications/Bar.app",
+		},
+		{
+			name: "only betas keep the newest",
+			copies: []models.Application{
+				{Name: "Baz Beta", Path: "/Applications/Baz Beta.app", Version: "3.0b1"},
+				{Name: "Baz Beta", Path: "/Applications/Old/Baz Beta.app", Version: "3.0b2"},
+			},
+			keep: "/Applications/Old/Baz Beta.app",
+		},
+	}
+
+	for _, tt := range tests {
+		copies := make([]models.DuplicateAppCopy, len(tt.copies))
+		for i, app := range tt.copies {
+			copies[i] = models.DuplicateAppCopy{App: app}
+		}
+
+		group := recommendDuplicateCopies(copies)
+
+		if !group.Copies[0].Keep || group.Copies[0].App.Path != tt.keep {
+			t.Fatalf("%s: kept %s, want %s", tt.name, group.Copies[0].App.Path, tt.keep)
+		}
+		for _, c := range group.Copies[1:] {
+			if c.Keep {
+				t.Fatalf("%s: more than one copy kept", tt.name)
+			}
+		}
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	tests := []struct {
+		app  models.Application
+		want bool
+	}{
+		{models.Application{Name: "Xcode-beta", Path: "/Applications/Xcode-beta.app"}, true},
+		{models.Application{Name: "Safari Technology Preview"}, true},
+		{models.Application{Name: "Foo", Version: "16.0b3"}, true},
+		{models.Application{Name: "Foo", Version: "2.0-rc.1"}, true},
+		{models.Application{Name: "Arc", Path: "/Applications/Arc.app", Version: "1.2.3"}, false},
+		{models.Application{Name: "Betaflight Configurator", Version: "10.9"}, false},
+	}
+	for _, tt := range tests {
+		if got := isPrerelease(tt.app); got != tt.want {
+			t.Fatalf("%s %s: got %v, want %v", tt.app.Name, tt.app.Version, got, tt.want)
+		}
+	}
+}
+
+func TestGroupDuplicateAppsRequiresMatchingBundleIDs(t *testing.T) {
+	apps := []models.Application{
+		{Name: "Photoshop 2023", BundleID: "com.adobe.Photoshop2023"},
+		{Name: "Photoshop 2024", BundleID: "com.adobe.Photoshop2024"},
+		{Name: "Xcode", BundleID: "com.apple.dt.Xcode"},
+		{Name: "Xcode-beta", BundleID: "com.apple.dt.Xcode"},
+		{Name: "Tool", BundleID: "com.example.tool"},
+		{Name: "Tool copy", BundleID: "com.other.tool"},
+		{Name: "Script", BundleID: ""},
+		{Name: "Script copy", BundleID: ""},
+	}
+
+	groups := groupDuplicateApps(apps)
+
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d: %v", len(groups), groups)
+	}
+	if groups[0][0].Name != "Xcode" || len(groups[0]) != 2 {
+		t.Fatalf("unexpected first group: %v", groups[0])
+	}
+	if groups[1][0].Name != "Script" || len(groups[1]) != 2 {
+		t.Fatalf("unexpected second group: %v", groups[1])
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// inventoryRecord is one row of an exported application inventory
+type inventoryRecord struct {
+	Name     string `json:"name"`
+	BundleID string `json:"bundleId"`
+	Version  string `json:"version"`
+	Source   string `json:"source"`
+	Size     int64  `json:"size"`
+}
+
+// ExportInventory writes the installed applications as json, csv or markdown.
+// An empty destination writes into ~/Downloads; the written path is returned.
+func (s *UninstallService) ExportInventory(format, destination string) (string, error) {
+	format = strings.ToLower(format)
+	ext, ok := map[string]string{"json": "json", "csv": "csv", "markdown": "md", "md": "md"}[format]
+	if !ok {
+		return "", fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	apps, err := s.ScanApplications(false)
+	if err != nil {
+		return "", fmt.Errorf("failed to scan applications: %w", err)
+	}
+
+	records := inventoryRecords(apps)
+
+	var data []byte
+	switch ext {
+	case "json":
+		data, err = json.MarshalIndent(records, "", "  ")
+	case "csv":
+		data, err = renderInventoryCSV(records)
+	case "md":
+		data = renderInventoryMarkdown(records)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to render inventory: %w", err)
+	}
+
+	return writeExport(destination, "installed-apps."+ext, data)
+}
+
+// GenerateBrewfile writes a Brewfile for the cask-installed applications
+func (s *UninstallService) GenerateBrewfile(destination string) (string, error) {
+	apps, err := s.ScanApplications(false)
+	if err != nil {
+		return "", fmt.Errorf("failed to scan applications: %w", err)
+	}
+
+	return writeExport(destination, "Brewfile", renderBrewfile(apps))
+}
+
+// Helper functions
+
+func inventoryRecords(apps []models.Application) []inventoryRecord {
+	records := make([]inventoryRecord, 0, len(apps))
+	for _, app := range apps {
+		source := app.Source
+		if source == "" {
+			source = AppSourceManual
+		}
+		records = append(records, inventoryRecord{
+			Name:     app.Name,
+			BundleID: app.BundleID,
+			Version:  app.Version,
+			Source:   source,
+			Size:     app.Size,
+		})
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return strings.ToLower(records[i].Name) < strings.ToLower(records[j].Name)
+	})
+	return records
+}
+
+func renderInventoryCSV(records []inventoryRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	if err := writer.Write([]string{"name", "bundle_id", "version", "source", "size_bytes"}); err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if err := writer.Write([]string{r.Name, r.BundleID, r.Version, r.Source, strconv.FormatInt(r.Size, 10)}); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+func renderInventoryMarkdown(records []inventoryRecord) []byte {
+	var buf bytes.Buffer
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	fmt.Fprintf(&buf, "# Installed Applications\n\nGenerated %s, %d apps.\n\n", time.Now().Format("2006-01-02"), len(records))
+	buf.WriteString("| Name | Bundle ID | Version | Source | Size |\n")
+	buf.WriteString("|------|-----------|---------|--------|-----:|\n")
+	for _, r := range records {
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s |\n",
+			escape.Replace(r.Name), escape.Replace(r.BundleID), escape.Replace(r.Version), r.Source, formatBytes(r.Size))
+	}
+
+	return buf.Bytes()
+}
+
+// renderBrewfile lists the cask-installed apps, preceded by the third-party
+// taps they come from so `brew bundle` can resolve them on a new machine
+func renderBrewfile(apps []models.Application) []byte {
+	taps := make(map[string]bool)
+	casks := make(map[string]bool)
+	for _, app := range apps {
+		if app.Source != AppSourceCask || app.CaskToken == "" {
+			continue
+		}
+		if app.CaskTap == "" || app.CaskTap == officialCaskTap {
+			casks[app.CaskToken] = true
+			continue
+		}
+		taps[app.CaskTap] = true
+		casks[app.CaskTap+"/"+app.CaskToken] = true
+	}
+
+	var buf bytes.Buffer
+	for _, tap := range sortedKeys(taps) {
+		fmt.Fprintf(&buf, "tap %q\n", tap)
+	}
+	if len(taps) > 0 {
+		buf.WriteString("\n")
+	}
+	for _, cask := range sortedKeys(casks) {
+		fmt.Fprintf(&buf, "cask %q\n", cask)
+	}
+	return buf.Bytes()
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func writeExport(destination, defaultName string, data []byte) (string, error) {
+	if destination == "" {
+		destination = filepath.Join(os.Getenv("HOME"), "Downloads", defaultName)
+	}
+	if !filepath.IsAbs(destination) {
+		return "", fmt.Errorf("path must be absolute: %s", destination)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	if err := os.WriteFile(destination, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write export: %w", err)
+	}
+	return destination, nil
+}
+
+// formatBytes formats bytes into a human-readable size
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+func TestRenderBrewfile(t *testing.T) {
+	apps := []models.Application{
+		{Name: "Firefox", Source: AppSourceCask, CaskToken: "firefox", CaskTap: "homebrew/cask"},
+		{Name: "Docker", Source: AppSourceCask, CaskToken: "docker"},
+		{Name: "Hammerspoon", Source: AppSourceCask, CaskToken: "hammerspoon", CaskTap: "example/tools"},
+		{Name: "Launcher", Source: AppSourceCask, CaskToken: "launcher", CaskTap: "example/tools"},
+		{Name: "Notes", Source: AppSourceCask, CaskToken: "notes", CaskTap: "acme/apps"},
+		// A cask with two apps is listed once
+		{Name: "Firefox Helper", Source: AppSourceCask, CaskToken: "firefox", CaskTap: "homebrew/cask"},
+		{Name: "Pages", Source: AppSourceManual},
+		{Name: "Broken", Source: AppSourceCask},
+	}
+
+	want := `tap "acme/apps"
+tap "example/tools"
+
+cask "acme/apps/notes"
+cask "docker"
+cask "example/tools/hammerspoon"
+cask "example/tools/launcher"
+cask "firefox"
+`
+	if got := string(renderBrewfile(apps)); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	official := []models.Application{{Name: "Firefox", Source: AppSourceCask, CaskToken: "firefox", CaskTap: "homebrew/cask"}}
+	if got := string(renderBrewfile(official)); got != "cask \"firefox\"\n" {
+		t.Fatalf("got %q for official casks only", got)
+	}
+}
+
+func TestInventoryRecords(t *testing.T) {
+	records := inventoryRecords([]models.Application{
+		{Name: "zoom", BundleID: "us.zoom.xos", Version: "6.1", Source: AppSourceCask, Size: 2048},
+		{Name: "Arc", BundleID: "company.thebrowser.Browser", Size: 100},
+	})
+	if len(records) != 2 || records[0].Name != "Arc" || records[1].Name != "zoom" {
+		t.Fatalf("records not sorted by name: %+v", records)
+	}
+	if records[0].Source != AppSourceManual {
+		t.Fatalf("missing source should default to %q, got %q", AppSourceManual, records[0].Source)
+	}
+}
+
+func TestRenderInventoryCSV(t *testing.T) {
+	records := []inventoryRecord{
+		{Name: "Arc", BundleID: "company.thebrowser.Browser", Version: "1.2", Source: AppSourceManual, Size: 100},
+		{Name: `Foo, "Pro"`, BundleID: "com.example.foo", Source: AppSourceCask, Size: 2048},
+	}
+	data, err := renderInventoryCSV(records)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "name,bundle_id,version,source,size_bytes\n" +
+		"Arc,company.thebrowser.Browser,1.2," + AppSourceManual + ",100\n" +
+		`"Foo, ""Pro""",com.example.foo,,` + AppSourceCask + ",2048\n"
+	if string(data) != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", data, want)
+	}
+}
+
+func TestRenderInventoryMarkdown(t *testing.T) {
+	records := []inventoryRecord{
+		{Name: "Arc", BundleID: "company.thebrowser.Browser", Version: "1.2", Source: AppSourceManual, Size: 100},
+		{Name: "A|B\nC", BundleID: "com.example.ab", Source: AppSourceCask, Size: 3 * 1024 * 1024},
+	}
+	lines := strings.Split(string(renderInventoryMarkdown(records)), "\n")
+
+	want := []string{
+		"# Installed Applications",
+		"",
+		"Generated " + time.Now().Format("2006-01-02") + ", 2 apps.",
+		"",
+		"| Name | Bundle ID | Version | Source | Size |",
+		"|------|-----------|---------|--------|-----:|",
+		"| Arc | company.thebrowser.Browser | 1.2 | " + AppSourceManual + " | 100 B |",
+		`| A\|B C | com.example.ab |  | ` + AppSourceCask + " | 3.0 MB |",
+		"",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(want), strings.Join(lines, "\n"))
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("line %d: got %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
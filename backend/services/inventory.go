@@ -0,0 +1,95 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+const (
+	inventoryCacheFile    = "app_inventory.json"
+	inventoryCacheVersion = 5
+)
+
+// inventoryEntry is a measured application together with the timestamps
+// used to decide whether it has to be measured again
+type inventoryEntry struct {
+	App           models.Application `json:"app"`
+	BundleModTime time.Time          `json:"bundleModTime"`
+	PlistModTime  time.Time          `json:"plistModTime"`
+}
+
+type inventoryFile struct {
+	Version       int                       `json:"version"`
+	Apps          map[string]inventoryEntry `json:"apps"`
+	Casks         map[string]caskInfo       `json:"casks"`         // By app bundle name, see brew.go
+	CaskroomStamp map[string]time.Time      `json:"caskroomStamp"` // When Casks was read
+}
+
+// fresh reports whether a cached entry still matches the bundle on disk
+func (e inventoryEntry) fresh(bundleModTime, plistModTime time.Time) bool {
+	return e.BundleModTime.Equal(bundleModTime) && e.PlistModTime.Equal(plistModTime)
+}
+
+func getInventoryCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	cacheDir := filepath.Join(home, ".cache", "mole")
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, inventoryCacheFile), nil
+}
+
+func loadInventory() inventoryFile {
+	empty := inventoryFile{Apps: map[string]inventoryEntry{}}
+
+	cachePath, err := getInventoryCachePath()
+	if err != nil {
+		return empty
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return empty
+	}
+
+	var inventory inventoryFile
+	if err := json.Unmarshal(data, &inventory); err != nil || inventory.Version != inventoryCacheVersion || inventory.Apps == nil {
+		return empty
+	}
+
+	return inventory
+}
+
+func saveInventory(inventory inventoryFile) error {
+	cachePath, err := getInventoryCachePath()
+	if err != nil {
+		return err
+	}
+
+	inventory.Version = inventoryCacheVersion
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := cachePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, cachePath)
+}
+
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
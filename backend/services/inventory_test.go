@@ -0,0 +1,150 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// fakeCandidate is an app bundle with an Info.plist whose measurements are counted
+type fakeCandidate struct {
+	bundle   string
+	plist    string
+	measured int
+}
+
+func newFakeCandidate(t *testing.T, dir, name string, mtime time.Time) *fakeCandidate {
+	t.Helper()
+	bundle := filepath.Join(dir, name+".app")
+	plist := filepath.Join(bundle, "Contents", "Info.plist")
+	if err := os.MkdirAll(filepath.Dir(plist), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(plist, []byte("plist"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := &fakeCandidate{bundle: bundle, plist: plist}
+	c.touch(t, c.plist, mtime)
+	c.touch(t, c.bundle, mtime)
+	return c
+}
+
+func (c *fakeCandidate) touch(t *testing.T, path string, mtime time.Time) {
+	t.Helper()
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *fakeCandidate) candidate() appCandidate {
+	return appCandidate{
+		path:     c.bundle,
+		metaPath: c.plist,
+		measure: func() models.Application {
+			c.measured++
+			return models.Application{Name: filepath.Base(c.bundle), Path: c.bundle, Size: int64(c.measured)}
+		},
+	}
+}
+
+func TestMeasureCandidatesInvalidation(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+
+	replaced := newFakeCandidate(t, dir, "Replaced", base)
+	updated := newFakeCandidate(t, dir, "Updated", base)
+	unchanged := newFakeCandidate(t, dir, "Unchanged", base)
+	all := []*fakeCandidate{replaced, updated, unchanged}
+
+	candidates := func() []appCandidate {
+		var list []appCandidate
+		for _, c := range all {
+			list = append(list, c.candidate())
+		}
+		return list
+	}
+
+	s := &UninstallService{}
+	inventory, apps := s.measureCandidates(candidates(), inventoryFile{Apps: map[string]inventoryEntry{}})
+	if len(apps) != 3 || len(inventory) != 3 {
+		t.Fatalf("first scan: got %d apps and %d entries, want 3", len(apps), len(inventory))
+	}
+	for _, c := range all {
+		if c.measured != 1 {
+			t.Fatalf("%s measured %d times on the first scan", c.bundle, c.measured)
+		}
+	}
+
+	// A replaced bundle changes the bundle mtime, an update only the Info.plist
+	replaced.touch(t, replaced.bundle, base.Add(time.Hour))
+	updated.touch(t, updated.plist, base.Add(time.Hour))
+
+	inventory, apps = s.measureCandidates(candidates(), inventoryFile{Apps: inventory})
+	want := map[*fakeCandidate]int{replaced: 2, updated: 2, unchanged: 1}
+	for c, n := range want {
+		if c.measured != n {
+			t.Fatalf("%s measured %d times, want %d", c.bundle, c.measured, n)
+		}
+	}
+	for _, app := range apps {
+		if app.Path == unchanged.bundle && app.Size != 1 {
+			t.Fatalf("unchanged app was not served from the cache: %+v", app)
+		}
+		if app.LastModified.IsZero() || app.Age == "" {
+			t.Fatalf("cached app lacks its dates: %+v", app)
+		}
+	}
+
+	// Removed apps drop out of the inventory
+	all = all[:2]
+	inventory, _ = s.measureCandidates(candidates(), inventoryFile{Apps: inventory})
+	if _, ok := inventory[unchanged.bundle]; ok || len(inventory) != 2 {
+		t.Fatalf("removed app still in inventory: %v", inventory)
+	}
+}
+
+func TestInventoryRoundTrip(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	if inventory := loadInventory(); inventory.Apps == nil || len(inventory.Apps) != 0 {
+		t.Fatalf("missing cache: got %+v", inventory)
+	}
+
+	mtime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	saved := inventoryFile{
+		Apps: map[string]inventoryEntry{
+			"/Applications/Example.app": {
+				App:           models.Application{Name: "Example", BundleID: "com.example.app"},
+				BundleModTime: mtime,
+				PlistModTime:  mtime,
+			},
+		},
+	}
+	if err := saveInventory(saved); err != nil {
+		t.Fatalf("failed to save inventory: %v", err)
+	}
+
+	loaded := loadInventory()
+	entry, ok := loaded.Apps["/Applications/Example.app"]
+	if !ok || entry.App.BundleID != "com.example.app" || !entry.fresh(mtime, mtime) {
+		t.Fatalf("unexpected inventory: %+v", loaded)
+	}
+	if entry.fresh(mtime, mtime.Add(time.Second)) || entry.fresh(mtime.Add(time.Second), mtime) {
+		t.Fatal("entry should be stale when either mtime changes")
+	}
+
+	// Caches written by another version are ignored
+	path, err := getInventoryCachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"version":1,"apps":{"/Applications/Old.app":{}}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if inventory := loadInventory(); len(inventory.Apps) != 0 {
+		t.Fatalf("stale cache version was used: %+v", inventory)
+	}
+}
@@ -1,26 +1,36 @@
 package services
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
+	"mole-wails/backend/status"
 )
 
+// metricsCollector is the part of status.Collector the impact measurement reads
+type metricsCollector interface {
+	Collect() (status.MetricsSnapshot, error)
+}
+
 type OptimizeService struct {
 	scriptsPath string
 	ctx         context.Context
+	broker      *privileged.Broker
+	collector   metricsCollector
 }
 
-func NewOptimizeService(scriptsPath string) *OptimizeService {
+func NewOptimizeService(scriptsPath string, broker *privileged.Broker) *OptimizeService {
 	return &OptimizeService{
 		scriptsPath: scriptsPath,
+		broker:      broker,
+		collector:   status.NewCollector(),
 	}
 }
 
@@ -28,111 +38,194 @@ func (s *OptimizeService) SetContext(ctx context.Context) {
 	s.ctx = ctx
 }
 
-// GetTasks returns available optimization tasks
+// GetTasks returns available optimization tasks; whitelisted tasks are disabled
 func (s *OptimizeService) GetTasks() ([]models.OptimizationTask, error) {
-	tasks := []models.OptimizationTask{
-		{
-			ID:           "rebuild_caches",
-			Name:         "Rebuild system databases and clear caches",
-			Description:  "Rebuilds system-level caches for improved performance",
-			Enabled:      true,
-			RequiresSudo: true,
-		},
-		{
-			ID:           "reset_network",
-			Name:         "Reset network services",
-			Description:  "Resets network configuration",
-			Enabled:      true,
-			RequiresSudo: true,
-		},
-		{
-			ID:           "refresh_ui",
-			Name:         "Refresh Finder and Dock",
-			Description:  "Restarts Finder and Dock for a fresh UI",
-			Enabled:      true,
-			RequiresSudo: false,
-		},
-		{
-			ID:           "clean_logs",
-			Name:         "Clean diagnostic and crash logs",
-			Description:  "Removes old system logs",
-			Enabled:      true,
-			RequiresSudo: true,
-		},
-		{
-			ID:           "restart_pager",
-			Name:         "Remove swap files and restart dynamic pager",
-			Description:  "Clears swap files and restarts paging",
-			Enabled:      true,
-			RequiresSudo: true,
-		},
-		{
-			ID:           "rebuild_services",
-			Name:         "Rebuild launch services and spotlight index",
-			Description:  "Rebuilds system service databases",
-			Enabled:      true,
-			RequiresSudo: true,
-		},
+	whitelist, err := s.loadWhitelistSet()
+	if err != nil {
+		return nil, err
+	}
+
+	registry := optimizeTaskRegistry()
+
+	tasks := make([]models.OptimizationTask, 0, len(registry))
+	for _, def := range registry {
+		task := def.task
+		task.Enabled = !whitelist[task.ID]
+		if !task.Enabled {
+			task.DisabledReason = whitelistedReason
+		}
+		tasks = append(tasks, task)
 	}
 
 	return tasks, nil
 }
 
-// ExecuteOptimizations runs selected optimization tasks
+// ExecuteOptimizations runs the selected optimization tasks in registry order.
+// A failing task does not stop the others; failures are collected in the result.
 func (s *OptimizeService) ExecuteOptimizations(taskIDs []string) error {
-	scriptPath := filepath.Join(s.scriptsPath, "bin", "optimize.sh")
-
-	cmd := exec.Command("/bin/bash", scriptPath)
-
-	stdout, err := cmd.StdoutPipe()
+	whitelist, err := s.loadWhitelistSet()
 	if err != nil {
-		return fmt.Errorf("failed to create stdout pipe: %w", err)
+		return err
 	}
 
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("failed to start optimization: %w", err)
+	selected := make(map[string]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		selected[id] = true
 	}
 
-	// Stream progress
-	scanner := bufio.NewScanner(stdout)
-	totalTasks := len(taskIDs)
-	currentTask := 0
+	var defs []optimizeTaskDef
+	for _, def := range optimizeTaskRegistry() {
+		if selected[def.task.ID] {
+			defs = append(defs, def)
+			delete(selected, def.task.ID)
+		}
+	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
+	result := models.OptimizeResult{
+		Tasks:   []models.OptimizeTaskEvent{},
+		Skipped: []string{},
+		Errors:  []string{},
+	}
+	for _, id := range taskIDs {
+		if selected[id] {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: unknown optimization task", id))
+			delete(selected, id)
+		}
+	}
+
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
 
-		if strings.Contains(line, "✓") {
-			currentTask++
-			percent := (currentTask * 100) / totalTasks
+	startedAt := time.Now()
+	probes := taskProbes(defs)
+	before := s.measureImpact(ctx, probes)
 
-			progress := models.OptimizeProgress{
-				Message: line,
-				Percent: percent,
-			}
+	for i, def := range defs {
+		var event models.OptimizeTaskEvent
+		if whitelist[def.task.ID] {
+			event = s.skipTask(def, whitelistedReason)
+		} else {
+			event = s.runTask(ctx, def)
+		}
+		result.Tasks = append(result.Tasks, event)
 
-			if s.ctx != nil {
-				runtime.EventsEmit(s.ctx, "optimize:progress", progress)
-			}
+		switch event.Status {
+		case TaskStatusCompleted:
+			result.TasksCompleted++
+		case TaskStatusSkipped:
+			result.Skipped = append(result.Skipped, def.task.ID)
+		default:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", def.task.ID, event.Error))
 		}
+
+		s.emit("optimize:progress", models.OptimizeProgress{
+			Task:    def.task.Name,
+			Message: fmt.Sprintf("%s %s", def.task.Name, event.Status),
+			Percent: (i + 1) * 100 / len(defs),
+		})
 	}
 
-	if err := cmd.Wait(); err != nil {
-		return fmt.Errorf("optimization failed: %w", err)
+	after := s.measureImpact(ctx, probes)
+	result.Impact = diffImpact(before, after, probes)
+
+	// History is best effort; a failed write must not fail the run
+	_ = appendOptimizeHistory(models.OptimizeHistoryEntry{
+		StartedAt:      startedAt,
+		TaskIDs:        taskIDs,
+		TasksCompleted: result.TasksCompleted,
+		Skipped:        result.Skipped,
+		Errors:         result.Errors,
+		Impact:         result.Impact,
+	})
+
+	s.emit("optimize:complete", result)
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d of %d optimization tasks failed", len(result.Errors), len(taskIDs))
 	}
+	return nil
+}
 
-	result := models.OptimizeResult{
-		TasksCompleted: currentTask,
+// runTask executes one registry entry and reports its start and finish
+func (s *OptimizeService) runTask(ctx context.Context, def optimizeTaskDef) models.OptimizeTaskEvent {
+	event := models.OptimizeTaskEvent{
+		TaskID: def.task.ID,
+		Name:   def.task.Name,
+		Status: TaskStatusRunning,
+		Output: []string{},
 	}
+	s.emit("optimize:task-start", event)
 
-	if s.ctx != nil {
-		runtime.EventsEmit(s.ctx, "optimize:complete", result)
+	tr := &taskRun{ctx: ctx, broker: s.broker}
+	start := time.Now()
+	err := def.run(tr)
+
+	event.DurationMs = time.Since(start).Milliseconds()
+	if tr.output != nil {
+		event.Output = tr.output
+	}
+	if err != nil {
+		event.Status = TaskStatusFailed
+		event.Error = err.Error()
+	} else {
+		event.Status = TaskStatusCompleted
 	}
 
-	return nil
+	s.emit("optimize:task-finish", event)
+	return event
+}
+
+// skipTask reports a selected task that policy prevents from running
+func (s *OptimizeService) skipTask(def optimizeTaskDef, reason string) models.OptimizeTaskEvent {
+	event := models.OptimizeTaskEvent{
+		TaskID: def.task.ID,
+		Name:   def.task.Name,
+		Status: TaskStatusSkipped,
+		Output: []string{},
+		Reason: reason,
+	}
+	s.emit("optimize:task-finish", event)
+	return event
+}
+
+func (s *OptimizeService) emit(name string, data interface{}) {
+	if s.ctx != nil {
+		runtime.EventsEmit(s.ctx, name, data)
+	}
 }
 
 // GetWhitelist returns optimization tasks in whitelist
 func (s *OptimizeService) GetWhitelist() ([]string, error) {
+	return readOptimizeWhitelist()
+}
+
+// loadWhitelistSet returns the whitelisted task IDs as a lookup set
+func (s *OptimizeService) loadWhitelistSet() (map[string]bool, error) {
+	return loadOptimizeWhitelistSet()
+}
+
+// UpdateWhitelist updates optimization whitelist
+func (s *OptimizeService) UpdateWhitelist(tasks []string) error {
+	whitelistPath := filepath.Join(os.Getenv("HOME"), ".config", "mole", "optimize_whitelist")
+
+	configDir := filepath.Dir(whitelistPath)
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	content := strings.Join(tasks, "\n")
+	if err := os.WriteFile(whitelistPath, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write whitelist: %w", err)
+	}
+
+	return nil
+}
+
+// readOptimizeWhitelist reads ~/.config/mole/optimize_whitelist, which also
+// protects system checks from being reported
+func readOptimizeWhitelist() ([]string, error) {
 	whitelistPath := filepath.Join(os.Getenv("HOME"), ".config", "mole", "optimize_whitelist")
 
 	data, err := os.ReadFile(whitelistPath)
@@ -155,19 +248,15 @@ func (s *OptimizeService) GetWhitelist() ([]string, error) {
 	return whitelist, nil
 }
 
-// UpdateWhitelist updates optimization whitelist
-func (s *OptimizeService) UpdateWhitelist(tasks []string) error {
-	whitelistPath := filepath.Join(os.Getenv("HOME"), ".config", "mole", "optimize_whitelist")
-
-	configDir := filepath.Dir(whitelistPath)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+func loadOptimizeWhitelistSet() (map[string]bool, error) {
+	whitelist, err := readOptimizeWhitelist()
+	if err != nil {
+		return nil, err
 	}
 
-	content := strings.Join(tasks, "\n")
-	if err := os.WriteFile(whitelistPath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write whitelist: %w", err)
+	set := make(map[string]bool, len(whitelist))
+	for _, id := range whitelist {
+		set[id] = true
 	}
-
-	return nil
+	return set, nil
 }
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"mole-wails/backend/models"
+)
+
+const (
+	optimizeHistoryFile  = "optimize_history.json"
+	optimizeHistoryLimit = 100
+	dnsProbeHost         = "apple.com"
+	dnsProbeTimeout      = 3 * time.Second
+	probeTimeout         = 10 * time.Second
+)
+
+// impactProbe is a task-specific reading taken around a run
+type impactProbe struct {
+	id      string
+	label   string
+	unit    string
+	measure func(ctx context.Context) (float64, error)
+}
+
+// taskProbes returns the probes for the tasks that will run
+func taskProbes(defs []optimizeTaskDef) []impactProbe {
+	seen := make(map[string]bool)
+	var probes []impactProbe
+	for _, def := range defs {
+		for _, probe := range def.probes {
+			if !seen[probe.id] {
+				seen[probe.id] = true
+				probes = append(probes, probe)
+			}
+		}
+	}
+	return probes
+}
+
+// measureImpact samples system metrics and the given probes. A collector error
+// only means some metrics are missing, so the fields it did fill are still used.
+func (s *OptimizeService) measureImpact(ctx context.Context, probes []impactProbe) models.OptimizeMeasurement {
+	m := models.OptimizeMeasurement{
+		TakenAt: time.Now(),
+		Probes:  make(map[string]float64),
+	}
+
+	snapshot, err := s.collector.Collect()
+	if err != nil && s.ctx != nil {
+		runtime.LogWarningf(s.ctx, "optimize: partial metrics for impact measurement: %v", err)
+	}
+
+	m.MemoryUsed = snapshot.Memory.Used
+	m.SwapUsed = snapshot.Memory.SwapUsed
+	m.Load1 = snapshot.CPU.Load1
+
+	// On APFS the writable data volume is the one that fills up
+	for _, mount := range []string{"/System/Volumes/Data", "/"} {
+		found := false
+		for _, d := range snapshot.Disks {
+			if d.Mount == mount && d.Total >= d.Used {
+				m.DiskFree = d.Total - d.Used
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	if ms, err := measureDNSResolve(ctx); err == nil {
+		m.DNSResolveMs = ms
+	}
+
+	for _, probe := range probes {
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		value, err := probe.measure(probeCtx)
+		cancel()
+		if err == nil {
+			m.Probes[probe.id] = value
+		}
+	}
+
+	return m
+}
+
+// diffImpact compares two measurements; lower is better except free disk space
+func diffImpact(before, after models.OptimizeMeasurement, probes []impactProbe) *models.OptimizeImpact {
+	impact := &models.OptimizeImpact{Before: before, After: after}
+
+	add := func(metric, label, unit string, b, a float64, higherIsBetter bool) {
+		delta := a - b
+		improved := delta < 0
+		if higherIsBetter {
+			improved = delta > 0
+		}
+		impact.Deltas = append(impact.Deltas, models.OptimizeImpactDelta{
+			Metric:   metric,
+			Label:    label,
+			Unit:     unit,
+			Before:   b,
+			After:    a,
+			Delta:    delta,
+			Improved: improved,
+		})
+	}
+
+	add("memory_used", "Memory used", "bytes", float64(before.MemoryUsed), float64(after.MemoryUsed), false)
+	add("swap_used", "Swap used", "bytes", float64(before.SwapUsed), float64(after.SwapUsed), false)
+	add("disk_free", "Disk free", "bytes", float64(before.DiskFree), float64(after.DiskFree), true)
+	add("load1", "Load average (1m)", "", before.Load1, after.Load1, false)
+	if before.DNSResolveMs > 0 && after.DNSResolveMs > 0 {
+		add("dns_resolve", "DNS resolve time", "ms", before.DNSResolveMs, after.DNSResolveMs, false)
+	}
+
+	for _, probe := range probes {
+		b, okBefore := before.Probes[probe.id]
+		a, okAfter := after.Probes[probe.id]
+		if okBefore && okAfter {
+			add(probe.id, probe.label, probe.unit, b, a, false)
+		}
+	}
+
+	return impact
+}
+
+// GetHistory returns past optimization runs, newest first
+func (s *OptimizeService) GetHistory() ([]models.OptimizeHistoryEntry, error) {
+	history, err := loadOptimizeHistory()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
+
+// Probes
+
+func measureDNSResolve(ctx context.Context) (float64, error) {
+	ctx, cancel := context.WithTimeout(ctx, dnsProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := net.DefaultResolver.LookupHost(ctx, dnsProbeHost); err != nil {
+		return 0, err
+	}
+	return float64(time.Since(start).Microseconds()) / 1000, nil
+}
+
+// pathSizeProbe measures the combined size of files below paths
+func pathSizeProbe(id, label string, paths ...string) impactProbe {
+	return impactProbe{
+		id:    id,
+		label: label,
+		unit:  "bytes",
+		measure: func(ctx context.Context) (float64, error) {
+			var total int64
+			for _, root := range paths {
+				_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+					if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					if err != nil || !d.Type().IsRegular() {
+						return nil
+					}
+					if info, err := d.Info(); err == nil {
+						total += info.Size()
+					}
+					return nil
+				})
+			}
+			return float64(total), ctx.Err()
+		},
+	}
+}
+
+// globCountProbe counts the files matching pattern
+func globCountProbe(id, label, pattern string) impactProbe {
+	return impactProbe{
+		id:    id,
+		label: label,
+		unit:  "files",
+		measure: func(ctx context.Context) (float64, error) {
+			matches, err := filepath.Glob(pattern)
+			return float64(len(matches)), err
+		},
+	}
+}
+
+// commandLinesProbe counts the non-empty output lines of a command
+func commandLinesProbe(id, label, unit, name string, args ...string) impactProbe {
+	return impactProbe{
+		id:    id,
+		label: label,
+		unit:  unit,
+		measure: func(ctx context.Context) (float64, error) {
+			output, err := exec.CommandContext(ctx, name, args...).Output()
+			if err != nil {
+				return 0, err
+			}
+			count := 0
+			for _, line := range strings.Split(string(output), "\n") {
+				if strings.TrimSpace(line) != "" {
+					count++
+				}
+			}
+			return float64(count), nil
+		},
+	}
+}
+
+// History
+
+func getOptimizeHistoryPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	configDir := filepath.Join(home, ".config", "mole")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, optimizeHistoryFile), nil
+}
+
+func loadOptimizeHistory() ([]models.OptimizeHistoryEntry, error) {
+	historyPath, err := getOptimizeHistoryPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate history: %w", err)
+	}
+
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.OptimizeHistoryEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var history []models.OptimizeHistoryEntry
+	if err := json.Unmarshal(data, &history); err != nil {
+		// A corrupt history file should not block optimizations
+		return []models.OptimizeHistoryEntry{}, nil
+	}
+	return history, nil
+}
+
+func appendOptimizeHistory(entry models.OptimizeHistoryEntry) error {
+	history, err := loadOptimizeHistory()
+	if err != nil {
+		return err
+	}
+
+	history = append(history, entry)
+	if len(history) > optimizeHistoryLimit {
+		history = history[len(history)-optimizeHistoryLimit:]
+	}
+
+	historyPath, err := getOptimizeHistoryPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := historyPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, historyPath)
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"mole-wails/backend/models"
+	"mole-wails/backend/status"
+)
+
+// fakeCollector returns a fixed snapshot together with an error, like a
+// collector that could only read some of the metrics
+type fakeCollector struct {
+	snapshot status.MetricsSnapshot
+	err      error
+}
+
+func (c fakeCollector) Collect() (status.MetricsSnapshot, error) {
+	return c.snapshot, c.err
+}
+
+func staticProbe(id string, value float64, err error) impactProbe {
+	return impactProbe{
+		id:    id,
+		label: id,
+		measure: func(context.Context) (float64, error) {
+			return value, err
+		},
+	}
+}
+
+func TestMeasureImpactPartialMetrics(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot status.MetricsSnapshot
+		diskFree uint64
+	}{
+		{
+			name: "data volume preferred",
+			snapshot: status.MetricsSnapshot{
+				Memory: status.MemoryStatus{Used: 100, SwapUsed: 5},
+				Disks: []status.DiskStatus{
+					{Mount: "/", Used: 10, Total: 50},
+					{Mount: "/System/Volumes/Data", Used: 60, Total: 100},
+				},
+			},
+			diskFree: 40,
+		},
+		{
+			name: "root volume",
+			snapshot: status.MetricsSnapshot{
+				Memory: status.MemoryStatus{Used: 100, SwapUsed: 5},
+				Disks:  []status.DiskStatus{{Mount: "/", Used: 10, Total: 50}},
+			},
+			diskFree: 40,
+		},
+		{
+			name: "disks missing",
+			snapshot: status.MetricsSnapshot{
+				Memory: status.MemoryStatus{Used: 100, SwapUsed: 5},
+			},
+			diskFree: 0,
+		},
+		{
+			name: "inconsistent disk",
+			snapshot: status.MetricsSnapshot{
+				Memory: status.MemoryStatus{Used: 100, SwapUsed: 5},
+				Disks:  []status.DiskStatus{{Mount: "/", Used: 60, Total: 50}},
+			},
+			diskFree: 0,
+		},
+	}
+
+	probes := []impactProbe{
+		staticProbe("swap_files", 3, nil),
+		staticProbe("arp_entries", 0, errors.New("arp not found")),
+	}
+	for _, tt := range tests {
+		s := &OptimizeService{collector: fakeCollector{snapshot: tt.snapshot, err: errors.New("gpu: unavailable")}}
+		m := s.measureImpact(context.Background(), probes)
+
+		// The collector error must not discard the metrics it did read
+		if m.MemoryUsed != 100 || m.SwapUsed != 5 || m.DiskFree != tt.diskFree {
+			t.Fatalf("%s: got memory=%d swap=%d disk=%d, want 100 5 %d", tt.name, m.MemoryUsed, m.SwapUsed, m.DiskFree, tt.diskFree)
+		}
+		if want := map[string]float64{"swap_files": 3}; !reflect.DeepEqual(m.Probes, want) {
+			t.Fatalf("%s: probes %v, want %v", tt.name, m.Probes, want)
+		}
+		if m.TakenAt.IsZero() {
+			t.Fatalf("%s: measurement has no time", tt.name)
+		}
+	}
+}
+
+func TestDiffImpact(t *testing.T) {
+	before := models.OptimizeMeasurement{
+		MemoryUsed:   1000,
+		SwapUsed:     200,
+		DiskFree:     5000,
+		Load1:        2.5,
+		DNSResolveMs: 0, // The lookup failed
+		Probes:       map[string]float64{"swap_files": 4, "arp_entries": 30},
+	}
+	after := models.OptimizeMeasurement{
+		MemoryUsed:   800,
+		SwapUsed:     200,
+		DiskFree:     6000,
+		Load1:        3,
+		DNSResolveMs: 12,
+		Probes:       map[string]float64{"swap_files": 1},
+	}
+	probes := []impactProbe{
+		staticProbe("swap_files", 0, nil),
+		staticProbe("arp_entries", 0, nil),
+	}
+
+	impact := diffImpact(before, after, probes)
+
+	type delta struct {
+		delta    float64
+		improved bool
+	}
+	got := make(map[string]delta)
+	for _, d := range impact.Deltas {
+		got[d.Metric] = delta{d.Delta, d.Improved}
+	}
+	// Readings missing on either side are left out instead of reported as changes
+	want := map[string]delta{
+		"memory_used": {delta: -200, improved: true},
+		"swap_used":   {delta: 0, improved: false},
+		"disk_free":   {delta: 1000, improved: true},
+		"load1":       {delta: 0.5, improved: false},
+		"swap_files":  {delta: -3, improved: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(impact.Before, before) || !reflect.DeepEqual(impact.After, after) {
+		t.Fatal("impact does not carry both measurements")
+	}
+}
+
+func TestTaskProbesDeduplicates(t *testing.T) {
+	shared := staticProbe("diagnostic_reports", 0, nil)
+	defs := []optimizeTaskDef{
+		{probes: []impactProbe{shared, staticProbe("swap_files", 0, nil)}},
+		{},
+		{probes: []impactProbe{shared}},
+	}
+
+	var ids []string
+	for _, probe := range taskProbes(defs) {
+		ids = append(ids, probe.id)
+	}
+	if want := []string{"diagnostic_reports", "swap_files"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("got %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
+)
+
+// Optimize task statuses reported in models.OptimizeTaskEvent.Status
+const (
+	TaskStatusRunning   = "running"
+	TaskStatusCompleted = "completed"
+	TaskStatusFailed    = "failed"
+	TaskStatusSkipped   = "skipped"
+)
+
+const whitelistedReason = "Protected by optimize whitelist"
+
+const optimizeStepTimeout = 2 * time.Minute
+
+// crashReportMaxAgeDays matches MOLE_CRASH_REPORT_AGE_DAYS in scripts/lib/core/base.sh
+const crashReportMaxAgeDays = 7
+
+const lsregisterPath = "/System/Library/Frameworks/CoreServices.framework/Frameworks/LaunchServices.framework/Support/lsregister"
+
+// optimizeTaskDef binds a task shown in the UI to the code that performs it
+type optimizeTaskDef struct {
+	task   models.OptimizationTask
+	run    func(tr *taskRun) error
+	probes []impactProbe
+}
+
+// taskRun collects the output of a single task execution
+type taskRun struct {
+	ctx    context.Context
+	broker *privileged.Broker
+	output []string
+}
+
+// optimizeTaskRegistry lists every task GetTasks exposes, in execution order
+func optimizeTaskRegistry() []optimizeTaskDef {
+	home := os.Getenv("HOME")
+	caches := filepath.Join(home, "Library", "Caches")
+
+	return []optimizeTaskDef{
+		{
+			task: models.OptimizationTask{
+				ID:           "rebuild_caches",
+				Name:         "Rebuild system databases and clear caches",
+				Description:  "Rebuilds system-level caches for improved performance",
+				RequiresSudo: true,
+			},
+			run: runRebuildCaches,
+			probes: []impactProbe{
+				pathSizeProbe("quicklook_cache", "QuickLook and icon caches",
+					filepath.Join(caches, "com.apple.QuickLook.thumbnailcache"),
+					filepath.Join(caches, "com.apple.iconservices.store"),
+					filepath.Join(caches, "com.apple.iconservices")),
+			},
+		},
+		{
+			task: models.OptimizationTask{
+				ID:           "reset_network",
+				Name:         "Reset network services",
+				Description:  "Resets network configuration",
+				RequiresSudo: true,
+			},
+			run: runResetNetwork,
+			probes: []impactProbe{
+				commandLinesProbe("arp_entries", "ARP cache entries", "entries", "arp", "-an"),
+			},
+		},
+		{
+			task: models.OptimizationTask{
+				ID:           "refresh_ui",
+				Name:         "Refresh Finder and Dock",
+				Description:  "Restarts Finder and Dock for a fresh UI",
+				RequiresSudo: false,
+			},
+			run: runRefreshUI,
+		},
+		{
+			task: models.OptimizationTask{
+				ID:           "clean_logs",
+				Name:         "Clean diagnostic and crash logs",
+				Description:  "Removes old system logs",
+				RequiresSudo: true,
+			},
+			run: runCleanLogs,
+			probes: []impactProbe{
+				pathSizeProbe("diagnostic_reports", "Diagnostic reports",
+					filepath.Join(home, "Library", "Logs", "DiagnosticReports")),
+			},
+		},
+		{
+			task: models.OptimizationTask{
+				ID:           "restart_pager",
+				Name:         "Remove swap files and restart dynamic pager",
+				Description:  "Clears swap files and restarts paging",
+				RequiresSudo: true,
+			},
+			run: runRestartPager,
+			probes: []impactProbe{
+				globCountProbe("swap_files", "Swap files", "/private/var/vm/swapfile*"),
+			},
+		},
+		{
+			task: models.OptimizationTask{
+				ID:           "rebuild_services",
+				Name:         "Rebuild launch services and spotlight index",
+				Description:  "Rebuilds system service databases",
+				RequiresSudo: true,
+			},
+			run: runRebuildServices,
+		},
+	}
+}
+
+// Task runners, ported from scripts/lib/optimize/tasks.sh
+
+func runRebuildCaches(tr *taskRun) error {
+	if err := tr.privileged(privileged.CmdFlushDNS); err != nil {
+		return err
+	}
+	tr.log("✓ DNS cache flushed")
+
+	_ = tr.command("qlmanage", "-r", "cache")
+	_ = tr.command("qlmanage", "-r")
+	tr.log("✓ QuickLook thumbnails refreshed")
+
+	caches := filepath.Join(os.Getenv("HOME"), "Library", "Caches")
+	for _, name := range []string{"com.apple.QuickLook.thumbnailcache", "com.apple.iconservices.store", "com.apple.iconservices"} {
+		target := filepath.Join(caches, name)
+		if _, err := os.Lstat(target); err != nil {
+			continue
+		}
+		if err := os.RemoveAll(target); err != nil {
+			tr.log(fmt.Sprintf("! Failed to remove %s: %v", target, err))
+			continue
+		}
+		tr.log("✓ Removed " + target)
+	}
+	return nil
+}
+
+func runResetNetwork(tr *taskRun) error {
+	if err := tr.privileged(privileged.CmdFlushDNS); err != nil {
+		return err
+	}
+	tr.log("✓ DNS cache flushed")
+
+	if err := tr.privileged(privileged.CmdFlushARP); err != nil {
+		return err
+	}
+	tr.log("✓ ARP cache rebuilt")
+	return nil
+}
+
+func runRefreshUI(tr *taskRun) error {
+	for _, app := range []string{"Finder", "Dock"} {
+		if err := tr.command("killall", app); err != nil {
+			return err
+		}
+		tr.log("✓ " + app + " restarted")
+	}
+	return nil
+}
+
+func runCleanLogs(tr *taskRun) error {
+	userReports := filepath.Join(os.Getenv("HOME"), "Library", "Logs", "DiagnosticReports")
+	cutoff := time.Now().AddDate(0, 0, -crashReportMaxAgeDays)
+	removed := removeOldReports(userReports, cutoff)
+	tr.log(fmt.Sprintf("✓ Removed %d old user diagnostic reports", removed))
+
+	if err := tr.privileged(privileged.CmdCleanDiagnostics); err != nil {
+		return err
+	}
+	tr.log("✓ Old system diagnostic reports removed")
+
+	if err := tr.privileged(privileged.CmdRotateLogs); err != nil {
+		return err
+	}
+	tr.log("✓ System logs rotated")
+	return nil
+}
+
+func runRestartPager(tr *taskRun) error {
+	if err := tr.privileged(privileged.CmdRestartPager); err != nil {
+		return err
+	}
+	tr.log("✓ Swap cache reset")
+	return nil
+}
+
+func runRebuildServices(tr *taskRun) error {
+	if err := tr.command(lsregisterPath, "-kill", "-r", "-domain", "local", "-domain", "system", "-domain", "user"); err != nil {
+		return err
+	}
+	tr.log("✓ Launch Services database rebuilt")
+
+	if err := tr.privileged(privileged.CmdReindexSpotlight); err != nil {
+		return err
+	}
+	tr.log("✓ Spotlight index rebuild scheduled")
+	return nil
+}
+
+// Helper functions
+
+func (tr *taskRun) log(line string) {
+	tr.output = append(tr.output, line)
+}
+
+// command runs a step as the current user
+func (tr *taskRun) command(name string, args ...string) error {
+	ctx, cancel := context.WithTimeout(tr.ctx, optimizeStepTimeout)
+	defer cancel()
+
+	step := filepath.Base(name)
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			tr.log(line)
+		}
+	}
+
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("%s timed out after %v", step, optimizeStepTimeout)
+		}
+		return fmt.Errorf("%s failed: %w", step, err)
+	}
+	return nil
+}
+
+// privileged runs an allow-listed root command through the session helper
+func (tr *taskRun) privileged(command string) error {
+	if tr.broker == nil {
+		return fmt.Errorf("%s requires administrator privileges", command)
+	}
+
+	res, err := tr.broker.Run(tr.ctx, privileged.Request{Command: command})
+	if err != nil {
+		return err
+	}
+
+	tr.output = append(tr.output, res.Output...)
+	if !res.OK {
+		return fmt.Errorf("%s: %s", command, res.Error)
+	}
+	return nil
+}
+
+// removeOldReports deletes the regular files directly in dir that were last
+// modified before cutoff, leaving recent reports and subdirectories alone
+func removeOldReports(dir string, cutoff time.Time) int {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, entry.Name())); err == nil {
+			removed++
+		}
+	}
+	return removed
+}
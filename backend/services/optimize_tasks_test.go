@@ -0,0 +1,66 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestRemoveOldReports(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -crashReportMaxAgeDays)
+
+	files := []struct {
+		name string
+		age  time.Duration
+	}{
+		{name: "old.ips", age: 10 * 24 * time.Hour},
+		{name: "older.crash", age: 30 * 24 * time.Hour},
+		{name: "recent.ips", age: time.Hour},
+		{name: "yesterday.diag", age: 24 * time.Hour},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		if err := os.WriteFile(path, []byte("report"), 0o644); err != nil {
+			t.Fatalf("failed to write %s: %v", f.name, err)
+		}
+		mtime := now.Add(-f.age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("failed to age %s: %v", f.name, err)
+		}
+	}
+	// Subdirectories such as Retired are not touched, however old
+	sub := filepath.Join(dir, "Retired")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatalf("failed to create subdirectory: %v", err)
+	}
+	old := now.AddDate(-1, 0, 0)
+	if err := os.Chtimes(sub, old, old); err != nil {
+		t.Fatalf("failed to age subdirectory: %v", err)
+	}
+
+	if got := removeOldReports(dir, cutoff); got != 2 {
+		t.Fatalf("removed %d reports, want 2", got)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read dir: %v", err)
+	}
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	sort.Strings(left)
+	if want := []string{"Retired", "recent.ips", "yesterday.diag"}; !reflect.DeepEqual(left, want) {
+		t.Fatalf("left %v, want %v", left, want)
+	}
+
+	if got := removeOldReports(filepath.Join(dir, "missing"), cutoff); got != 0 {
+		t.Fatalf("removed %d reports from a missing dir, want 0", got)
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeOptimizeWhitelist(t *testing.T, home, content string) {
+	t.Helper()
+	path := filepath.Join(home, ".config", "mole", "optimize_whitelist")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadOptimizeWhitelist(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	whitelist, err := readOptimizeWhitelist()
+	if err != nil || len(whitelist) != 0 {
+		t.Fatalf("missing whitelist: got %v, %v", whitelist, err)
+	}
+
+	writeOptimizeWhitelist(t, home, "# Keep the Dock alone\nrefresh_ui\n\n  clean_logs  \n#reset_network\n")
+	whitelist, err = readOptimizeWhitelist()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"refresh_ui", "clean_logs"}; !reflect.DeepEqual(whitelist, want) {
+		t.Fatalf("got %v, want %v", whitelist, want)
+	}
+}
+
+func TestGetTasksDisablesWhitelisted(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeOptimizeWhitelist(t, home, "refresh_ui\nclean_logs\n")
+
+	tasks, err := NewOptimizeService("", nil).GetTasks()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tasks) != len(optimizeTaskRegistry()) {
+		t.Fatalf("got %d tasks, want every registry entry", len(tasks))
+	}
+	for _, task := range tasks {
+		whitelisted := task.ID == "refresh_ui" || task.ID == "clean_logs"
+		if task.Enabled == whitelisted {
+			t.Fatalf("%s: enabled=%v, whitelisted=%v", task.ID, task.Enabled, whitelisted)
+		}
+		if whitelisted && task.DisabledReason != whitelistedReason {
+			t.Fatalf("%s: disabled reason %q", task.ID, task.DisabledReason)
+		}
+		if !whitelisted && task.DisabledReason != "" {
+			t.Fatalf("%s: unexpected disabled reason %q", task.ID, task.DisabledReason)
+		}
+	}
+}
+
+func TestExecuteOptimizationsSkipsWhitelisted(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	// Every selected task is whitelisted; they only need the privileged
+	// helper, which is absent, so a regression cannot touch the system
+	writeOptimizeWhitelist(t, home, "reset_network\nrestart_pager\n")
+
+	s := NewOptimizeService("", nil)
+	err := s.ExecuteOptimizations([]string{"restart_pager", "reset_network", "defrag_disk"})
+	if err == nil || !strings.Contains(err.Error(), "1 of 3") {
+		t.Fatalf("expected only the unknown task to fail, got %v", err)
+	}
+
+	history, err := s.GetHistory()
+	if err != nil {
+		t.Fatalf("failed to read history: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("got %d history entries, want 1", len(history))
+	}
+	entry := history[0]
+	// Skipped tasks follow registry order, not selection order
+	if want := []string{"reset_network", "restart_pager"}; !reflect.DeepEqual(entry.Skipped, want) {
+		t.Fatalf("skipped %v, want %v", entry.Skipped, want)
+	}
+	if entry.TasksCompleted != 0 {
+		t.Fatalf("%d tasks completed, want 0", entry.TasksCompleted)
+	}
+	if want := []string{"defrag_disk: unknown optimization task"}; !reflect.DeepEqual(entry.Errors, want) {
+		t.Fatalf("errors %v, want %v", entry.Errors, want)
+	}
+}
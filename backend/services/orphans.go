@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// orphanLocation describes a Library folder whose entries are named after bundle IDs
+type orphanLocation struct {
+	kind   string
+	path   string
+	suffix string
+}
+
+// protectedVendorPatterns mirrors SYSTEM_CRITICAL_BUNDLES and the vendor part of
+// DATA_PROTECTED_BUNDLES in scripts/lib/core/app_protection.sh
+var protectedVendorPatterns = []string{
+	"com.apple.*",
+	"com.nektony.*",
+	"com.macpaw.*",
+	"com.daisydiskapp.*",
+	"com.1password.*",
+	"com.agilebits.*",
+	"com.lastpass.*",
+	"com.dashlane.*",
+	"com.bitwarden.*",
+	"com.keepassx.*",
+	"org.keepassx.*",
+	"com.authy.*",
+	"com.yubico.*",
+	"com.jetbrains.*",
+}
+
+var reverseDNSPattern = regexp.MustCompile(`^[a-z]{2,63}\.[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)+$`)
+
+const (
+	// orphanAgeThreshold mirrors ORPHAN_AGE_THRESHOLD: data touched more
+	// recently may belong to an app that is still in use
+	orphanAgeThreshold = 60 * 24 * time.Hour
+	// installedAppDepth mirrors the find -maxdepth in scan_installed_apps
+	installedAppDepth  = 3
+	runningAppsTimeout = 5 * time.Second
+)
+
+type OrphanService struct {
+	uninstall *UninstallService
+	ctx       context.Context
+}
+
+func NewOrphanService(uninstall *UninstallService) *OrphanService {
+	return &OrphanService{
+		uninstall: uninstall,
+	}
+}
+
+func (s *OrphanService) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// ScanOrphans lists leftover data of apps that are no longer installed,
+// grouped by inferred vendor and app
+func (s *OrphanService) ScanOrphans() ([]models.OrphanVendorGroup, error) {
+	installed, err := s.installedBundleIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var items []models.OrphanedItem
+	for _, loc := range s.locations() {
+		items = append(items, s.orphansIn(loc, installed, now)...)
+	}
+
+	return groupOrphans(items), nil
+}
+
+// Helper functions
+
+// orphansIn lists the entries of loc named after a bundle that is neither
+// installed nor protected and was left untouched for orphanAgeThreshold
+func (s *OrphanService) orphansIn(loc orphanLocation, installed []string, now time.Time) []models.OrphanedItem {
+	entries, err := os.ReadDir(loc.path)
+	if err != nil {
+		return nil
+	}
+
+	var items []models.OrphanedItem
+	for _, entry := range entries {
+		name := entry.Name()
+		if loc.suffix != "" {
+			if !strings.HasSuffix(name, loc.suffix) {
+				continue
+			}
+			name = strings.TrimSuffix(name, loc.suffix)
+		}
+
+		if !reverseDNSPattern.MatchString(name) {
+			continue
+		}
+		if isProtectedBundle(name) || isInstalledBundle(name, installed) {
+			continue
+		}
+
+		itemPath := filepath.Join(loc.path, entry.Name())
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < orphanAgeThreshold {
+			continue
+		}
+
+		size, _ := s.uninstall.getDirSize(itemPath)
+		if size == 0 {
+			continue
+		}
+
+		items = append(items, models.OrphanedItem{
+			Path:         itemPath,
+			BundleID:     name,
+			Kind:         loc.kind,
+			Size:         size,
+			LastModified: info.ModTime(),
+		})
+	}
+	return items
+}
+
+// installedBundleIDs mirrors scan_installed_apps in scripts/lib/clean/apps.sh:
+// bundles nested up to three levels in the Applications folders, running
+// apps and launch agents all count as installed
+func (s *OrphanService) installedBundleIDs() ([]string, error) {
+	apps, err := s.uninstall.ScanApplications(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan applications: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var installed []string
+	add := func(bundleID string) {
+		id := strings.ToLower(strings.TrimSpace(bundleID))
+		if id == "" || id == "unknown" || seen[id] {
+			return
+		}
+		seen[id] = true
+		installed = append(installed, id)
+	}
+
+	for _, app := range apps {
+		add(app.BundleID)
+	}
+
+	home := os.Getenv("HOME")
+	for _, dir := range []string{"/Applications", "/System/Applications", filepath.Join(home, "Applications")} {
+		for _, appPath := range findAppBundles(dir, installedAppDepth) {
+			bundleID, _ := s.uninstall.readBundleInfo(appPath)
+			add(bundleID)
+		}
+	}
+
+	for _, bundleID := range runningBundleIDs() {
+		add(bundleID)
+	}
+
+	for _, dir := range []string{filepath.Join(home, "Library", "LaunchAgents"), "/Library/LaunchAgents"} {
+		agents, _ := filepath.Glob(filepath.Join(dir, "*.plist"))
+		for _, agent := range agents {
+			add(strings.TrimSuffix(filepath.Base(agent), ".plist"))
+		}
+	}
+
+	return installed, nil
+}
+
+// findAppBundles lists .app bundles at most depth levels below dir
+func findAppBundles(dir string, depth int) []string {
+	var bundles []string
+	_ = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || p == dir {
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), ".app") {
+			bundles = append(bundles, p)
+			return filepath.SkipDir
+		}
+		if rel, _ := filepath.Rel(dir, p); strings.Count(rel, string(os.PathSeparator))+1 >= depth {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return bundles
+}
+
+// runningBundleIDs asks System Events for the bundle IDs of running apps
+func runningBundleIDs() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), runningAppsTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "osascript", "-e",
+		`tell application "System Events" to get bundle identifier of every application process`).Output()
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, id := range strings.Split(string(out), ",") {
+		if id = strings.TrimSpace(id); id != "" && id != "missing value" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (s *OrphanService) locations() []orphanLocation {
+	library := filepath.Join(os.Getenv("HOME"), "Library")
+
+	return []orphanLocation{
+		{kind: "Preferences", path: filepath.Join(library, "Preferences"), suffix: ".plist"},
+		{kind: "Caches", path: filepath.Join(library, "Caches")},
+		{kind: "Containers", path: filepath.Join(library, "Containers")},
+		{kind: "Application Support", path: filepath.Join(library, "Application Support")},
+		{kind: "Saved State", path: filepath.Join(library, "Saved Application State"), suffix: ".savedState"},
+		{kind: "HTTP Storage", path: filepath.Join(library, "HTTPStorages")},
+		{kind: "WebKit", path: filepath.Join(library, "WebKit")},
+		{kind: "Logs", path: filepath.Join(library, "Logs")},
+	}
+}
+
+func isProtectedBundle(bundleID string) bool {
+	lower := strings.ToLower(bundleID)
+	for _, pattern := range protectedVendorPatterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), lower); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isInstalledBundle treats helpers and extensions (com.vendor.App.helper) as
+// belonging to their installed parent app, and vice versa
+func isInstalledBundle(bundleID string, installed []string) bool {
+	lower := strings.ToLower(bundleID)
+	for _, id := range installed {
+		if lower == id || strings.HasPrefix(lower, id+".") || strings.HasPrefix(id, lower+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// splitBundleID infers vendor and app from a reverse-DNS identifier
+func splitBundleID(bundleID string) (vendor, app, appID string) {
+	parts := strings.Split(bundleID, ".")
+	vendor = strings.Join(parts[:2], ".")
+	if len(parts) < 3 {
+		return vendor, parts[1], bundleID
+	}
+	return vendor, parts[2], strings.Join(parts[:3], ".")
+}
+
+func groupOrphans(items []models.OrphanedItem) []models.OrphanVendorGroup {
+	vendors := make(map[string]*models.OrphanVendorGroup)
+	appIndex := make(map[string]map[string]int)
+
+	for _, item := range items {
+		vendor, app, appID := splitBundleID(item.BundleID)
+		vendorKey := strings.ToLower(vendor)
+		appKey := strings.ToLower(appID)
+
+		group, ok := vendors[vendorKey]
+		if !ok {
+			group = &models.OrphanVendorGroup{Vendor: vendor}
+			vendors[vendorKey] = group
+			appIndex[vendorKey] = make(map[string]int)
+		}
+
+		idx, ok := appIndex[vendorKey][appKey]
+		if !ok {
+			group.Apps = append(group.Apps, models.OrphanAppGroup{App: app, BundleID: appID})
+			idx = len(group.Apps) - 1
+			appIndex[vendorKey][appKey] = idx
+		}
+
+		group.Apps[idx].Items = append(group.Apps[idx].Items, item)
+		group.Apps[idx].Size += item.Size
+		group.Size += item.Size
+	}
+
+	result := make([]models.OrphanVendorGroup, 0, len(vendors))
+	for _, group := range vendors {
+		sort.Slice(group.Apps, func(i, j int) bool {
+			return group.Apps[i].Size > group.Apps[j].Size
+		})
+		for _, app := range group.Apps {
+			sort.Slice(app.Items, func(i, j int) bool {
+				return app.Items[i].Size > app.Items[j].Size
+			})
+		}
+		result = append(result, *group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Size > result[j].Size
+	})
+
+	return result
+}
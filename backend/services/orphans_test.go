@@ -0,0 +1,140 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+func TestReverseDNSPattern(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "com.example.app", want: true},
+		{name: "io.github.Some-App_2", want: true},
+		{name: "dev.vendor.tool.helper", want: true},
+		{name: "technology.vendor.app", want: true},
+		{name: "com.example", want: false},
+		{name: "Google", want: false},
+		{name: "Com.example.app", want: false},
+		{name: "x.example.app", want: false},
+		{name: "com..app", want: false},
+		{name: "com.example.app.", want: false},
+		{name: "com.example app.tool", want: false},
+	}
+	for _, tt := range tests {
+		if got := reverseDNSPattern.MatchString(tt.name); got != tt.want {
+			t.Fatalf("reverseDNSPattern.MatchString(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIsInstalledBundle(t *testing.T) {
+	installed := []string{"com.example.app", "org.vendor.suite.editor"}
+	tests := []struct {
+		bundleID string
+		want     bool
+	}{
+		{bundleID: "com.example.app", want: true},
+		{bundleID: "com.Example.App", want: true},
+		{bundleID: "com.example.app.helper", want: true},
+		{bundleID: "org.vendor.suite", want: true},
+		{bundleID: "com.example.application", want: false},
+		{bundleID: "com.other.app", want: false},
+	}
+	for _, tt := range tests {
+		if got := isInstalledBundle(tt.bundleID, installed); got != tt.want {
+			t.Fatalf("isInstalledBundle(%q) = %v, want %v", tt.bundleID, got, tt.want)
+		}
+	}
+}
+
+func TestOrphansInFiltersByAge(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	old := now.Add(-orphanAgeThreshold - 24*time.Hour)
+	recent := now.Add(-orphanAgeThreshold + 24*time.Hour)
+
+	entries := []struct {
+		name  string
+		data  string
+		mtime time.Time
+	}{
+		{name: "com.gone.app.plist", data: "old", mtime: old},
+		{name: "com.fresh.app.plist", data: "recent", mtime: recent},
+		{name: "com.installed.app.plist", data: "installed", mtime: old},
+		{name: "com.installed.app.helper.plist", data: "helper", mtime: old},
+		{name: "com.apple.finder.plist", data: "system", mtime: old},
+		{name: "com.gone.empty.plist", data: "", mtime: old},
+		{name: "notes.plist", data: "not a bundle", mtime: old},
+		{name: "com.gone.other.txt", data: "wrong suffix", mtime: old},
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.name)
+		if err := os.WriteFile(path, []byte(e.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, e.mtime, e.mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewOrphanService(&UninstallService{})
+	loc := orphanLocation{kind: "Preferences", path: dir, suffix: ".plist"}
+	items := s.orphansIn(loc, []string{"com.installed.app"}, now)
+
+	if len(items) != 1 {
+		t.Fatalf("got %d orphans, want 1: %+v", len(items), items)
+	}
+	if !items[0].LastModified.Equal(old) {
+		t.Fatalf("last modified %v, want %v", items[0].LastModified, old)
+	}
+	items[0].LastModified = old
+	want := models.OrphanedItem{
+		Path:         filepath.Join(dir, "com.gone.app.plist"),
+		BundleID:     "com.gone.app",
+		Kind:         "Preferences",
+		Size:         3,
+		LastModified: old,
+	}
+	if !reflect.DeepEqual(items[0], want) {
+		t.Fatalf("got %+v, want %+v", items[0], want)
+	}
+
+	if items := s.orphansIn(orphanLocation{path: filepath.Join(dir, "missing")}, nil, now); items != nil {
+		t.Fatalf("missing location: got %+v", items)
+	}
+}
+
+func TestGroupOrphans(t *testing.T) {
+	items := []models.OrphanedItem{
+		{BundleID: "com.vendor.small", Size: 10},
+		{BundleID: "com.vendor.big", Size: 100},
+		{BundleID: "com.vendor.big.helper", Size: 50},
+		{BundleID: "org.other.tool", Size: 20},
+	}
+	groups := groupOrphans(items)
+
+	if len(groups) != 2 || groups[0].Vendor != "com.vendor" || groups[0].Size != 160 || groups[1].Vendor != "org.other" {
+		t.Fatalf("unexpected vendor groups: %+v", groups)
+	}
+	var apps []string
+	for _, app := range groups[0].Apps {
+		apps = append(apps, app.BundleID)
+	}
+	if want := []string{"com.vendor.big", "com.vendor.small"}; !reflect.DeepEqual(apps, want) {
+		t.Fatalf("apps = %v, want %v", apps, want)
+	}
+	big := groups[0].Apps[0]
+	if big.Size != 150 || len(big.Items) != 2 || !sort.SliceIsSorted(big.Items, func(i, j int) bool {
+		return big.Items[i].Size > big.Items[j].Size
+	}) {
+		t.Fatalf("unexpected app group: %+v", big)
+	}
+}
@@ -0,0 +1,447 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"howett.net/plist"
+	"mole-wails/backend/models"
+)
+
+// Reasons reported in models.BrokenPreference.Reason
+const (
+	PreferenceEmpty   = "empty"
+	PreferenceCorrupt = "corrupt"
+)
+
+const preferenceRepairLogFile = "preference_repairs.json"
+
+// PreferenceService finds preference plists that no longer parse and repairs
+// them by moving them into a backup folder, so every removal can be undone
+type PreferenceService struct {
+	ctx       context.Context
+	uninstall *UninstallService
+	logMu     sync.Mutex
+}
+
+func NewPreferenceService(uninstall *UninstallService) *PreferenceService {
+	return &PreferenceService{
+		uninstall: uninstall,
+	}
+}
+
+func (s *PreferenceService) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// ScanBroken lists empty or unparsable preference files with their owning app
+func (s *PreferenceService) ScanBroken() ([]models.BrokenPreference, error) {
+	var broken []models.BrokenPreference
+	for _, file := range findPreferenceFiles() {
+		if pref, ok := validatePreference(file); !ok {
+			broken = append(broken, pref)
+		}
+	}
+
+	if len(broken) > 0 && s.uninstall != nil {
+		if apps, err := s.uninstall.ScanApplications(false); err == nil {
+			for i := range broken {
+				if app, ok := preferenceOwner(broken[i], apps); ok {
+					broken[i].OwnerApp = app.Name
+					broken[i].OwnerPath = app.Path
+				}
+			}
+		}
+	}
+
+	sort.Slice(broken, func(i, j int) bool {
+		return broken[i].Path < broken[j].Path
+	})
+
+	return broken, nil
+}
+
+// Repair backs up and removes the given preference files. Each path is
+// validated again first; files that parse now are left alone.
+func (s *PreferenceService) Repair(paths []string) ([]models.PreferenceRepair, error) {
+	allowed := make(map[string]preferenceFile)
+	for _, file := range findPreferenceFiles() {
+		allowed[file.path] = file
+	}
+
+	backupDir, err := preferenceBackupDir(time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	var apps []models.Application
+	if s.uninstall != nil {
+		apps, _ = s.uninstall.ScanApplications(false)
+	}
+
+	home := os.Getenv("HOME")
+	var repairs []models.PreferenceRepair
+	var failures []string
+
+	for _, path := range paths {
+		file, ok := allowed[path]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("%s: not a user preference file", path))
+			continue
+		}
+
+		pref, ok := validatePreference(file)
+		if ok {
+			continue
+		}
+
+		rel, err := filepath.Rel(home, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = filepath.Base(path)
+		}
+		backupPath := filepath.Join(backupDir, rel)
+
+		if err := moveFile(path, backupPath); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+
+		repair := models.PreferenceRepair{
+			ID:         fmt.Sprintf("%d-%d", time.Now().UnixNano(), len(repairs)),
+			Path:       path,
+			BackupPath: backupPath,
+			Reason:     pref.Reason,
+			RemovedAt:  time.Now(),
+		}
+		if app, ok := preferenceOwner(pref, apps); ok {
+			repair.OwnerApp = app.Name
+		}
+		repairs = append(repairs, repair)
+	}
+
+	if len(repairs) > 0 {
+		if err := s.appendRepairLog(repairs); err != nil {
+			return repairs, fmt.Errorf("preferences were moved to %s but the repair log could not be written: %w", backupDir, err)
+		}
+	}
+
+	if len(failures) > 0 {
+		return repairs, fmt.Errorf("failed to repair %d preference files: %s", len(failures), strings.Join(failures, "; "))
+	}
+	return repairs, nil
+}
+
+// GetRepairLog returns past repairs, newest first
+func (s *PreferenceService) GetRepairLog() ([]models.PreferenceRepair, error) {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	repairs, err := loadPreferenceRepairs()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(repairs, func(i, j int) bool {
+		return repairs[i].RemovedAt.After(repairs[j].RemovedAt)
+	})
+	return repairs, nil
+}
+
+// Restore moves a backed-up preference file back into place
+func (s *PreferenceService) Restore(id string) error {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	repairs, err := loadPreferenceRepairs()
+	if err != nil {
+		return err
+	}
+
+	for i := range repairs {
+		if repairs[i].ID != id {
+			continue
+		}
+		repair := &repairs[i]
+		if repair.RestoredAt != nil {
+			return fmt.Errorf("%s was already restored", repair.Path)
+		}
+		if _, err := os.Lstat(repair.Path); err == nil {
+			return fmt.Errorf("a new preference file exists at %s", repair.Path)
+		}
+		if err := moveFile(repair.BackupPath, repair.Path); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", repair.Path, err)
+		}
+
+		now := time.Now()
+		repair.RestoredAt = &now
+		return savePreferenceRepairs(repairs)
+	}
+
+	return fmt.Errorf("repair not found: %s", id)
+}
+
+func (s *PreferenceService) appendRepairLog(entries []models.PreferenceRepair) error {
+	s.logMu.Lock()
+	defer s.logMu.Unlock()
+
+	repairs, err := loadPreferenceRepairs()
+	if err != nil {
+		return err
+	}
+	return savePreferenceRepairs(append(repairs, entries...))
+}
+
+// Helper functions
+
+// preferenceFile is a plist found in a preferences folder
+type preferenceFile struct {
+	path      string
+	domain    string
+	container string
+}
+
+// findPreferenceFiles lists third-party preference plists in
+// ~/Library/Preferences, its ByHost folder and sandbox container preferences
+func findPreferenceFiles() []preferenceFile {
+	home := os.Getenv("HOME")
+	prefsDir := filepath.Join(home, "Library", "Preferences")
+
+	var files []preferenceFile
+	files = append(files, listPreferenceDir(prefsDir, "", false)...)
+	files = append(files, listPreferenceDir(filepath.Join(prefsDir, "ByHost"), "", true)...)
+
+	containerPatterns := []string{
+		filepath.Join(home, "Library", "Containers", "*", "Data", "Library", "Preferences"),
+		filepath.Join(home, "Library", "Group Containers", "*", "Library", "Preferences"),
+	}
+	for _, pattern := range containerPatterns {
+		dirs, _ := filepath.Glob(pattern)
+		for _, dir := range dirs {
+			container := containerName(dir)
+			files = append(files, listPreferenceDir(dir, container, false)...)
+			files = append(files, listPreferenceDir(filepath.Join(dir, "ByHost"), container, true)...)
+		}
+	}
+
+	return files
+}
+
+func listPreferenceDir(dir, container string, byHost bool) []preferenceFile {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []preferenceFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasSuffix(name, ".plist") {
+			continue
+		}
+		// System preferences are managed by cfprefsd and never touched
+		if strings.HasPrefix(name, "com.apple.") || strings.HasPrefix(name, ".GlobalPreferences") || name == "loginwindow.plist" {
+			continue
+		}
+
+		domain := strings.TrimSuffix(name, ".plist")
+		if byHost {
+			// ByHost files carry the hardware UUID as last component
+			if idx := strings.LastIndex(domain, "."); idx > 0 {
+				domain = domain[:idx]
+			}
+		}
+
+		files = append(files, preferenceFile{
+			path:      filepath.Join(dir, name),
+			domain:    domain,
+			container: container,
+		})
+	}
+	return files
+}
+
+// containerName returns the container folder name for a preferences directory
+func containerName(prefsDir string) string {
+	dir := filepath.Dir(prefsDir) // Library
+	if filepath.Base(filepath.Dir(dir)) == "Data" {
+		dir = filepath.Dir(dir) // Data
+	}
+	return filepath.Base(filepath.Dir(dir))
+}
+
+// validatePreference reports the file and whether it parses as a plist
+func validatePreference(file preferenceFile) (models.BrokenPreference, bool) {
+	pref := models.BrokenPreference{
+		Path:      file.path,
+		Domain:    file.domain,
+		Container: file.container,
+	}
+
+	info, err := os.Stat(file.path)
+	if err != nil {
+		// Unreadable files are not ours to judge
+		return pref, true
+	}
+	pref.Size = info.Size()
+	pref.ModTime = info.ModTime()
+
+	if info.Size() == 0 {
+		pref.Reason = PreferenceEmpty
+		return pref, false
+	}
+
+	data, err := os.ReadFile(file.path)
+	if err != nil {
+		return pref, true
+	}
+
+	var value interface{}
+	if _, err := plist.Unmarshal(data, &value); err != nil {
+		pref.Reason = PreferenceCorrupt
+		pref.Error = err.Error()
+		return pref, false
+	}
+
+	return pref, true
+}
+
+// preferenceOwner matches a preference domain or container to an installed app
+func preferenceOwner(pref models.BrokenPreference, apps []models.Application) (models.Application, bool) {
+	candidates := []string{strings.ToLower(pref.Domain)}
+	if pref.Container != "" {
+		candidates = append(candidates, strings.ToLower(pref.Container))
+	}
+
+	var best models.Application
+	bestLen := 0
+	for _, app := range apps {
+		id := strings.ToLower(app.BundleID)
+		if id == "" || id == "unknown" {
+			continue
+		}
+		for _, candidate := range candidates {
+			// Group containers are prefixed with a team ID, e.g. ABCDE12345.com.vendor.app
+			if candidate == id || strings.HasPrefix(candidate, id+".") || strings.HasSuffix(candidate, "."+id) {
+				if len(id) > bestLen {
+					best = app
+					bestLen = len(id)
+				}
+			}
+		}
+	}
+	return best, bestLen > 0
+}
+
+// preferenceBackupDir creates a new backup folder for one repair run. The
+// timestamp only orders folders; a random suffix keeps runs in the same
+// second apart.
+func preferenceBackupDir(now time.Time) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	base := filepath.Join(home, ".config", "mole", "preference_backups")
+	if err := os.MkdirAll(base, 0700); err != nil {
+		return "", err
+	}
+	return os.MkdirTemp(base, now.Format("20060102-150405")+"-")
+}
+
+// moveFile renames src to dst, copying across filesystems when needed.
+// An existing dst is never replaced.
+func moveFile(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	if _, err := os.Lstat(dst); err == nil {
+		return fmt.Errorf("%s already exists", dst)
+	}
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
+
+func getPreferenceRepairLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	configDir := filepath.Join(home, ".config", "mole")
+	if err := os.MkdirAll(configDir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, preferenceRepairLogFile), nil
+}
+
+func loadPreferenceRepairs() ([]models.PreferenceRepair, error) {
+	logPath, err := getPreferenceRepairLogPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate repair log: %w", err)
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.PreferenceRepair{}, nil
+		}
+		return nil, fmt.Errorf("failed to read repair log: %w", err)
+	}
+
+	var repairs []models.PreferenceRepair
+	if err := json.Unmarshal(data, &repairs); err != nil {
+		// Never overwrite a log we cannot read; it points at the backups
+		return nil, fmt.Errorf("failed to parse repair log %s: %w", logPath, err)
+	}
+	return repairs, nil
+}
+
+func savePreferenceRepairs(repairs []models.PreferenceRepair) error {
+	logPath, err := getPreferenceRepairLogPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(repairs, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := logPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, logPath)
+}
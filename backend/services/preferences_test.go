@@ -0,0 +1,191 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const validPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>LastRun</key>
+	<string>today</string>
+</dict>
+</plist>
+`
+
+// writePreference writes a plist into ~/Library/Preferences of the test home
+func writePreference(t *testing.T, home, name, data string) string {
+	t.Helper()
+	dir := filepath.Join(home, "Library", "Preferences")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestValidatePreference(t *testing.T) {
+	home := t.TempDir()
+
+	tests := []struct {
+		name   string
+		data   string
+		ok     bool
+		reason string
+	}{
+		{name: "com.example.valid.plist", data: validPlist, ok: true},
+		{name: "com.example.empty.plist", data: "", ok: false, reason: PreferenceEmpty},
+		{name: "com.example.corrupt.plist", data: "<?xml version=\"1.0\"?><plist><dict><key>", ok: false, reason: PreferenceCorrupt},
+	}
+	for _, tt := range tests {
+		path := writePreference(t, home, tt.name, tt.data)
+		pref, ok := validatePreference(preferenceFile{path: path, domain: strings.TrimSuffix(tt.name, ".plist")})
+		if ok != tt.ok || pref.Reason != tt.reason {
+			t.Fatalf("%s: got ok=%v reason=%q, want ok=%v reason=%q", tt.name, ok, pref.Reason, tt.ok, tt.reason)
+		}
+		if pref.Path != path || pref.Size != int64(len(tt.data)) {
+			t.Fatalf("%s: unexpected details %+v", tt.name, pref)
+		}
+		if tt.reason == PreferenceCorrupt && pref.Error == "" {
+			t.Fatalf("%s: corrupt file without parse error", tt.name)
+		}
+	}
+
+	// Files that vanished are not reported as broken
+	missing := preferenceFile{path: filepath.Join(home, "missing.plist")}
+	if _, ok := validatePreference(missing); !ok {
+		t.Fatal("missing file reported as broken")
+	}
+}
+
+func TestPreferenceBackupDirIsUnique(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.Local)
+
+	first, err := preferenceBackupDir(now)
+	if err != nil {
+		t.Fatalf("failed to create backup dir: %v", err)
+	}
+	second, err := preferenceBackupDir(now)
+	if err != nil {
+		t.Fatalf("failed to create backup dir: %v", err)
+	}
+	if first == second {
+		t.Fatalf("runs in the same second share %s", first)
+	}
+	if !strings.HasPrefix(filepath.Base(first), "20260304-050607-") {
+		t.Fatalf("backup dir %s is not named after its time", first)
+	}
+}
+
+func TestMoveFileRefusesOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.plist")
+	dst := filepath.Join(dir, "backup", "dst.plist")
+	for path, data := range map[string]string{src: "new", dst: "old"} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := moveFile(src, dst); err == nil {
+		t.Fatal("expected an error for an existing destination")
+	}
+	if data, _ := os.ReadFile(dst); string(data) != "old" {
+		t.Fatalf("destination was overwritten: %q", data)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("source was lost: %v", err)
+	}
+}
+
+func TestPreferenceRepairLog(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	broken := writePreference(t, home, "com.example.broken.plist", "not a plist at all \x00")
+	valid := writePreference(t, home, "com.example.valid.plist", validPlist)
+	s := NewPreferenceService(nil)
+
+	repairs, err := s.Repair([]string{broken, valid})
+	if err != nil {
+		t.Fatalf("repair failed: %v", err)
+	}
+	if len(repairs) != 1 || repairs[0].Path != broken || repairs[0].Reason != PreferenceCorrupt {
+		t.Fatalf("unexpected repairs: %+v", repairs)
+	}
+	if _, err := os.Stat(broken); !os.IsNotExist(err) {
+		t.Fatalf("broken file still in place: %v", err)
+	}
+	if _, err := os.Stat(valid); err != nil {
+		t.Fatalf("valid file was touched: %v", err)
+	}
+	if _, err := os.Stat(repairs[0].BackupPath); err != nil {
+		t.Fatalf("backup missing: %v", err)
+	}
+
+	// A second run within the same second must not overwrite the first backup
+	writePreference(t, home, "com.example.broken.plist", "")
+	again, err := s.Repair([]string{broken})
+	if err != nil {
+		t.Fatalf("second repair failed: %v", err)
+	}
+	if len(again) != 1 || again[0].BackupPath == repairs[0].BackupPath {
+		t.Fatalf("second repair reused %s: %+v", repairs[0].BackupPath, again)
+	}
+
+	if _, err := s.Repair([]string{filepath.Join(home, "elsewhere.plist")}); err == nil {
+		t.Fatal("expected an error for a file outside the preference folders")
+	}
+
+	log, err := s.GetRepairLog()
+	if err != nil {
+		t.Fatalf("failed to read repair log: %v", err)
+	}
+	if len(log) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(log))
+	}
+
+	// Restoring needs the original path to be free
+	writePreference(t, home, "com.example.broken.plist", validPlist)
+	if err := s.Restore(again[0].ID); err == nil {
+		t.Fatal("expected an error while a new file exists at the original path")
+	}
+	if err := os.Remove(broken); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Restore(again[0].ID); err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if data, err := os.ReadFile(broken); err != nil || len(data) != 0 {
+		t.Fatalf("restored file: %q, %v", data, err)
+	}
+	if err := s.Restore(again[0].ID); err == nil {
+		t.Fatal("expected an error when restoring twice")
+	}
+	if err := s.Restore("unknown"); err == nil {
+		t.Fatal("expected an error for an unknown repair")
+	}
+
+	log, err = s.GetRepairLog()
+	if err != nil {
+		t.Fatalf("failed to read repair log: %v", err)
+	}
+	for _, entry := range log {
+		restored := entry.RestoredAt != nil
+		if restored != (entry.ID == again[0].ID) {
+			t.Fatalf("entry %s restored=%v", entry.ID, restored)
+		}
+	}
+}
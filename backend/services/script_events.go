@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Script event types, see scripts/lib/core/events.sh
+const (
+	ScriptEventHello       = "hello"
+	ScriptEventTaskStart   = "task_start"
+	ScriptEventTaskFinish  = "task_finish"
+	ScriptEventFileRemoved = "file_removed"
+	ScriptEventWarning     = "warning"
+	ScriptEventError       = "error"
+	// ScriptEventOutput carries a plain stdout line for display
+	ScriptEventOutput = "output"
+)
+
+const (
+	scriptEventsEnv   = "MOLE_EVENTS"
+	scriptEventFDEnv  = "MOLE_EVENT_FD"
+	scriptEventFD     = 3 // first entry of exec.Cmd.ExtraFiles
+	scriptEventFormat = "jsonl"
+)
+
+// ansiEscape matches terminal colour sequences in legacy script output
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// ScriptEvent is one line of the JSON-lines protocol, or an event derived
+// from legacy text output for scripts that predate it
+type ScriptEvent struct {
+	Type    string `json:"type"`
+	Version int    `json:"version,omitempty"`
+	Task    string `json:"task,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Status  string `json:"status,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Size    int64  `json:"size,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Legacy is set on events parsed from "✓" style text
+	Legacy bool `json:"legacy,omitempty"`
+}
+
+// runScript runs a bash script with structured events enabled and calls
+// onEvent for every event, in order. Stdout lines are always reported as
+// output events. Events parsed from legacy text, and stderr lines which
+// become warnings for legacy scripts, are held back until the script exits
+// and only reported if it never emitted a structured event, since stdout and
+// the event pipe are read independently.
+func runScript(scriptPath string, args []string, onEvent func(ScriptEvent)) error {
+	eventReader, eventWriter, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("failed to create event pipe: %w", err)
+	}
+	defer eventReader.Close()
+
+	cmd := exec.Command("/bin/bash", append([]string{scriptPath}, args...)...)
+	cmd.Env = append(os.Environ(),
+		scriptEventsEnv+"="+scriptEventFormat,
+		fmt.Sprintf("%s=%d", scriptEventFDEnv, scriptEventFD),
+	)
+	cmd.ExtraFiles = []*os.File{eventWriter}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		eventWriter.Close()
+		return fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		eventWriter.Close()
+		return fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		eventWriter.Close()
+		return fmt.Errorf("failed to start %s: %w", scriptPath, err)
+	}
+	// The child holds its own copy; closing ours lets the reader see EOF
+	eventWriter.Close()
+
+	type line struct {
+		source string
+		text   string
+	}
+	lines := make(chan line)
+
+	var wg sync.WaitGroup
+	read := func(source string, r io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- line{source, scanner.Text()}
+		}
+	}
+	wg.Add(3)
+	go read("events", eventReader)
+	go read("stdout", stdout)
+	go read("stderr", stderr)
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	structured := false
+	var legacy []ScriptEvent
+	for l := range lines {
+		switch l.source {
+		case "events":
+			var ev ScriptEvent
+			if err := json.Unmarshal([]byte(l.text), &ev); err != nil || ev.Type == "" {
+				continue
+			}
+			if !structured {
+				structured = true
+				// Stderr read so far was plain output after all
+				for _, held := range legacy {
+					if held.Type == ScriptEventWarning {
+						onEvent(ScriptEvent{Type: ScriptEventOutput, Message: held.Message})
+					}
+				}
+				legacy = nil
+			}
+			if ev.Type != ScriptEventHello {
+				onEvent(ev)
+			}
+		case "stdout":
+			text := strings.TrimSpace(ansiEscape.ReplaceAllString(l.text, ""))
+			if text == "" {
+				continue
+			}
+			onEvent(ScriptEvent{Type: ScriptEventOutput, Message: text})
+			if !structured {
+				if ev, ok := parseLegacyLine(text); ok {
+					legacy = append(legacy, ev)
+				}
+			}
+		case "stderr":
+			// Structured scripts already report their errors as events
+			text := strings.TrimSpace(ansiEscape.ReplaceAllString(l.text, ""))
+			switch {
+			case text == "":
+			case structured:
+				onEvent(ScriptEvent{Type: ScriptEventOutput, Message: text})
+			default:
+				legacy = append(legacy, ScriptEvent{Type: ScriptEventWarning, Message: text, Legacy: true})
+			}
+		}
+	}
+
+	if !structured {
+		for _, ev := range legacy {
+			onEvent(ev)
+		}
+	}
+
+	return cmd.Wait()
+}
+
+// parseLegacyLine maps the old "✓ Removed <path>" and "✓ <task>" lines to events
+func parseLegacyLine(text string) (ScriptEvent, bool) {
+	idx := strings.Index(text, "✓")
+	if idx < 0 {
+		return ScriptEvent{}, false
+	}
+	rest := strings.TrimSpace(text[idx+len("✓"):])
+
+	if path, ok := strings.CutPrefix(rest, "Removed"); ok {
+		return ScriptEvent{Type: ScriptEventFileRemoved, Path: strings.TrimSpace(path), Legacy: true}, true
+	}
+	return ScriptEvent{Type: ScriptEventTaskFinish, Name: rest, Status: TaskStatusCompleted, Legacy: true}, true
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseLegacyLine(t *testing.T) {
+	tests := []struct {
+		text string
+		want ScriptEvent
+		ok   bool
+	}{
+		{
+			text: "✓ Removed /Users/me/Library/Caches/com.example.app",
+			want: ScriptEvent{Type: ScriptEventFileRemoved, Path: "/Users/me/Library/Caches/com.example.app", Legacy: true},
+			ok:   true,
+		},
+		{
+			text: "  ✓ Cleared DNS cache",
+			want: ScriptEvent{Type: ScriptEventTaskFinish, Name: "Cleared DNS cache", Status: TaskStatusCompleted, Legacy: true},
+			ok:   true,
+		},
+		{text: "Scanning application files...", ok: false},
+		{text: "", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := parseLegacyLine(tt.text)
+		if ok != tt.ok {
+			t.Fatalf("parseLegacyLine(%q) ok = %v, want %v", tt.text, ok, tt.ok)
+		}
+		if ok && !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("parseLegacyLine(%q) = %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "script.sh")
+	if err := os.WriteFile(path, []byte(body), 0o755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
+
+func collectEvents(t *testing.T, script string) []ScriptEvent {
+	t.Helper()
+	var events []ScriptEvent
+	if err := runScript(script, nil, func(ev ScriptEvent) {
+		events = append(events, ev)
+	}); err != nil {
+		t.Fatalf("runScript failed: %v", err)
+	}
+	return events
+}
+
+func countEvents(events []ScriptEvent, eventType string) int {
+	n := 0
+	for _, ev := range events {
+		if ev.Type == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRunScriptLegacy(t *testing.T) {
+	script := writeScript(t, `
+echo -e "\033[0;32m✓\033[0m Removed /tmp/a"
+echo "✓ Removed /tmp/b"
+echo "✓ Done"
+echo "oops" >&2
+`)
+	events := collectEvents(t, script)
+
+	if got := countEvents(events, ScriptEventOutput); got != 3 {
+		t.Fatalf("got %d output events, want 3: %+v", got, events)
+	}
+	if got := countEvents(events, ScriptEventWarning); got != 1 {
+		t.Fatalf("got %d warnings, want 1: %+v", got, events)
+	}
+
+	var removed []string
+	for _, ev := range events {
+		if ev.Type == ScriptEventFileRemoved {
+			if !ev.Legacy {
+				t.Fatalf("legacy event not marked: %+v", ev)
+			}
+			removed = append(removed, ev.Path)
+		}
+	}
+	if want := []string{"/tmp/a", "/tmp/b"}; !reflect.DeepEqual(removed, want) {
+		t.Fatalf("removed = %v, want %v", removed, want)
+	}
+	if got := countEvents(events, ScriptEventTaskFinish); got != 1 {
+		t.Fatalf("got %d task_finish events, want 1: %+v", got, events)
+	}
+}
+
+func TestRunScriptStructured(t *testing.T) {
+	// Stdout and stderr are read before hello reaches the event pipe; the
+	// "✓ Removed" line must not be counted next to the structured event
+	script := writeScript(t, `
+fd="${MOLE_EVENT_FD:-3}"
+echo "✓ Removed /tmp/a"
+echo "warning text" >&2
+sleep 0.2
+echo '{"type":"hello","version":1}' >&"$fd"
+echo '{"type":"file_removed","path":"/tmp/a","size":42}' >&"$fd"
+`)
+	events := collectEvents(t, script)
+
+	var removed []ScriptEvent
+	for _, ev := range events {
+		switch {
+		case ev.Type == ScriptEventHello:
+			t.Fatalf("hello must not be reported: %+v", ev)
+		case ev.Legacy:
+			t.Fatalf("legacy event reported for a structured script: %+v", ev)
+		case ev.Type == ScriptEventFileRemoved:
+			removed = append(removed, ev)
+		}
+	}
+	want := []ScriptEvent{{Type: ScriptEventFileRemoved, Path: "/tmp/a", Size: 42}}
+	if !reflect.DeepEqual(removed, want) {
+		t.Fatalf("removed = %+v, want %+v", removed, want)
+	}
+	if got := countEvents(events, ScriptEventOutput); got != 2 {
+		t.Fatalf("got %d output events, want 2: %+v", got, events)
+	}
+}
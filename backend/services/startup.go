@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
+)
+
+// Startup item kinds reported in models.StartupItem.Kind
+const (
+	StartupKindLaunchAgent  = "LaunchAgent"
+	StartupKindLaunchDaemon = "LaunchDaemon"
+	StartupKindLoginItem    = "LoginItem"
+	StartupKindBackground   = "BackgroundItem"
+	StartupKindAutostart    = "Autostart"
+	StartupKindSystemd      = "systemd"
+)
+
+type StartupService struct {
+	uninstall *UninstallService
+	broker    *privileged.Broker
+	ctx       context.Context
+}
+
+func NewStartupService(uninstall *UninstallService, broker *privileged.Broker) *StartupService {
+	return &StartupService{
+		uninstall: uninstall,
+		broker:    broker,
+	}
+}
+
+func (s *StartupService) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// ListItems returns everything configured to start at login or boot
+func (s *StartupService) ListItems() ([]models.StartupItem, error) {
+	items, err := listStartupItems()
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []models.Application
+	if s.uninstall != nil {
+		apps, _ = s.uninstall.ScanApplications(false)
+	}
+
+	for i := range items {
+		if items[i].Program != "" {
+			_, err := os.Stat(items[i].Program)
+			items[i].ProgramExists = err == nil
+		}
+		items[i].OwnerApp = findOwnerApp(items[i], apps)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Scope != items[j].Scope {
+			return items[i].Scope == "user"
+		}
+		return strings.ToLower(items[i].Label) < strings.ToLower(items[j].Label)
+	})
+
+	return items, nil
+}
+
+// DisableItem stops an item from starting without deleting it
+func (s *StartupService) DisableItem(id string) error {
+	item, err := s.findItem(id)
+	if err != nil {
+		return err
+	}
+	return setStartupItemEnabled(s.baseContext(), s.broker, item, false)
+}
+
+// EnableItem re-enables a previously disabled item
+func (s *StartupService) EnableItem(id string) error {
+	item, err := s.findItem(id)
+	if err != nil {
+		return err
+	}
+	return setStartupItemEnabled(s.baseContext(), s.broker, item, true)
+}
+
+// RemoveItem unloads an item and moves its definition to the Trash
+func (s *StartupService) RemoveItem(id string) error {
+	item, err := s.findItem(id)
+	if err != nil {
+		return err
+	}
+	if item.Scope != "user" {
+		return fmt.Errorf("cannot remove system startup item: %s", item.Label)
+	}
+	return removeStartupItem(item)
+}
+
+// Helper functions
+
+func (s *StartupService) baseContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// findItem resolves an ID against a fresh listing so only known items are modified
+func (s *StartupService) findItem(id string) (models.StartupItem, error) {
+	items, err := listStartupItems()
+	if err != nil {
+		return models.StartupItem{}, err
+	}
+	for _, item := range items {
+		if item.ID == id {
+			if !item.Editable {
+				return models.StartupItem{}, fmt.Errorf("startup item is not editable: %s", item.Label)
+			}
+			return item, nil
+		}
+	}
+	return models.StartupItem{}, fmt.Errorf("startup item not found: %s", id)
+}
+
+// findOwnerApp attributes an item to the app bundle its program lives in,
+// or to the installed app whose bundle ID prefixes its label
+func findOwnerApp(item models.StartupItem, apps []models.Application) string {
+	if idx := strings.Index(item.Program, ".app/"); idx >= 0 {
+		return filepath.Base(item.Program[:idx])
+	}
+
+	label := strings.ToLower(item.Label)
+	owner := ""
+	longest := 0
+	for _, app := range apps {
+		id := strings.ToLower(app.BundleID)
+		if id == "" || id == "unknown" || len(id) <= longest {
+			continue
+		}
+		if label == id || strings.HasPrefix(label, id+".") {
+			owner = app.Name
+			longest = len(id)
+		}
+	}
+	return owner
+}
+
+// moveToTrash moves a file into the user's Trash. Nothing is deleted when
+// that fails, so the item can't be lost without a way to restore it.
+func moveToTrash(path string) error {
+	trashDir := filepath.Join(os.Getenv("HOME"), ".Trash")
+	if _, err := os.Stat(trashDir); err != nil {
+		return moveToXDGTrash(path, time.Now())
+	}
+
+	target := filepath.Join(trashDir, filepath.Base(path))
+	if _, err := os.Stat(target); err == nil {
+		target = fmt.Sprintf("%s.%d", target, os.Getpid())
+	}
+	return os.Rename(path, target)
+}
+
+// moveToXDGTrash trashes path as the FreeDesktop trash spec describes: the
+// .trashinfo entry is created first, under a name no other entry uses, so
+// file managers can restore the item to where it came from
+func moveToXDGTrash(path string, now time.Time) error {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(os.Getenv("HOME"), ".local", "share")
+	}
+	filesDir := filepath.Join(dataHome, "Trash", "files")
+	infoDir := filepath.Join(dataHome, "Trash", "info")
+	for _, dir := range []string{filesDir, infoDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+
+	info := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: absPath}).EscapedPath(), now.Format("2006-01-02T15:04:05"))
+
+	base := filepath.Base(absPath)
+	for i := 0; i < 1000; i++ {
+		name := base
+		if i > 0 {
+			name = fmt.Sprintf("%s.%d", base, i)
+		}
+
+		infoPath := filepath.Join(infoDir, name+".trashinfo")
+		f, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = f.WriteString(info)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			if _, statErr := os.Lstat(filepath.Join(filesDir, name)); statErr == nil {
+				os.Remove(infoPath)
+				continue
+			}
+			err = os.Rename(absPath, filepath.Join(filesDir, name))
+		}
+		if err != nil {
+			os.Remove(infoPath)
+			return err
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no free trash name for %s", base)
+}
@@ -0,0 +1,469 @@
+//go:build darwin
+
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"howett.net/plist"
+	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
+)
+
+const (
+	launchctlTimeout = 10 * time.Second
+	loginItemPrefix  = "login-item:"
+)
+
+// launchdPlist holds the launchd.plist keys shown in the startup manager
+type launchdPlist struct {
+	Label            string      `plist:"Label"`
+	Program          string      `plist:"Program"`
+	ProgramArguments []string    `plist:"ProgramArguments"`
+	RunAtLoad        bool        `plist:"RunAtLoad"`
+	KeepAlive        interface{} `plist:"KeepAlive"`
+	Disabled         bool        `plist:"Disabled"`
+}
+
+type launchdDir struct {
+	path  string
+	kind  string
+	scope string
+}
+
+func launchdDirs() []launchdDir {
+	return []launchdDir{
+		{path: filepath.Join(os.Getenv("HOME"), "Library", "LaunchAgents"), kind: StartupKindLaunchAgent, scope: "user"},
+		{path: "/Library/LaunchAgents", kind: StartupKindLaunchAgent, scope: "system"},
+		{path: "/Library/LaunchDaemons", kind: StartupKindLaunchDaemon, scope: "system"},
+	}
+}
+
+func listStartupItems() ([]models.StartupItem, error) {
+	userDisabled := launchctlDisabled(fmt.Sprintf("gui/%d", os.Getuid()))
+	systemDisabled := launchctlDisabled("system")
+
+	items := listLaunchdItems(userDisabled, systemDisabled)
+	items = append(items, listBackgroundItems(userDisabled, systemDisabled)...)
+	items = append(items, listLoginItems()...)
+	return items, nil
+}
+
+func listLaunchdItems(userDisabled, systemDisabled map[string]bool) []models.StartupItem {
+	var items []models.StartupItem
+	for _, dir := range launchdDirs() {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".plist") {
+				continue
+			}
+
+			path := filepath.Join(dir.path, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+
+			job, err := parseLaunchdPlist(data)
+			if err != nil {
+				continue
+			}
+			if job.Label == "" {
+				job.Label = strings.TrimSuffix(entry.Name(), ".plist")
+			}
+
+			disabled := job.Disabled
+			if override, ok := userDisabled[job.Label]; ok && dir.scope == "user" {
+				disabled = override
+			}
+			if override, ok := systemDisabled[job.Label]; ok && dir.scope == "system" {
+				disabled = override
+			}
+
+			program := job.Program
+			if program == "" && len(job.ProgramArguments) > 0 {
+				program = job.ProgramArguments[0]
+			}
+			if rest, ok := strings.CutPrefix(program, "~/"); ok {
+				program = filepath.Join(os.Getenv("HOME"), rest)
+			}
+
+			items = append(items, models.StartupItem{
+				ID:        path,
+				Label:     job.Label,
+				Kind:      dir.kind,
+				Scope:     dir.scope,
+				Path:      path,
+				Program:   program,
+				Arguments: job.ProgramArguments,
+				RunAtLoad: job.RunAtLoad,
+				KeepAlive: keepAliveEnabled(job.KeepAlive),
+				Enabled:   !disabled,
+				// Apple's own jobs break login and system services when touched.
+				// System agents are toggled for this user, daemons through the helper.
+				Editable: !strings.HasPrefix(job.Label, "com.apple."),
+			})
+		}
+	}
+
+	return items
+}
+
+// listBackgroundItems lists the launchd jobs apps register from inside their
+// bundle with SMAppService. They are managed in System Settings > Login Items.
+func listBackgroundItems(userDisabled, systemDisabled map[string]bool) []models.StartupItem {
+	var items []models.StartupItem
+	for _, appDir := range []string{"/Applications", filepath.Join(os.Getenv("HOME"), "Applications")} {
+		bundles, _ := filepath.Glob(filepath.Join(appDir, "*.app"))
+		for _, bundle := range bundles {
+			for _, sub := range []struct {
+				dir, kind, scope string
+				disabled         map[string]bool
+			}{
+				{"LaunchAgents", StartupKindLaunchAgent, "user", userDisabled},
+				{"LaunchDaemons", StartupKindLaunchDaemon, "system", systemDisabled},
+			} {
+				plists, _ := filepath.Glob(filepath.Join(bundle, "Contents", "Library", sub.dir, "*.plist"))
+				for _, path := range plists {
+					data, err := os.ReadFile(path)
+					if err != nil {
+						continue
+					}
+					job, err := parseLaunchdPlist(data)
+					if err != nil {
+						continue
+					}
+					if job.Label == "" {
+						job.Label = strings.TrimSuffix(filepath.Base(path), ".plist")
+					}
+
+					program := job.Program
+					if program == "" && len(job.ProgramArguments) > 0 {
+						program = job.ProgramArguments[0]
+					}
+					if program != "" && !filepath.IsAbs(program) {
+						program = filepath.Join(bundle, program)
+					}
+
+					items = append(items, models.StartupItem{
+						ID:        path,
+						Label:     job.Label,
+						Kind:      StartupKindBackground,
+						Scope:     sub.scope,
+						Path:      path,
+						Program:   program,
+						Arguments: job.ProgramArguments,
+						RunAtLoad: job.RunAtLoad,
+						KeepAlive: keepAliveEnabled(job.KeepAlive),
+						Enabled:   !sub.disabled[job.Label],
+						OwnerApp:  filepath.Base(bundle),
+					})
+				}
+			}
+		}
+	}
+	return items
+}
+
+// Login items
+
+// loginItem is a login item as reported by System Events
+type loginItem struct {
+	Name   string `json:"name"`
+	Path   string `json:"path"`
+	Hidden bool   `json:"hidden"`
+}
+
+// listLoginItems lists the user's login items, plus those disabled here.
+// Login items cannot be switched off in place, so disabling one removes it
+// from System Events and remembers it for re-enabling.
+func listLoginItems() []models.StartupItem {
+	current, _ := systemLoginItems()
+	disabled := loadDisabledLoginItems()
+
+	var items []models.StartupItem
+	seen := make(map[string]bool)
+	for _, item := range current {
+		seen[item.Path] = true
+		items = append(items, loginStartupItem(item, true))
+	}
+	for _, item := range disabled {
+		if !seen[item.Path] {
+			items = append(items, loginStartupItem(item, false))
+		}
+	}
+	return items
+}
+
+func loginStartupItem(item loginItem, enabled bool) models.StartupItem {
+	return models.StartupItem{
+		ID:        loginItemPrefix + item.Path,
+		Label:     item.Name,
+		Kind:      StartupKindLoginItem,
+		Scope:     "user",
+		Path:      item.Path,
+		Program:   item.Path,
+		RunAtLoad: true,
+		Enabled:   enabled,
+		Editable:  true,
+	}
+}
+
+func systemLoginItems() ([]loginItem, error) {
+	output, err := runJXA(`function run() {
+		const se = Application("System Events");
+		return JSON.stringify(se.loginItems().map(i => ({name: i.name(), path: i.path(), hidden: i.hidden()})));
+	}`)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []loginItem
+	if err := json.Unmarshal([]byte(output), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse login items: %w", err)
+	}
+	return items, nil
+}
+
+func setLoginItemEnabled(item models.StartupItem, enabled bool) error {
+	disabled := loadDisabledLoginItems()
+
+	if enabled {
+		entry := loginItem{Name: item.Label, Path: item.Path}
+		for _, d := range disabled {
+			if d.Path == item.Path {
+				entry = d
+			}
+		}
+		if _, err := runJXA(`function run(argv) {
+			const se = Application("System Events");
+			se.make({new: "loginItem", at: se.loginItems.end, withProperties: {path: argv[0], hidden: argv[1] === "true"}});
+		}`, entry.Path, fmt.Sprintf("%t", entry.Hidden)); err != nil {
+			return fmt.Errorf("failed to add login item %s: %w", item.Label, err)
+		}
+		return saveDisabledLoginItems(withoutLoginItem(disabled, item.Path))
+	}
+
+	current, err := systemLoginItems()
+	if err != nil {
+		return fmt.Errorf("failed to read login items: %w", err)
+	}
+	for _, c := range current {
+		if c.Path == item.Path {
+			if err := deleteLoginItem(c.Name); err != nil {
+				return err
+			}
+			return saveDisabledLoginItems(append(withoutLoginItem(disabled, c.Path), c))
+		}
+	}
+	return nil
+}
+
+func removeLoginItem(item models.StartupItem) error {
+	if item.Enabled {
+		if err := deleteLoginItem(item.Label); err != nil {
+			return err
+		}
+	}
+	return saveDisabledLoginItems(withoutLoginItem(loadDisabledLoginItems(), item.Path))
+}
+
+func deleteLoginItem(name string) error {
+	if _, err := runJXA(`function run(argv) {
+		Application("System Events").loginItems.byName(argv[0]).delete();
+	}`, name); err != nil {
+		return fmt.Errorf("failed to remove login item %s: %w", name, err)
+	}
+	return nil
+}
+
+func withoutLoginItem(items []loginItem, path string) []loginItem {
+	var kept []loginItem
+	for _, item := range items {
+		if item.Path != path {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+func disabledLoginItemsPath() string {
+	return filepath.Join(os.Getenv("HOME"), ".config", "mole", "disabled_login_items.json")
+}
+
+func loadDisabledLoginItems() []loginItem {
+	data, err := os.ReadFile(disabledLoginItemsPath())
+	if err != nil {
+		return nil
+	}
+	var items []loginItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil
+	}
+	return items
+}
+
+func saveDisabledLoginItems(items []loginItem) error {
+	path := disabledLoginItemsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// runJXA runs a JavaScript for Automation script; arguments reach run(argv)
+// without being spliced into the source
+func runJXA(script string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), launchctlTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "osascript", append([]string{"-l", "JavaScript", "-e", script}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("%v (%s)", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func parseLaunchdPlist(data []byte) (launchdPlist, error) {
+	var job launchdPlist
+	if _, err := plist.Unmarshal(data, &job); err != nil {
+		return launchdPlist{}, fmt.Errorf("invalid launchd plist: %w", err)
+	}
+	return job, nil
+}
+
+// keepAliveEnabled treats KeepAlive dictionaries (conditional keep-alive) as enabled
+func keepAliveEnabled(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		return len(v) > 0
+	default:
+		return false
+	}
+}
+
+// launchctlDisabled parses `launchctl print-disabled <domain>` into label => disabled
+func launchctlDisabled(domain string) map[string]bool {
+	output, err := runLaunchctl("print-disabled", domain)
+	if err != nil {
+		return nil
+	}
+	return parseLaunchctlDisabled(output)
+}
+
+func parseLaunchctlDisabled(output string) map[string]bool {
+	result := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		label, state, ok := strings.Cut(line, "=>")
+		if !ok {
+			continue
+		}
+		label = strings.Trim(strings.TrimSpace(label), `"`)
+		state = strings.TrimSpace(state)
+		result[label] = state == "disabled" || state == "true"
+	}
+	return result
+}
+
+func setStartupItemEnabled(ctx context.Context, broker *privileged.Broker, item models.StartupItem, enabled bool) error {
+	switch item.Kind {
+	case StartupKindLoginItem:
+		return setLoginItemEnabled(item, enabled)
+	case StartupKindLaunchDaemon:
+		return setLaunchDaemonEnabled(ctx, broker, item, enabled)
+	}
+
+	// System agents run in the user's session too, so they are toggled for this user
+	domain := fmt.Sprintf("gui/%d", os.Getuid())
+
+	if enabled {
+		if _, err := runLaunchctl("enable", domain+"/"+item.Label); err != nil {
+			return fmt.Errorf("failed to enable %s: %w", item.Label, err)
+		}
+		// Already-loaded jobs make bootstrap fail; the job is enabled either way
+		_, _ = runLaunchctl("bootstrap", domain, item.Path)
+		return nil
+	}
+
+	_, _ = runLaunchctl("bootout", domain, item.Path)
+	if _, err := runLaunchctl("disable", domain+"/"+item.Label); err != nil {
+		return fmt.Errorf("failed to disable %s: %w", item.Label, err)
+	}
+	return nil
+}
+
+// setLaunchDaemonEnabled toggles a daemon in the system domain through the root helper
+func setLaunchDaemonEnabled(ctx context.Context, broker *privileged.Broker, item models.StartupItem, enabled bool) error {
+	if broker == nil {
+		return fmt.Errorf("privileged helper unavailable")
+	}
+
+	command := privileged.CmdDisableDaemon
+	if enabled {
+		command = privileged.CmdEnableDaemon
+	}
+	res, err := broker.Run(ctx, privileged.Request{Command: command, Path: item.Path})
+	if err != nil {
+		return fmt.Errorf("failed to run privileged helper: %w", err)
+	}
+	if !res.OK {
+		return fmt.Errorf("failed to update %s: %s", item.Label, res.Error)
+	}
+	return nil
+}
+
+func removeStartupItem(item models.StartupItem) error {
+	// A login item's path is the app itself, which must stay
+	if item.Kind == StartupKindLoginItem {
+		return removeLoginItem(item)
+	}
+
+	_, _ = runLaunchctl("bootout", fmt.Sprintf("gui/%d", os.Getuid()), item.Path)
+	if err := moveToTrash(item.Path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", item.Path, err)
+	}
+	return nil
+}
+
+func runLaunchctl(args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), launchctlTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "launchctl", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("%v (%s)", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,88 @@
+//go:build darwin
+
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLaunchdPlist(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>com.example.agent</string>
+	<key>ProgramArguments</key>
+	<array>
+		<string>/Applications/Example.app/Contents/MacOS/agent</string>
+		<string>--background</string>
+	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+</dict>
+</plist>`)
+
+	job, err := parseLaunchdPlist(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if job.Label != "com.example.agent" || !job.RunAtLoad || job.Disabled {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	wantArgs := []string{"/Applications/Example.app/Contents/MacOS/agent", "--background"}
+	if !reflect.DeepEqual(job.ProgramArguments, wantArgs) {
+		t.Fatalf("got arguments %v, want %v", job.ProgramArguments, wantArgs)
+	}
+	if !keepAliveEnabled(job.KeepAlive) {
+		t.Fatalf("conditional KeepAlive should count as enabled: %#v", job.KeepAlive)
+	}
+
+	if _, err := parseLaunchdPlist([]byte("not a plist")); err == nil {
+		t.Fatal("expected an error for invalid data")
+	}
+}
+
+func TestKeepAliveEnabled(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{value: true, want: true},
+		{value: false, want: false},
+		{value: map[string]interface{}{"NetworkState": true}, want: true},
+		{value: map[string]interface{}{}, want: false},
+		{value: nil, want: false},
+		{value: "yes", want: false},
+	}
+	for _, tt := range tests {
+		if got := keepAliveEnabled(tt.value); got != tt.want {
+			t.Fatalf("keepAliveEnabled(%#v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseLaunchctlDisabled(t *testing.T) {
+	output := `disabled services = {
+	"com.example.agent" => disabled
+	"com.example.helper" => enabled
+	"com.example.legacy" => true
+	"com.example.other" => false
+}
+`
+	want := map[string]bool{
+		"com.example.agent":  true,
+		"com.example.helper": false,
+		"com.example.legacy": true,
+		"com.example.other":  false,
+	}
+	if got := parseLaunchctlDisabled(output); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,297 @@
+//go:build linux
+
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
+)
+
+type autostartDir struct {
+	path  string
+	scope string
+}
+
+type systemdUnitDir struct {
+	path  string
+	scope string
+}
+
+func autostartDirs() []autostartDir {
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+
+	return []autostartDir{
+		{path: filepath.Join(configHome, "autostart"), scope: "user"},
+		{path: "/etc/xdg/autostart", scope: "system"},
+	}
+}
+
+func systemdUserUnitDirs() []systemdUnitDir {
+	home := os.Getenv("HOME")
+
+	return []systemdUnitDir{
+		{path: filepath.Join(home, ".config", "systemd", "user"), scope: "user"},
+		{path: filepath.Join(home, ".local", "share", "systemd", "user"), scope: "user"},
+		{path: "/etc/systemd/user", scope: "system"},
+		{path: "/usr/lib/systemd/user", scope: "system"},
+	}
+}
+
+func listStartupItems() ([]models.StartupItem, error) {
+	items := listAutostartItems()
+	items = append(items, listSystemdUserUnits()...)
+	return items, nil
+}
+
+// Autostart entries
+
+func listAutostartItems() []models.StartupItem {
+	seen := make(map[string]bool)
+	var items []models.StartupItem
+
+	// User entries shadow system entries with the same file name
+	for _, dir := range autostartDirs() {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".desktop") || seen[entry.Name()] {
+				continue
+			}
+			seen[entry.Name()] = true
+
+			path := filepath.Join(dir.path, entry.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+
+			desktop := parseDesktopEntry(string(data))
+			label := desktop.Name
+			if label == "" {
+				label = strings.TrimSuffix(entry.Name(), ".desktop")
+			}
+
+			args := strings.Fields(desktop.Exec)
+			program := ""
+			if len(args) > 0 {
+				program = desktopExecBinary(path)
+				if program == "" {
+					program = strings.Trim(args[0], `"`)
+				}
+			}
+
+			items = append(items, models.StartupItem{
+				ID:        path,
+				Label:     label,
+				Kind:      StartupKindAutostart,
+				Scope:     dir.scope,
+				Path:      path,
+				Program:   program,
+				Arguments: args,
+				RunAtLoad: true,
+				Enabled:   !desktop.Hidden && autostartEnabled(string(data)),
+				// System entries are disabled through a user override
+				Editable: true,
+			})
+		}
+	}
+
+	return items
+}
+
+func autostartEnabled(data string) bool {
+	for _, line := range strings.Split(data, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "X-GNOME-Autostart-enabled="); ok {
+			return strings.TrimSpace(value) != "false"
+		}
+	}
+	return true
+}
+
+// setDesktopKey sets key=value in the [Desktop Entry] group, adding it if missing
+func setDesktopKey(data, key, value string) string {
+	lines := strings.Split(data, "\n")
+	inEntry := false
+	insertAt := -1
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			if inEntry {
+				break
+			}
+			inEntry = trimmed == "[Desktop Entry]"
+			if inEntry {
+				insertAt = i + 1
+			}
+			continue
+		}
+		if inEntry && strings.HasPrefix(trimmed, key+"=") {
+			lines[i] = key + "=" + value
+			return strings.Join(lines, "\n")
+		}
+	}
+
+	if insertAt < 0 {
+		return "[Desktop Entry]\n" + key + "=" + value + "\n" + data
+	}
+	lines = append(lines[:insertAt], append([]string{key + "=" + value}, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n")
+}
+
+func setAutostartEnabled(item models.StartupItem, enabled bool) error {
+	data, err := os.ReadFile(item.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", item.Path, err)
+	}
+
+	content := setDesktopKey(string(data), "Hidden", fmt.Sprintf("%t", !enabled))
+	content = setDesktopKey(content, "X-GNOME-Autostart-enabled", fmt.Sprintf("%t", enabled))
+
+	// System entries are overridden from the user's autostart folder
+	target := item.Path
+	if item.Scope != "user" {
+		target = filepath.Join(autostartDirs()[0].path, filepath.Base(item.Path))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create autostart directory: %w", err)
+		}
+	}
+
+	if err := os.WriteFile(target, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+// systemd user units
+
+func listSystemdUserUnits() []models.StartupItem {
+	output, err := runPackageQuery("systemctl", "--user", "list-unit-files", "--type=service", "--no-legend", "--no-pager")
+	if err != nil {
+		return nil
+	}
+	states := parseUnitFileStates(output)
+
+	seen := make(map[string]bool)
+	var items []models.StartupItem
+
+	for _, dir := range systemdUserUnitDirs() {
+		entries, err := os.ReadDir(dir.path)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || !strings.HasSuffix(name, ".service") || strings.Contains(name, "@") || seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			state := states[name]
+			// Vendor units are only interesting when something enabled them
+			if dir.scope == "system" && state != "enabled" {
+				continue
+			}
+			if state != "enabled" && state != "disabled" {
+				continue
+			}
+
+			path := filepath.Join(dir.path, name)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			args := unitExecStart(string(data))
+			program := ""
+			if len(args) > 0 {
+				program = args[0]
+			}
+
+			items = append(items, models.StartupItem{
+				ID:        path,
+				Label:     name,
+				Kind:      StartupKindSystemd,
+				Scope:     dir.scope,
+				Path:      path,
+				Program:   program,
+				Arguments: args,
+				RunAtLoad: true,
+				KeepAlive: strings.Contains(string(data), "Restart=always"),
+				Enabled:   state == "enabled",
+				Editable:  true,
+			})
+		}
+	}
+
+	return items
+}
+
+// parseUnitFileStates parses `systemctl list-unit-files --no-legend` into unit => state
+func parseUnitFileStates(output string) map[string]string {
+	states := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 {
+			states[fields[0]] = fields[1]
+		}
+	}
+	return states
+}
+
+// unitExecStart returns the command line of the first ExecStart= in a unit file
+func unitExecStart(data string) []string {
+	for _, line := range strings.Split(data, "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), "ExecStart=")
+		if !ok {
+			continue
+		}
+		args := strings.Fields(value)
+		if len(args) > 0 {
+			// Strip systemd exec prefixes such as "-" or "@"
+			args[0] = strings.TrimLeft(args[0], "-@:+!")
+		}
+		return args
+	}
+	return nil
+}
+
+func setStartupItemEnabled(_ context.Context, _ *privileged.Broker, item models.StartupItem, enabled bool) error {
+	if item.Kind == StartupKindAutostart {
+		return setAutostartEnabled(item, enabled)
+	}
+
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+	if _, err := runPackageQuery("systemctl", "--user", action, "--now", item.Label); err != nil {
+		return fmt.Errorf("failed to %s %s: %w", action, item.Label, err)
+	}
+	return nil
+}
+
+func removeStartupItem(item models.StartupItem) error {
+	if item.Kind == StartupKindSystemd {
+		_, _ = runPackageQuery("systemctl", "--user", "disable", "--now", item.Label)
+	}
+	if err := moveToTrash(item.Path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", item.Path, err)
+	}
+	if item.Kind == StartupKindSystemd {
+		_, _ = runPackageQuery("systemctl", "--user", "daemon-reload")
+	}
+	return nil
+}
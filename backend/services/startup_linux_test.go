@@ -0,0 +1,153 @@
+//go:build linux
+
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAutostartEnabled(t *testing.T) {
+	tests := []struct {
+		data string
+		want bool
+	}{
+		{data: "[Desktop Entry]\nName=Agent\n", want: true},
+		{data: "[Desktop Entry]\nX-GNOME-Autostart-enabled=true\n", want: true},
+		{data: "[Desktop Entry]\nX-GNOME-Autostart-enabled=false\n", want: false},
+	}
+	for _, tt := range tests {
+		if got := autostartEnabled(tt.data); got != tt.want {
+			t.Fatalf("autostartEnabled(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestSetDesktopKey(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{
+			name: "replace",
+			data: "[Desktop Entry]\nName=Agent\nHidden=false\n",
+			want: "[Desktop Entry]\nName=Agent\nHidden=true\n",
+		},
+		{
+			name: "insert",
+			data: "[Desktop Entry]\nName=Agent\n",
+			want: "[Desktop Entry]\nHidden=true\nName=Agent\n",
+		},
+		{
+			name: "other group untouched",
+			data: "[Desktop Entry]\nName=Agent\n[Desktop Action new]\nHidden=false\n",
+			want: "[Desktop Entry]\nHidden=true\nName=Agent\n[Desktop Action new]\nHidden=false\n",
+		},
+		{
+			name: "no entry group",
+			data: "Name=Agent\n",
+			want: "[Desktop Entry]\nHidden=true\nName=Agent\n",
+		},
+	}
+	for _, tt := range tests {
+		if got := setDesktopKey(tt.data, "Hidden", "true"); got != tt.want {
+			t.Fatalf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseUnitFileStates(t *testing.T) {
+	output := "agent.service            enabled  enabled\n" +
+		"backup.service           disabled enabled\n" +
+		"sync@.service            static   -\n" +
+		"\n"
+	want := map[string]string{
+		"agent.service":  "enabled",
+		"backup.service": "disabled",
+		"sync@.service":  "static",
+	}
+	if got := parseUnitFileStates(output); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestUnitExecStart(t *testing.T) {
+	tests := []struct {
+		data string
+		want []string
+	}{
+		{
+			data: "[Service]\nExecStart=/usr/bin/agent --daemon\nExecStart=/usr/bin/other\n",
+			want: []string{"/usr/bin/agent", "--daemon"},
+		},
+		{
+			data: "[Service]\n  ExecStart=-/usr/bin/agent\n",
+			want: []string{"/usr/bin/agent"},
+		},
+		{
+			data: "[Service]\nExecStartPre=/usr/bin/prepare\n",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		if got := unitExecStart(tt.data); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("unitExecStart(%q) = %v, want %v", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestListAutostartItems(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	dir := filepath.Join(configHome, "autostart")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"agent.desktop":    "[Desktop Entry]\nName=Agent\nExec=/opt/agent/bin/agent --tray\n",
+		"hidden.desktop":   "[Desktop Entry]\nName=Hidden\nExec=/usr/bin/hidden\nHidden=true\n",
+		"disabled.desktop": "[Desktop Entry]\nExec=/usr/bin/disabled\nX-GNOME-Autostart-enabled=false\n",
+		"notes.txt":        "not an entry",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := make(map[string]struct {
+		label   string
+		program string
+		enabled bool
+	})
+	for _, item := range listAutostartItems() {
+		if item.Scope != "user" {
+			continue
+		}
+		if item.Kind != StartupKindAutostart || item.ID != item.Path {
+			t.Fatalf("unexpected item: %+v", item)
+		}
+		got[filepath.Base(item.Path)] = struct {
+			label   string
+			program string
+			enabled bool
+		}{item.Label, item.Program, item.Enabled}
+	}
+
+	want := map[string]struct {
+		label   string
+		program string
+		enabled bool
+	}{
+		"agent.desktop":    {label: "Agent", program: "/opt/agent/bin/agent", enabled: true},
+		"hidden.desktop":   {label: "Hidden", program: "/usr/bin/hidden", enabled: false},
+		"disabled.desktop": {label: "disabled", program: "/usr/bin/disabled", enabled: false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMoveToXDGTrashWritesTrashInfo(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+
+	src := filepath.Join(t.TempDir(), "my agent.desktop")
+	for i := 0; i < 2; i++ {
+		if err := os.WriteFile(src, []byte("[Desktop Entry]\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := moveToXDGTrash(src, time.Date(2026, 3, 4, 5, 6, 7, 0, time.Local)); err != nil {
+			t.Fatalf("move %d: %v", i, err)
+		}
+	}
+
+	for _, name := range []string{"my agent.desktop", "my agent.desktop.1"} {
+		if _, err := os.Stat(filepath.Join(dataHome, "Trash", "files", name)); err != nil {
+			t.Fatalf("%s not in trash: %v", name, err)
+		}
+		info, err := os.ReadFile(filepath.Join(dataHome, "Trash", "info", name+".trashinfo"))
+		if err != nil {
+			t.Fatalf("%s has no trashinfo: %v", name, err)
+		}
+		want := "[Trash Info]\nPath=" + filepath.Dir(src) + "/my%20agent.desktop\nDeletionDate=2026-03-04T05:06:07\n"
+		if string(info) != want {
+			t.Fatalf("%s: got %q, want %q", name, info, want)
+		}
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("source still exists: %v", err)
+	}
+}
+
+func TestMoveToTrashKeepsFileOnFailure(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	// A file where the trash directory should go makes the XDG trash unusable
+	blocked := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(blocked, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_DATA_HOME", blocked)
+
+	src := filepath.Join(t.TempDir(), "agent.plist")
+	if err := os.WriteFile(src, []byte("plist"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveToTrash(src); err == nil {
+		t.Fatal("expected an error when no trash is usable")
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Fatalf("source was removed: %v", err)
+	}
+}
+
+func TestMoveToTrashUsesHomeTrash(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	trash := filepath.Join(home, ".Trash")
+	if err := os.Mkdir(trash, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(home, "agent.plist")
+	if err := os.WriteFile(src, []byte("plist"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := moveToTrash(src); err != nil {
+		t.Fatalf("moveToTrash failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(trash, "agent.plist")); err != nil {
+		t.Fatalf("file not in trash: %v", err)
+	}
+	if _, err := os.Stat(src); !os.IsNotExist(err) {
+		t.Fatalf("source still exists: %v", err)
+	}
+}
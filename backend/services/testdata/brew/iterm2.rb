@@ -0,0 +1,12 @@
+cask "iterm2" do
+  version "3.5.4"
+  sha256 "d2a4b0c1e3f5a7b9c1d3e5f7a9b1c3d5e7f9a1b3c5d7e9f1a3b5c7d9e1f3a5b7"
+
+  url "https://iterm2.com/downloads/stable/iTerm2-#{version.dots_to_underscores}.zip"
+  name "iTerm2"
+  homepage "https://iterm2.com/"
+
+  app "iTerm.app"
+
+  zap trash: "~/Library/Preferences/com.googlecode.iterm2.plist"
+end
@@ -0,0 +1,9 @@
+cask "parallels" do
+  version "19.4.1-54985"
+
+  app "Parallels Desktop.app"
+    app "Parallels Toolbox.app", target: "Toolbox/Parallels Toolbox.app"
+
+  # app "Commented.app"
+  uninstall delete: "/Applications/Parallels Desktop.app"
+end
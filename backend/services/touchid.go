@@ -4,21 +4,19 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
 
 	"mole-wails/backend/models"
+	"mole-wails/backend/privileged"
 )
 
 type TouchIDService struct {
-	scriptsPath string
-	ctx         context.Context
+	ctx    context.Context
+	broker *privileged.Broker
 }
 
-func NewTouchIDService(scriptsPath string) *TouchIDService {
+func NewTouchIDService(broker *privileged.Broker) *TouchIDService {
 	return &TouchIDService{
-		scriptsPath: scriptsPath,
+		broker: broker,
 	}
 }
 
@@ -28,27 +26,20 @@ func (s *TouchIDService) SetContext(ctx context.Context) {
 
 // GetStatus checks if Touch ID for sudo is enabled
 func (s *TouchIDService) GetStatus() (*models.TouchIDStatus, error) {
-	configPath := "/etc/pam.d/sudo"
-	pamModulePath := "/usr/lib/pam/pam_tid.so.2"
-
 	// Check if pam_tid module exists
-	_, err := os.Stat(pamModulePath)
+	_, err := os.Stat(privileged.TouchIDPamModule)
 	available := err == nil
 
-	// Check if Touch ID is enabled in config
-	data, err := os.ReadFile(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read sudoers file: %w", err)
+	enabled, configPath := privileged.TouchIDSudoEnabled()
+	if configPath == "" {
+		configPath = privileged.TouchIDConfigFile()
 	}
 
-	content := string(data)
-	enabled := strings.Contains(content, "pam_tid.so")
-
 	status := &models.TouchIDStatus{
 		Enabled:       enabled,
 		Available:     available,
 		Status:        "Disabled",
-		PamModulePath: pamModulePath,
+		PamModulePath: privileged.TouchIDPamModule,
 		ConfigPath:    configPath,
 	}
 
@@ -59,28 +50,38 @@ func (s *TouchIDService) GetStatus() (*models.TouchIDStatus, error) {
 	return status, nil
 }
 
-// Enable enables Touch ID for sudo
+// Enable enables Touch ID for sudo through the privileged helper
 func (s *TouchIDService) Enable() error {
-	scriptPath := filepath.Join(s.scriptsPath, "bin", "touchid.sh")
-
-	cmd := exec.Command("/bin/bash", scriptPath, "enable")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to enable Touch ID: %w, output: %s", err, string(output))
+	if err := s.run(privileged.CmdEnableTouchIDSudo); err != nil {
+		return fmt.Errorf("failed to enable Touch ID: %w", err)
 	}
-
 	return nil
 }
 
-// Disable disables Touch ID for sudo
+// Disable disables Touch ID for sudo through the privileged helper
 func (s *TouchIDService) Disable() error {
-	scriptPath := filepath.Join(s.scriptsPath, "bin", "touchid.sh")
+	if err := s.run(privileged.CmdDisableTouchIDSudo); err != nil {
+		return fmt.Errorf("failed to disable Touch ID: %w", err)
+	}
+	return nil
+}
 
-	cmd := exec.Command("/bin/bash", scriptPath, "disable")
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("failed to disable Touch ID: %w, output: %s", err, string(output))
+func (s *TouchIDService) run(command string) error {
+	if s.broker == nil {
+		return fmt.Errorf("administrator privileges are not available")
+	}
+
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
+	res, err := s.broker.Run(ctx, privileged.Request{Command: command})
+	if err != nil {
+		return err
+	}
+	if !res.OK {
+		return fmt.Errorf("%s", res.Error)
+	}
 	return nil
 }
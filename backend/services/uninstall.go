@@ -1,27 +1,49 @@
 package services
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
+	"howett.net/plist"
 	"mole-wails/backend/models"
 )
 
+// Application sources reported in models.Application.Source
+const (
+	AppSourceManual   = "manual"
+	AppSourceAppStore = "appstore"
+	AppSourceCask     = "cask"
+	AppSourceDesktop  = "desktop"
+	AppSourceFlatpak  = "flatpak"
+	AppSourceSnap     = "snap"
+	AppSourceAppImage = "appimage"
+)
+
 type UninstallService struct {
 	scriptsPath string
 	ctx         context.Context
+	usage       UsageSource
+	inventoryMu sync.Mutex
+}
+
+// appCandidate is an installed application located on disk but not yet measured
+type appCandidate struct {
+	path     string // Inventory key; its mtime changes when the app is replaced
+	metaPath string // Info.plist, .desktop or package metadata; its mtime changes on update
+	measure  func() models.Application
+	lastUsed func() time.Time
 }
 
 func NewUninstallService(scriptsPath string) *UninstallService {
 	return &UninstallService{
 		scriptsPath: scriptsPath,
+		usage:       newPlatformUsageSource(),
 	}
 }
 
@@ -29,110 +51,162 @@ func (s *UninstallService) SetContext(ctx context.Context) {
 	s.ctx = ctx
 }
 
-// ScanApplications scans installed applications
+// ScanApplications scans installed applications, re-measuring only bundles
+// that changed since the last scan unless forceRescan is set
 func (s *UninstallService) ScanApplications(forceRescan bool) ([]models.Application, error) {
-	scriptPath := filepath.Join(s.scriptsPath, "bin", "uninstall.sh")
+	s.inventoryMu.Lock()
+	defer s.inventoryMu.Unlock()
 
-	args := []string{scriptPath}
-	if forceRescan {
-		args = append(args, "--force-rescan")
+	cached := inventoryFile{Apps: map[string]inventoryEntry{}}
+	if !forceRescan {
+		cached = loadInventory()
 	}
 
-	// We need to run the script and parse its output
-	// For now, we'll scan applications directories directly
-	var apps []models.Application
+	inventory, apps := s.measureCandidates(s.findAppCandidates(), cached)
 
-	appDirs := []string{
-		"/Applications",
-		filepath.Join(os.Getenv("HOME"), "Applications"),
+	// Cask ownership can change without touching the bundle, so it is cached
+	// against the Caskroom folders instead
+	stamp := caskroomStamp()
+	casks := cached.Casks
+	if casks == nil || !sameCaskroomStamp(cached.CaskroomStamp, stamp) {
+		casks = loadInstalledCasks()
 	}
 
-	for _, dir := range appDirs {
-		entries, err := os.ReadDir(dir)
-		if err != nil {
-			continue
-		}
+	_ = saveInventory(inventoryFile{Apps: inventory, Casks: casks, CaskroomStamp: stamp})
+	markCaskApps(apps, casks)
 
-		for _, entry := range entries {
-			if !strings.HasSuffix(entry.Name(), ".app") {
-				continue
-			}
+	return apps, nil
+}
 
-			appPath := filepath.Join(dir, entry.Name())
-			info, err := entry.Info()
-			if err != nil {
-				continue
+// measureCandidates returns the inventory entry of every candidate, measuring
+// only those whose bundle or metadata mtime differs from the cached entry
+func (s *UninstallService) measureCandidates(candidates []appCandidate, cached inventoryFile) (map[string]inventoryEntry, []models.Application) {
+	inventory := make(map[string]inventoryEntry, len(candidates))
+	apps := make([]models.Application, 0, len(candidates))
+
+	for i, candidate := range candidates {
+		bundleModTime := modTime(candidate.path)
+		plistModTime := modTime(candidate.metaPath)
+
+		entry, ok := cached.Apps[candidate.path]
+		measured := !ok || !entry.fresh(bundleModTime, plistModTime)
+		if measured {
+			app := candidate.measure()
+			if app.LastModified.IsZero() {
+				app.LastModified = bundleModTime
+			}
+			entry = inventoryEntry{
+				App:           app,
+				BundleModTime: bundleModTime,
+				PlistModTime:  plistModTime,
 			}
+		}
 
-			// Get app size
-			size, _ := s.getDirSize(appPath)
+		if candidate.lastUsed != nil {
+			entry.App.LastUsed = candidate.lastUsed()
+		}
+		entry.App.Age = s.calculateAge(entry.App.LastModified)
 
-			// Calculate age
-			age := s.calculateAge(info.ModTime())
+		inventory[candidate.path] = entry
+		apps = append(apps, entry.App)
 
-			apps = append(apps, models.Application{
-				Name:         strings.TrimSuffix(entry.Name(), ".app"),
-				BundleID:     s.getBundleID(appPath),
-				Path:         appPath,
-				Size:         size,
-				LastModified: info.ModTime(),
-				Age:          age,
+		if measured && s.ctx != nil {
+			runtime.EventsEmit(s.ctx, "uninstall:scan-progress", models.AppScanProgress{
+				App:     entry.App,
+				Scanned: i + 1,
+				Total:   len(candidates),
 			})
 		}
 	}
 
-	return apps, nil
+	return inventory, apps
 }
 
 // UninstallApps uninstalls selected applications
 func (s *UninstallService) UninstallApps(apps []string) error {
 	scriptPath := filepath.Join(s.scriptsPath, "bin", "uninstall.sh")
 
-	for i, app := range apps {
-		// Execute uninstall script for each app
-		cmd := exec.Command("/bin/bash", scriptPath, app)
-
-		stdout, err := cmd.StdoutPipe()
-		if err != nil {
-			return fmt.Errorf("failed to create stdout pipe: %w", err)
+	installed, err := s.ScanApplications(false)
+	if err != nil {
+		return fmt.Errorf("failed to scan applications: %w", err)
+	}
+	casks := make(map[string]models.Application)
+	for _, app := range installed {
+		if app.CaskToken != "" {
+			casks[app.BundleID] = app
 		}
+	}
+
+	result := models.UninstallResult{
+		AppsRemoved: len(apps),
+	}
 
-		if err := cmd.Start(); err != nil {
-			return fmt.Errorf("failed to start uninstall: %w", err)
+	for i, app := range apps {
+		percent := ((i + 1) * 100) / len(apps)
+
+		// Cask-managed apps go through brew to keep Homebrew's records consistent
+		if caskApp, ok := casks[app]; ok {
+			if err := s.uninstallCask(caskApp, percent); err != nil {
+				return err
+			}
+			continue
 		}
 
-		// Stream progress
-		scanner := bufio.NewScanner(stdout)
+		// Execute uninstall script for each app
 		filesRemoved := 0
+		var spaceFreed int64
+		var failure string
 
-		for scanner.Scan() {
-			line := scanner.Text()
+		err := runScript(scriptPath, []string{app}, func(ev ScriptEvent) {
+			progress := models.UninstallProgress{
+				App:     app,
+				Message: ev.Message,
+				Level:   "info",
+				Percent: percent,
+			}
 
-			if strings.Contains(line, "✓ Removed") {
+			switch ev.Type {
+			case ScriptEventFileRemoved:
 				filesRemoved++
+				spaceFreed += ev.Size
+				progress.Message = "Removed " + ev.Path
+			case ScriptEventTaskStart:
+				progress.Step = ev.Name
+				progress.Message = "Uninstalling " + ev.Name
+			case ScriptEventTaskFinish:
+				progress.Step = ev.Task
+				if ev.Status == TaskStatusFailed {
+					failure = ev.Message
+					progress.Level = "error"
+				}
+				if progress.Message == "" {
+					progress.Message = ev.Status
+				}
+			case ScriptEventWarning:
+				progress.Level = "warning"
+			case ScriptEventError:
+				progress.Level = "error"
 			}
 
-			percent := ((i + 1) * 100) / len(apps)
-
-			progress := models.UninstallProgress{
-				App:          app,
-				Message:      line,
-				Percent:      percent,
-				FilesRemoved: filesRemoved,
+			// Legacy events duplicate the output line they were parsed from
+			if ev.Legacy && ev.Type != ScriptEventWarning {
+				return
 			}
 
+			progress.FilesRemoved = filesRemoved
+			progress.SpaceFreed = spaceFreed
 			if s.ctx != nil {
 				runtime.EventsEmit(s.ctx, "uninstall:progress", progress)
 			}
-		}
-
-		if err := cmd.Wait(); err != nil {
+		})
+		if err != nil {
 			return fmt.Errorf("uninstall failed for %s: %w", app, err)
 		}
-	}
-
-	result := models.UninstallResult{
-		AppsRemoved: len(apps),
+		if failure != "" {
+			return fmt.Errorf("uninstall failed for %s: %s", app, failure)
+		}
+		result.FilesRemoved += filesRemoved
+		result.SpaceFreed += spaceFreed
 	}
 
 	if s.ctx != nil {
@@ -144,18 +218,10 @@ func (s *UninstallService) UninstallApps(apps []string) error {
 
 // GetRelatedFiles finds all files related to an application
 func (s *UninstallService) GetRelatedFiles(bundleID string) ([]string, error) {
-	// Search common locations for app-related files
-	searchPaths := []string{
-		filepath.Join(os.Getenv("HOME"), "Library", "Application Support"),
-		filepath.Join(os.Getenv("HOME"), "Library", "Caches"),
-		filepath.Join(os.Getenv("HOME"), "Library", "Preferences"),
-		filepath.Join(os.Getenv("HOME"), "Library", "Logs"),
-		filepath.Join(os.Getenv("HOME"), "Library", "Cookies"),
-	}
-
 	var relatedFiles []string
 
-	for _, searchPath := range searchPaths {
+	// Search common locations for app-related files
+	for _, searchPath := range relatedFileSearchPaths() {
 		entries, err := os.ReadDir(searchPath)
 		if err != nil {
 			continue
@@ -189,17 +255,27 @@ func (s *UninstallService) getDirSize(path string) (int64, error) {
 	return size, err
 }
 
-func (s *UninstallService) getBundleID(appPath string) string {
-	plistPath := filepath.Join(appPath, "Contents", "Info.plist")
-
-	// Use plutil to read bundle ID
-	cmd := exec.Command("plutil", "-extract", "CFBundleIdentifier", "raw", plistPath)
-	output, err := cmd.Output()
+// readBundleInfo reads the bundle ID and version from an app's Info.plist
+func (s *UninstallService) readBundleInfo(appPath string) (bundleID, version string) {
+	data, err := os.ReadFile(filepath.Join(appPath, "Contents", "Info.plist"))
 	if err != nil {
-		return "unknown"
+		return "unknown", ""
+	}
+
+	var info struct {
+		BundleID     string `plist:"CFBundleIdentifier"`
+		ShortVersion string `plist:"CFBundleShortVersionString"`
+		Version      string `plist:"CFBundleVersion"`
+	}
+	if _, err := plist.Unmarshal(data, &info); err != nil || info.BundleID == "" {
+		return "unknown", ""
 	}
 
-	return strings.TrimSpace(string(output))
+	version = info.ShortVersion
+	if version == "" {
+		version = info.Version
+	}
+	return info.BundleID, version
 }
 
 func (s *UninstallService) calculateAge(modTime time.Time) string {
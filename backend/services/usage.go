@@ -0,0 +1,84 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// AppUsage describes how recently and how often an application was used
+type AppUsage struct {
+	LastUsed time.Time
+	UseCount int
+}
+
+// UsageSource reports real usage data for installed applications
+type UsageSource interface {
+	Usage(app models.Application) (AppUsage, error)
+}
+
+// SetUsageSource replaces the platform usage source
+func (s *UninstallService) SetUsageSource(source UsageSource) {
+	s.usage = source
+}
+
+// GetUnusedApps ranks apps not used for at least minMonths by size × months unused.
+// Apps without usage data follow unranked, largest first.
+func (s *UninstallService) GetUnusedApps(minMonths int) ([]models.UnusedApp, error) {
+	apps, err := s.ScanApplications(false)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildUnusedReport(apps, s.usage, minMonths, time.Now()), nil
+}
+
+// Helper functions
+
+func buildUnusedReport(apps []models.Application, source UsageSource, minMonths int, now time.Time) []models.UnusedApp {
+	var report, unknown []models.UnusedApp
+
+	for _, app := range apps {
+		entry := models.UnusedApp{App: app}
+
+		// The bundle modification time says nothing about use, so apps
+		// without usage data are not ranked
+		var usage AppUsage
+		var err error
+		if source != nil {
+			usage, err = source.Usage(app)
+		}
+		if source == nil || err != nil || usage.LastUsed.IsZero() {
+			unknown = append(unknown, entry)
+			continue
+		}
+
+		entry.LastUsed = usage.LastUsed
+		entry.UseCount = usage.UseCount
+		entry.UsageKnown = true
+		entry.MonthsUnused = monthsBetween(usage.LastUsed, now)
+		if entry.MonthsUnused < float64(minMonths) {
+			continue
+		}
+
+		entry.Score = float64(app.Size) * entry.MonthsUnused
+		report = append(report, entry)
+	}
+
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].Score > report[j].Score
+	})
+	sort.SliceStable(unknown, func(i, j int) bool {
+		return unknown[i].App.Size > unknown[j].App.Size
+	})
+
+	return append(report, unknown...)
+}
+
+func monthsBetween(from, to time.Time) float64 {
+	if from.IsZero() || !to.After(from) {
+		return 0
+	}
+	return to.Sub(from).Hours() / 24 / 30
+}
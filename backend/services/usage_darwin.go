@@ -0,0 +1,65 @@
+//go:build darwin
+
+package services
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+const mdlsTimeout = 5 * time.Second
+
+// spotlightUsageSource reads last-used dates from Spotlight metadata
+type spotlightUsageSource struct{}
+
+func newPlatformUsageSource() UsageSource {
+	return &spotlightUsageSource{}
+}
+
+func (spotlightUsageSource) Usage(app models.Application) (AppUsage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), mdlsTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "mdls", "-name", "kMDItemLastUsedDate", "-name", "kMDItemUseCount", app.Path)
+	output, err := cmd.Output()
+	if err != nil {
+		return AppUsage{}, err
+	}
+
+	return parseMdlsUsage(string(output)), nil
+}
+
+// parseMdlsUsage parses `mdls -name kMDItemLastUsedDate -name kMDItemUseCount` output
+func parseMdlsUsage(output string) AppUsage {
+	var usage AppUsage
+
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if value == "(null)" {
+			continue
+		}
+
+		switch key {
+		case "kMDItemLastUsedDate":
+			if t, err := time.Parse("2006-01-02 15:04:05 -0700", value); err == nil {
+				usage.LastUsed = t
+			}
+		case "kMDItemUseCount":
+			if n, err := strconv.Atoi(value); err == nil {
+				usage.UseCount = n
+			}
+		}
+	}
+
+	return usage
+}
@@ -0,0 +1,196 @@
+//go:build linux
+
+package services
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// desktopUsageSource estimates usage from the launch history desktop apps
+// record in recently-used.xbel, and from access times of the .desktop entry
+// and the binary it launches
+type desktopUsageSource struct {
+	mu      sync.Mutex
+	modTime time.Time
+	history map[string]AppUsage // By program name, see recentlyUsedKey
+}
+
+func newPlatformUsageSource() UsageSource {
+	return &desktopUsageSource{}
+}
+
+func (d *desktopUsageSource) Usage(app models.Application) (AppUsage, error) {
+	var program string
+	if strings.HasSuffix(app.Path, ".desktop") {
+		program = desktopExecBinary(app.Path)
+	}
+
+	usage := d.launchHistory(app, program)
+
+	// Inventory sources already resolved the most telling path for this app
+	lastUsed := app.LastUsed
+	if lastUsed.IsZero() {
+		lastUsed = latestAccessTime(app.Path, program)
+	}
+	if lastUsed.After(usage.LastUsed) {
+		usage.LastUsed = lastUsed
+	}
+
+	if usage.LastUsed.IsZero() {
+		return AppUsage{}, fmt.Errorf("no usage data for %s", app.Path)
+	}
+	return usage, nil
+}
+
+// launchHistory looks app up in recently-used.xbel by program and by name
+func (d *desktopUsageSource) launchHistory(app models.Application, program string) AppUsage {
+	history := d.loadHistory()
+	for _, key := range []string{recentlyUsedKey(program), recentlyUsedKey(app.Name)} {
+		if usage, ok := history[key]; key != "" && ok {
+			return usage
+		}
+	}
+	return AppUsage{}
+}
+
+// loadHistory reads recently-used.xbel again whenever it changed
+func (d *desktopUsageSource) loadHistory() map[string]AppUsage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	path := recentlyUsedPath()
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	if d.history != nil && info.ModTime().Equal(d.modTime) {
+		return d.history
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	d.history = parseRecentlyUsed(data)
+	d.modTime = info.ModTime()
+	return d.history
+}
+
+func recentlyUsedPath() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(os.Getenv("HOME"), ".local", "share")
+	}
+	return filepath.Join(dataHome, "recently-used.xbel")
+}
+
+// recentlyUsedBookmarks is the part of the XBEL format needed to read which
+// applications opened files, when and how often
+type recentlyUsedBookmarks struct {
+	Bookmarks []struct {
+		Applications []struct {
+			Name     string `xml:"name,attr"`
+			Exec     string `xml:"exec,attr"`
+			Modified string `xml:"modified,attr"`
+			Count    int    `xml:"count,attr"`
+		} `xml:"info>metadata>applications>application"`
+	} `xml:"bookmark"`
+}
+
+// parseRecentlyUsed sums the launches of each application over all
+// bookmarks, keyed by both its program and its name
+func parseRecentlyUsed(data []byte) map[string]AppUsage {
+	var doc recentlyUsedBookmarks
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+
+	history := make(map[string]AppUsage)
+	for _, bookmark := range doc.Bookmarks {
+		for _, app := range bookmark.Applications {
+			modified, err := time.Parse(time.RFC3339Nano, app.Modified)
+			if err != nil {
+				continue
+			}
+
+			var program string
+			if fields := strings.Fields(strings.Trim(app.Exec, `'"`)); len(fields) > 0 {
+				program = fields[0]
+			}
+			keys := []string{recentlyUsedKey(program), recentlyUsedKey(app.Name)}
+			for i, key := range keys {
+				if key == "" || (i > 0 && key == keys[0]) {
+					continue
+				}
+				usage := history[key]
+				usage.UseCount += app.Count
+				if modified.After(usage.LastUsed) {
+					usage.LastUsed = modified
+				}
+				history[key] = usage
+			}
+		}
+	}
+	return history
+}
+
+// recentlyUsedKey normalizes a program path or application name, so
+// "/usr/bin/firefox" matches the "firefox" recorded by the launcher
+func recentlyUsedKey(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	return strings.ToLower(filepath.Base(name))
+}
+
+// desktopExecBinary resolves the program named by the Exec= key of a .desktop file
+func desktopExecBinary(desktopPath string) string {
+	data, err := os.ReadFile(desktopPath)
+	if err != nil {
+		return ""
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), "Exec=")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			return ""
+		}
+		program := strings.Trim(fields[0], `"`)
+		if filepath.IsAbs(program) {
+			return program
+		}
+		if resolved, err := exec.LookPath(program); err == nil {
+			return resolved
+		}
+		return ""
+	}
+
+	return ""
+}
+
+func accessTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(stat.Atim.Sec, stat.Atim.Nsec)
+}
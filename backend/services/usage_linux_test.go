@@ -0,0 +1,63 @@
+//go:build linux
+
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+const recentlyUsedFixture = `<?xml version="1.0" encoding="UTF-8"?>
+<xbel version="1.0"
+      xmlns:bookmark="http://www.freedesktop.org/standards/desktop-bookmarks"
+      xmlns:mime="http://www.freedesktop.org/standards/shared-mime-info">
+  <bookmark href="file:///home/u/a.pdf" added="2026-01-01T10:00:00Z" modified="2026-01-02T10:00:00Z" visited="2026-01-02T10:00:00Z">
+    <info>
+      <metadata owner="http://freedesktop.org">
+        <mime:mime-type type="application/pdf"/>
+        <bookmark:applications>
+          <bookmark:application name="Document Viewer" exec="&apos;evince %u&apos;" modified="2026-01-02T10:00:00Z" count="2"/>
+          <bookmark:application name="Firefox" exec="&apos;firefox %u&apos;" modified="2026-01-01T10:00:00.5Z" count="1"/>
+        </bookmark:applications>
+      </metadata>
+    </info>
+  </bookmark>
+  <bookmark href="file:///home/u/b.pdf" added="2026-02-01T10:00:00Z" modified="2026-02-03T10:00:00Z" visited="2026-02-03T10:00:00Z">
+    <info>
+      <metadata owner="http://freedesktop.org">
+        <bookmark:applications>
+          <bookmark:application name="Document Viewer" exec="&apos;/usr/bin/evince %u&apos;" modified="2026-02-03T10:00:00Z" count="3"/>
+        </bookmark:applications>
+      </metadata>
+    </info>
+  </bookmark>
+</xbel>`
+
+func TestParseRecentlyUsed(t *testing.T) {
+	history := parseRecentlyUsed([]byte(recentlyUsedFixture))
+
+	tests := []struct {
+		key      string
+		count    int
+		lastUsed time.Time
+	}{
+		{"evince", 5, time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)},
+		{"document viewer", 5, time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)},
+		{"firefox", 1, time.Date(2026, 1, 1, 10, 0, 0, 5e8, time.UTC)},
+	}
+	for _, tt := range tests {
+		usage, ok := history[tt.key]
+		if !ok {
+			t.Fatalf("%s: missing from history", tt.key)
+		}
+		if usage.UseCount != tt.count || !usage.LastUsed.Equal(tt.lastUsed) {
+			t.Fatalf("%s: got %d uses at %v, want %d at %v", tt.key, usage.UseCount, usage.LastUsed, tt.count, tt.lastUsed)
+		}
+	}
+}
+
+func TestParseRecentlyUsedInvalid(t *testing.T) {
+	if history := parseRecentlyUsed([]byte("not xml")); len(history) != 0 {
+		t.Fatalf("expected no history, got %v", history)
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"mole-wails/backend/models"
+)
+
+// fakeUsageSource returns canned usage keyed by application path
+type fakeUsageSource struct {
+	entries map[string]AppUsage
+}
+
+func (f *fakeUsageSource) Usage(app models.Application) (AppUsage, error) {
+	usage, ok := f.entries[app.Path]
+	if !ok {
+		return AppUsage{}, fmt.Errorf("no usage data for %s", app.Path)
+	}
+	return usage, nil
+}
+
+func TestBuildUnusedReportRanksBySizeTimesMonths(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	apps := []models.Application{
+		{Name: "Small", Path: "/Applications/Small.app", Size: 100},
+		{Name: "Large", Path: "/Applications/Large.app", Size: 1000},
+		{Name: "Recent", Path: "/Applications/Recent.app", Size: 5000},
+	}
+	source := &fakeUsageSource{entries: map[string]AppUsage{
+		"/Applications/Small.app":  {LastUsed: now.AddDate(0, 0, -300), UseCount: 2},
+		"/Applications/Large.app":  {LastUsed: now.AddDate(0, 0, -90), UseCount: 7},
+		"/Applications/Recent.app": {LastUsed: now.AddDate(0, 0, -10)},
+	}}
+
+	report := buildUnusedReport(apps, source, 1, now)
+
+	if len(report) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(report))
+	}
+	if report[0].App.Name != "Large" || report[1].App.Name != "Small" {
+		t.Fatalf("unexpected order: %s, %s", report[0].App.Name, report[1].App.Name)
+	}
+	if report[0].MonthsUnused != 3 || report[0].Score != 3000 {
+		t.Fatalf("unexpected months/score: %v/%v", report[0].MonthsUnused, report[0].Score)
+	}
+	if !report[0].UsageKnown || report[0].UseCount != 7 {
+		t.Fatalf("expected known usage with 7 uses, got %+v", report[0])
+	}
+}
+
+func TestBuildUnusedReportLeavesUnknownUsageUnranked(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	apps := []models.Application{
+		{Name: "NoData", Path: "/Applications/NoData.app", Size: 10, LastModified: now.AddDate(-3, 0, 0)},
+		{Name: "Huge", Path: "/Applications/Huge.app", Size: 900, LastModified: now.AddDate(-5, 0, 0)},
+		{Name: "Used", Path: "/Applications/Used.app", Size: 1, LastModified: now},
+	}
+	source := &fakeUsageSource{entries: map[string]AppUsage{
+		"/Applications/Used.app": {LastUsed: now.AddDate(0, -6, 0)},
+	}}
+
+	report := buildUnusedReport(apps, source, 1, now)
+
+	if len(report) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(report))
+	}
+	if report[0].App.Name != "Used" || !report[0].UsageKnown {
+		t.Fatalf("ranked entry must come first, got %+v", report[0])
+	}
+	for _, entry := range report[1:] {
+		if entry.UsageKnown || entry.Score != 0 || entry.MonthsUnused != 0 || !entry.LastUsed.IsZero() {
+			t.Fatalf("bundle mtime must not be used as usage: %+v", entry)
+		}
+	}
+	if report[1].App.Name != "Huge" || report[2].App.Name != "NoData" {
+		t.Fatalf("unknown entries must follow largest first, got %s, %s", report[1].App.Name, report[2].App.Name)
+	}
+}
+
+func TestBuildUnusedReportWithoutSource(t *testing.T) {
+	apps := []models.Application{{Name: "A", Path: "/Applications/A.app", Size: 1}}
+
+	report := buildUnusedReport(apps, nil, 0, time.Now())
+
+	if len(report) != 1 || report[0].UsageKnown {
+		t.Fatalf("expected one entry with unknown usage, got %+v", report)
+	}
+}
@@ -2,14 +2,50 @@
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
 
+export function AnalyzeCancelScan():Promise<void>;
+
+export function AnalyzeClearCache():Promise<void>;
+
 export function AnalyzeDeletePath(arg1:string):Promise<void>;
 
+export function AnalyzeDeleteSnapshot(arg1:string):Promise<void>;
+
+export function AnalyzeDiffSnapshots(arg1:string,arg2:string,arg3:string,arg4:number):Promise<models.ScanDiff>;
+
+export function AnalyzeForecastVolumeFull(arg1:number):Promise<models.VolumeForecast>;
+
+export function AnalyzeGetCacheInfo():Promise<models.ScanCacheInfo>;
+
 export function AnalyzeGetLargeFiles(arg1:string,arg2:number):Promise<Array<models.FileEntry>>;
 
+export function AnalyzeGetScanTree(arg1:string,arg2:number,arg3:models.ScanOptions):Promise<models.ScanNode>;
+
+export function AnalyzeGetSizeTrends(arg1:number):Promise<Array<models.SizeTrend>>;
+
+export function AnalyzeListSnapshots(arg1:string):Promise<Array<models.ScanSnapshot>>;
+
 export function AnalyzeOpenInFinder(arg1:string):Promise<void>;
 
+export function AnalyzeSaveSnapshot(arg1:string,arg2:string,arg3:models.ScanOptions):Promise<models.ScanSnapshot>;
+
 export function AnalyzeScanDirectory(arg1:string):Promise<models.ScanResult>;
 
+export function AnalyzeScanDirectoryWithOptions(arg1:string,arg2:models.ScanOptions):Promise<models.ScanResult>;
+
+export function AnalyzeSetNodeBudget(arg1:number):Promise<void>;
+
+export function AnalyzeSunburst(arg1:string,arg2:number,arg3:number,arg4:number,arg5:models.ScanOptions):Promise<Array<models.SunburstArc>>;
+
+export function AnalyzeTreemap(arg1:string,arg2:number,arg3:number,arg4:number,arg5:number,arg6:models.ScanOptions):Promise<Array<models.TreemapRect>>;
+
+export function AnalyzeWatchCaches(arg1:boolean):Promise<void>;
+
+export function CheckApplyFix(arg1:string):Promise<models.CheckFixResult>;
+
+export function CheckRun(arg1:string):Promise<models.CheckResult>;
+
+export function CheckRunAll():Promise<Array<models.CheckResult>>;
+
 export function CleanExecute(arg1:Array<string>,arg2:boolean):Promise<void>;
 
 export function CleanGetWhitelist():Promise<Array<string>>;
@@ -20,12 +56,36 @@ export function CleanUpdateWhitelist(arg1:Array<string>):Promise<void>;
 
 export function OptimizeExecute(arg1:Array<string>):Promise<void>;
 
+export function OptimizeGetHistory():Promise<Array<models.OptimizeHistoryEntry>>;
+
 export function OptimizeGetTasks():Promise<Array<models.OptimizationTask>>;
 
 export function OptimizeGetWhitelist():Promise<Array<string>>;
 
 export function OptimizeUpdateWhitelist(arg1:Array<string>):Promise<void>;
 
+export function OrphansScan():Promise<Array<models.OrphanVendorGroup>>;
+
+export function PreferencesGetRepairLog():Promise<Array<models.PreferenceRepair>>;
+
+export function PreferencesRepair(arg1:Array<string>):Promise<Array<models.PreferenceRepair>>;
+
+export function PreferencesRestore(arg1:string):Promise<void>;
+
+export function PreferencesScanBroken():Promise<Array<models.BrokenPreference>>;
+
+export function PrivilegedAuthenticate():Promise<void>;
+
+export function PrivilegedIsAuthenticated():Promise<boolean>;
+
+export function StartupDisableItem(arg1:string):Promise<void>;
+
+export function StartupEnableItem(arg1:string):Promise<void>;
+
+export function StartupListItems():Promise<Array<models.StartupItem>>;
+
+export function StartupRemoveItem(arg1:string):Promise<void>;
+
 export function StatusGetMetrics():Promise<models.MetricsSnapshot>;
 
 export function StatusStartMonitoring(arg1:number):Promise<void>;
@@ -40,6 +100,14 @@ export function TouchIDGetStatus():Promise<models.TouchIDStatus>;
 
 export function UninstallApps(arg1:Array<string>):Promise<void>;
 
+export function UninstallExportInventory(arg1:string,arg2:string):Promise<string>;
+
+export function UninstallGenerateBrewfile(arg1:string):Promise<string>;
+
+export function UninstallGetDuplicateApps():Promise<Array<models.DuplicateAppGroup>>;
+
 export function UninstallGetRelatedFiles(arg1:string):Promise<Array<string>>;
 
+export function UninstallGetUnusedApps(arg1:number):Promise<Array<models.UnusedApp>>;
+
 export function UninstallScanApps(arg1:boolean):Promise<Array<models.Application>>;
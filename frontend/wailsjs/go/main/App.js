@@ -2,22 +2,94 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AnalyzeCancelScan() {
+  return window['go']['main']['App']['AnalyzeCancelScan']();
+}
+
+export function AnalyzeClearCache() {
+  return window['go']['main']['App']['AnalyzeClearCache']();
+}
+
 export function AnalyzeDeletePath(arg1) {
   return window['go']['main']['App']['AnalyzeDeletePath'](arg1);
 }
 
+export function AnalyzeDeleteSnapshot(arg1) {
+  return window['go']['main']['App']['AnalyzeDeleteSnapshot'](arg1);
+}
+
+export function AnalyzeDiffSnapshots(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['AnalyzeDiffSnapshots'](arg1, arg2, arg3, arg4);
+}
+
+export function AnalyzeForecastVolumeFull(arg1) {
+  return window['go']['main']['App']['AnalyzeForecastVolumeFull'](arg1);
+}
+
+export function AnalyzeGetCacheInfo() {
+  return window['go']['main']['App']['AnalyzeGetCacheInfo']();
+}
+
 export function AnalyzeGetLargeFiles(arg1, arg2) {
   return window['go']['main']['App']['AnalyzeGetLargeFiles'](arg1, arg2);
 }
 
+export function AnalyzeGetScanTree(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AnalyzeGetScanTree'](arg1, arg2, arg3);
+}
+
+export function AnalyzeGetSizeTrends(arg1) {
+  return window['go']['main']['App']['AnalyzeGetSizeTrends'](arg1);
+}
+
+export function AnalyzeListSnapshots(arg1) {
+  return window['go']['main']['App']['AnalyzeListSnapshots'](arg1);
+}
+
 export function AnalyzeOpenInFinder(arg1) {
   return window['go']['main']['App']['AnalyzeOpenInFinder'](arg1);
 }
 
+export function AnalyzeSaveSnapshot(arg1, arg2, arg3) {
+  return window['go']['main']['App']['AnalyzeSaveSnapshot'](arg1, arg2, arg3);
+}
+
 export function AnalyzeScanDirectory(arg1) {
   return window['go']['main']['App']['AnalyzeScanDirectory'](arg1);
 }
 
+export function AnalyzeScanDirectoryWithOptions(arg1, arg2) {
+  return window['go']['main']['App']['AnalyzeScanDirectoryWithOptions'](arg1, arg2);
+}
+
+export function AnalyzeSetNodeBudget(arg1) {
+  return window['go']['main']['App']['AnalyzeSetNodeBudget'](arg1);
+}
+
+export function AnalyzeSunburst(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['AnalyzeSunburst'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function AnalyzeTreemap(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['AnalyzeTreemap'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function AnalyzeWatchCaches(arg1) {
+  return window['go']['main']['App']['AnalyzeWatchCaches'](arg1);
+}
+
+export function CheckApplyFix(arg1) {
+  return window['go']['main']['App']['CheckApplyFix'](arg1);
+}
+
+export function CheckRun(arg1) {
+  return window['go']['main']['App']['CheckRun'](arg1);
+}
+
+export function CheckRunAll() {
+  return window['go']['main']['App']['CheckRunAll']();
+}
+
 export function CleanExecute(arg1, arg2) {
   return window['go']['main']['App']['CleanExecute'](arg1, arg2);
 }
@@ -38,6 +110,10 @@ export function OptimizeExecute(arg1) {
   return window['go']['main']['App']['OptimizeExecute'](arg1);
 }
 
+export function OptimizeGetHistory() {
+  return window['go']['main']['App']['OptimizeGetHistory']();
+}
+
 export function OptimizeGetTasks() {
   return window['go']['main']['App']['OptimizeGetTasks']();
 }
@@ -50,6 +126,50 @@ export function OptimizeUpdateWhitelist(arg1) {
   return window['go']['main']['App']['OptimizeUpdateWhitelist'](arg1);
 }
 
+export function OrphansScan() {
+  return window['go']['main']['App']['OrphansScan']();
+}
+
+export function PreferencesGetRepairLog() {
+  return window['go']['main']['App']['PreferencesGetRepairLog']();
+}
+
+export function PreferencesRepair(arg1) {
+  return window['go']['main']['App']['PreferencesRepair'](arg1);
+}
+
+export function PreferencesRestore(arg1) {
+  return window['go']['main']['App']['PreferencesRestore'](arg1);
+}
+
+export function PreferencesScanBroken() {
+  return window['go']['main']['App']['PreferencesScanBroken']();
+}
+
+export function PrivilegedAuthenticate() {
+  return window['go']['main']['App']['PrivilegedAuthenticate']();
+}
+
+export function PrivilegedIsAuthenticated() {
+  return window['go']['main']['App']['PrivilegedIsAuthenticated']();
+}
+
+export function StartupDisableItem(arg1) {
+  return window['go']['main']['App']['StartupDisableItem'](arg1);
+}
+
+export function StartupEnableItem(arg1) {
+  return window['go']['main']['App']['StartupEnableItem'](arg1);
+}
+
+export function StartupListItems() {
+  return window['go']['main']['App']['StartupListItems']();
+}
+
+export function StartupRemoveItem(arg1) {
+  return window['go']['main']['App']['StartupRemoveItem'](arg1);
+}
+
 export function StatusGetMetrics() {
   return window['go']['main']['App']['StatusGetMetrics']();
 }
@@ -78,10 +198,26 @@ export function UninstallApps(arg1) {
   return window['go']['main']['App']['UninstallApps'](arg1);
 }
 
+export function UninstallExportInventory(arg1, arg2) {
+  return window['go']['main']['App']['UninstallExportInventory'](arg1, arg2);
+}
+
+export function UninstallGenerateBrewfile(arg1) {
+  return window['go']['main']['App']['UninstallGenerateBrewfile'](arg1);
+}
+
+export function UninstallGetDuplicateApps() {
+  return window['go']['main']['App']['UninstallGetDuplicateApps']();
+}
+
 export function UninstallGetRelatedFiles(arg1) {
   return window['go']['main']['App']['UninstallGetRelatedFiles'](arg1);
 }
 
+export function UninstallGetUnusedApps(arg1) {
+  return window['go']['main']['App']['UninstallGetUnusedApps'](arg1);
+}
+
 export function UninstallScanApps(arg1) {
   return window['go']['main']['App']['UninstallScanApps'](arg1);
 }
@@ -3,12 +3,18 @@ export namespace models {
 	export class Application {
 	    name: string;
 	    bundleId: string;
+	    version?: string;
 	    path: string;
 	    size: number;
 	    // Go type: time
 	    lastModified: any;
+	    // Go type: time
+	    lastUsed: any;
 	    age: string;
 	    icon?: string;
+	    source?: string;
+	    caskToken?: string;
+	    caskTap?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new Application(source);
@@ -18,11 +24,16 @@ export namespace models {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.name = source["name"];
 	        this.bundleId = source["bundleId"];
+	        this.version = source["version"];
 	        this.path = source["path"];
 	        this.size = source["size"];
 	        this.lastModified = this.convertValues(source["lastModified"], null);
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
 	        this.age = source["age"];
 	        this.icon = source["icon"];
+	        this.source = source["source"];
+	        this.caskToken = source["caskToken"];
+	        this.caskTap = source["caskTap"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -65,6 +76,53 @@ export namespace models {
 	        this.fanSpeed = source["fanSpeed"];
 	    }
 	}
+	export class BrokenPreference {
+	    path: string;
+	    domain: string;
+	    size: number;
+	    // Go type: time
+	    modTime: any;
+	    reason: string;
+	    error?: string;
+	    container?: string;
+	    ownerApp?: string;
+	    ownerPath?: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new BrokenPreference(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.domain = source["domain"];
+	        this.size = source["size"];
+	        this.modTime = this.convertValues(source["modTime"], null);
+	        this.reason = source["reason"];
+	        this.error = source["error"];
+	        this.container = source["container"];
+	        this.ownerApp = source["ownerApp"];
+	        this.ownerPath = source["ownerPath"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class CPUMetrics {
 	    totalPercent: number;
 	    loadAvg: number[];
@@ -85,12 +143,112 @@ export namespace models {
 	        this.temperature = source["temperature"];
 	    }
 	}
+	export class CheckFix {
+	    description: string;
+	    requiresSudo: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new CheckFix(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.description = source["description"];
+	        this.requiresSudo = source["requiresSudo"];
+	    }
+	}
+	export class CheckResult {
+	    id: string;
+	    name: string;
+	    category: string;
+	    status: string;
+	    severity: string;
+	    message: string;
+	    evidence: string[];
+	    fix?: CheckFix;
+	    durationMs: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CheckResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.category = source["category"];
+	        this.status = source["status"];
+	        this.severity = source["severity"];
+	        this.message = source["message"];
+	        this.evidence = source["evidence"];
+	        this.fix = this.convertValues(source["fix"], CheckFix);
+	        this.durationMs = source["durationMs"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CheckFixResult {
+	    checkId: string;
+	    applied: boolean;
+	    output: string[];
+	    error?: string;
+	    result: CheckResult;
+	
+	    static createFrom(source: any = {}) {
+	        return new CheckFixResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.checkId = source["checkId"];
+	        this.applied = source["applied"];
+	        this.output = source["output"];
+	        this.error = source["error"];
+	        this.result = this.convertValues(source["result"], CheckResult);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
 	export class CleanCategory {
 	    id: string;
 	    name: string;
 	    description: string;
 	    enabled: boolean;
 	    estimatedMB: number;
+	    requiresSudo: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new CleanCategory(source);
@@ -103,6 +261,7 @@ export namespace models {
 	        this.description = source["description"];
 	        this.enabled = source["enabled"];
 	        this.estimatedMB = source["estimatedMB"];
+	        this.requiresSudo = source["requiresSudo"];
 	    }
 	}
 	export class DirEntry {
@@ -113,6 +272,7 @@ export namespace models {
 	    // Go type: time
 	    lastAccess: any;
 	    percent: number;
+	    mount?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new DirEntry(source);
@@ -126,6 +286,7 @@ export namespace models {
 	        this.isDir = source["isDir"];
 	        this.lastAccess = this.convertValues(source["lastAccess"], null);
 	        this.percent = source["percent"];
+	        this.mount = source["mount"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -172,6 +333,79 @@ export namespace models {
 	        this.writeSpeed = source["writeSpeed"];
 	    }
 	}
+	export class DuplicateAppCopy {
+	    app: Application;
+	    // Go type: time
+	    lastUsed: any;
+	    keep: boolean;
+	    reason: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateAppCopy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.app = this.convertValues(source["app"], Application);
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
+	        this.keep = source["keep"];
+	        this.reason = source["reason"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateAppGroup {
+	    name: string;
+	    bundleId: string;
+	    copies: DuplicateAppCopy[];
+	    reclaimable: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateAppGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.bundleId = source["bundleId"];
+	        this.copies = this.convertValues(source["copies"], DuplicateAppCopy);
+	        this.reclaimable = source["reclaimable"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class FileEntry {
 	    name: string;
 	    path: string;
@@ -188,6 +422,46 @@ export namespace models {
 	        this.size = source["size"];
 	    }
 	}
+	export class FileTypeStat {
+	    category: string;
+	    label: string;
+	    size: number;
+	    count: number;
+	    percent: number;
+	    topFiles: FileEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new FileTypeStat(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.label = source["label"];
+	        this.size = source["size"];
+	        this.count = source["count"];
+	        this.percent = source["percent"];
+	        this.topFiles = this.convertValues(source["topFiles"], FileEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
 	export class GPUMetrics {
 	    usage: number;
 	    temperature: number;
@@ -335,6 +609,30 @@ export namespace models {
 		    return a;
 		}
 	}
+	export class MountEntry {
+	    path: string;
+	    label: string;
+	    fsType: string;
+	    kind: string;
+	    size: number;
+	    skipped: boolean;
+	    timedOut: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MountEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.label = source["label"];
+	        this.fsType = source["fsType"];
+	        this.kind = source["kind"];
+	        this.size = source["size"];
+	        this.skipped = source["skipped"];
+	        this.timedOut = source["timedOut"];
+	    }
+	}
 	
 	export class OptimizationTask {
 	    id: string;
@@ -342,6 +640,7 @@ export namespace models {
 	    description: string;
 	    enabled: boolean;
 	    requiresSudo: boolean;
+	    disabledReason?: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new OptimizationTask(source);
@@ -354,27 +653,56 @@ export namespace models {
 	        this.description = source["description"];
 	        this.enabled = source["enabled"];
 	        this.requiresSudo = source["requiresSudo"];
+	        this.disabledReason = source["disabledReason"];
 	    }
 	}
+	export class OptimizeImpactDelta {
+	    metric: string;
+	    label: string;
+	    unit: string;
+	    before: number;
+	    after: number;
+	    delta: number;
+	    improved: boolean;
 	
-	export class ScanResult {
-	    entries: DirEntry[];
-	    largeFiles: FileEntry[];
-	    totalSize: number;
-	    totalItems: number;
-	    path: string;
+	    static createFrom(source: any = {}) {
+	        return new OptimizeImpactDelta(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.metric = source["metric"];
+	        this.label = source["label"];
+	        this.unit = source["unit"];
+	        this.before = source["before"];
+	        this.after = source["after"];
+	        this.delta = source["delta"];
+	        this.improved = source["improved"];
+	    }
+	}
+	export class OptimizeMeasurement {
+	    // Go type: time
+	    takenAt: any;
+	    memoryUsed: number;
+	    swapUsed: number;
+	    diskFree: number;
+	    load1: number;
+	    dnsResolveMs: number;
+	    probes: Record<string, number>;
 	
 	    static createFrom(source: any = {}) {
-	        return new ScanResult(source);
+	        return new OptimizeMeasurement(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.entries = this.convertValues(source["entries"], DirEntry);
-	        this.largeFiles = this.convertValues(source["largeFiles"], FileEntry);
-	        this.totalSize = source["totalSize"];
-	        this.totalItems = source["totalItems"];
-	        this.path = source["path"];
+	        this.takenAt = this.convertValues(source["takenAt"], null);
+	        this.memoryUsed = source["memoryUsed"];
+	        this.swapUsed = source["swapUsed"];
+	        this.diskFree = source["diskFree"];
+	        this.load1 = source["load1"];
+	        this.dnsResolveMs = source["dnsResolveMs"];
+	        this.probes = source["probes"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -395,25 +723,830 @@ export namespace models {
 		    return a;
 		}
 	}
-	export class TouchIDStatus {
-	    enabled: boolean;
-	    available: boolean;
-	    status: string;
-	    pamModulePath: string;
-	    configPath: string;
+	export class OptimizeImpact {
+	    before: OptimizeMeasurement;
+	    after: OptimizeMeasurement;
+	    deltas: OptimizeImpactDelta[];
 	
 	    static createFrom(source: any = {}) {
-	        return new TouchIDStatus(source);
+	        return new OptimizeImpact(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.enabled = source["enabled"];
-	        this.available = source["available"];
-	        this.status = source["status"];
-	        this.pamModulePath = source["pamModulePath"];
-	        this.configPath = source["configPath"];
+	        this.before = this.convertValues(source["before"], OptimizeMeasurement);
+	        this.after = this.convertValues(source["after"], OptimizeMeasurement);
+	        this.deltas = this.convertValues(source["deltas"], OptimizeImpactDelta);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OptimizeHistoryEntry {
+	    // Go type: time
+	    startedAt: any;
+	    taskIds: string[];
+	    tasksCompleted: number;
+	    skipped: string[];
+	    errors: string[];
+	    impact?: OptimizeImpact;
+	
+	    static createFrom(source: any = {}) {
+	        return new OptimizeHistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.startedAt = this.convertValues(source["startedAt"], null);
+	        this.taskIds = source["taskIds"];
+	        this.tasksCompleted = source["tasksCompleted"];
+	        this.skipped = source["skipped"];
+	        this.errors = source["errors"];
+	        this.impact = this.convertValues(source["impact"], OptimizeImpact);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	export class OrphanedItem {
+	    path: string;
+	    bundleId: string;
+	    kind: string;
+	    size: number;
+	    // Go type: time
+	    lastModified: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new OrphanedItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.bundleId = source["bundleId"];
+	        this.kind = source["kind"];
+	        this.size = source["size"];
+	        this.lastModified = this.convertValues(source["lastModified"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OrphanAppGroup {
+	    app: string;
+	    bundleId: string;
+	    size: number;
+	    items: OrphanedItem[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OrphanAppGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.app = source["app"];
+	        this.bundleId = source["bundleId"];
+	        this.size = source["size"];
+	        this.items = this.convertValues(source["items"], OrphanedItem);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class OrphanVendorGroup {
+	    vendor: string;
+	    size: number;
+	    apps: OrphanAppGroup[];
+	
+	    static createFrom(source: any = {}) {
+	        return new OrphanVendorGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.vendor = source["vendor"];
+	        this.size = source["size"];
+	        this.apps = this.convertValues(source["apps"], OrphanAppGroup);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class PreferenceRepair {
+	    id: string;
+	    path: string;
+	    backupPath: string;
+	    reason: string;
+	    ownerApp?: string;
+	    // Go type: time
+	    removedAt: any;
+	    // Go type: time
+	    restoredAt?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new PreferenceRepair(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.path = source["path"];
+	        this.backupPath = source["backupPath"];
+	        this.reason = source["reason"];
+	        this.ownerApp = source["ownerApp"];
+	        this.removedAt = this.convertValues(source["removedAt"], null);
+	        this.restoredAt = this.convertValues(source["restoredAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScanCacheEntry {
+	    path: string;
+	    bytes: number;
+	    // Go type: time
+	    created: any;
+	    // Go type: time
+	    lastUsed: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanCacheEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.bytes = source["bytes"];
+	        this.created = this.convertValues(source["created"], null);
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanCacheInfo {
+	    dir: string;
+	    bytes: number;
+	    budget: number;
+	    entries: ScanCacheEntry[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanCacheInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.dir = source["dir"];
+	        this.bytes = source["bytes"];
+	        this.budget = source["budget"];
+	        this.entries = this.convertValues(source["entries"], ScanCacheEntry);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanDiffEntry {
+	    name: string;
+	    path: string;
+	    isDir: boolean;
+	    isOther: boolean;
+	    change: string;
+	    oldSize: number;
+	    newSize: number;
+	    delta: number;
+	    drillable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanDiffEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.isDir = source["isDir"];
+	        this.isOther = source["isOther"];
+	        this.change = source["change"];
+	        this.oldSize = source["oldSize"];
+	        this.newSize = source["newSize"];
+	        this.delta = source["delta"];
+	        this.drillable = source["drillable"];
+	    }
+	}
+	export class ScanOptions {
+	    maxEntries: number;
+	    maxLargeFiles: number;
+	    minLargeFileSize: number;
+	    followSymlinks: boolean;
+	    stayOnFilesystem: boolean;
+	    skipRemoteMounts: boolean;
+	    remoteDirTimeoutMs: number;
+	    foldPatterns: string[];
+	    skipPatterns: string[];
+	    maxWorkers: number;
+	    nodeBudget: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.maxEntries = source["maxEntries"];
+	        this.maxLargeFiles = source["maxLargeFiles"];
+	        this.minLargeFileSize = source["minLargeFileSize"];
+	        this.followSymlinks = source["followSymlinks"];
+	        this.stayOnFilesystem = source["stayOnFilesystem"];
+	        this.skipRemoteMounts = source["skipRemoteMounts"];
+	        this.remoteDirTimeoutMs = source["remoteDirTimeoutMs"];
+	        this.foldPatterns = source["foldPatterns"];
+	        this.skipPatterns = source["skipPatterns"];
+	        this.maxWorkers = source["maxWorkers"];
+	        this.nodeBudget = source["nodeBudget"];
+	    }
+	}
+	export class ScanSnapshot {
+	    id: string;
+	    name: string;
+	    path: string;
+	    // Go type: time
+	    createdAt: any;
+	    totalSize: number;
+	    files: number;
+	    options: ScanOptions;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanSnapshot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	        this.totalSize = source["totalSize"];
+	        this.files = source["files"];
+	        this.options = this.convertValues(source["options"], ScanOptions);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanDiff {
+	    path: string;
+	    from: ScanSnapshot;
+	    to: ScanSnapshot;
+	    oldSize: number;
+	    newSize: number;
+	    delta: number;
+	    entries: ScanDiffEntry[];
+	    omitted: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanDiff(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.path = source["path"];
+	        this.from = this.convertValues(source["from"], ScanSnapshot);
+	        this.to = this.convertValues(source["to"], ScanSnapshot);
+	        this.oldSize = source["oldSize"];
+	        this.newSize = source["newSize"];
+	        this.delta = source["delta"];
+	        this.entries = this.convertValues(source["entries"], ScanDiffEntry);
+	        this.omitted = source["omitted"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScanNode {
+	    name: string;
+	    path: string;
+	    size: number;
+	    files: number;
+	    isDir: boolean;
+	    isOther: boolean;
+	    collapsed: boolean;
+	    childCount: number;
+	    mount?: string;
+	    children?: ScanNode[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanNode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.files = source["files"];
+	        this.isDir = source["isDir"];
+	        this.isOther = source["isOther"];
+	        this.collapsed = source["collapsed"];
+	        this.childCount = source["childCount"];
+	        this.mount = source["mount"];
+	        this.children = this.convertValues(source["children"], ScanNode);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class ScanResult {
+	    entries: DirEntry[];
+	    largeFiles: FileEntry[];
+	    types: FileTypeStat[];
+	    mounts: MountEntry[];
+	    totalSize: number;
+	    totalItems: number;
+	    path: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.entries = this.convertValues(source["entries"], DirEntry);
+	        this.largeFiles = this.convertValues(source["largeFiles"], FileEntry);
+	        this.types = this.convertValues(source["types"], FileTypeStat);
+	        this.mounts = this.convertValues(source["mounts"], MountEntry);
+	        this.totalSize = source["totalSize"];
+	        this.totalItems = source["totalItems"];
+	        this.path = source["path"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class SizeSample {
+	    date: string;
+	    size: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeSample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.size = source["size"];
+	    }
+	}
+	export class SizeTrend {
+	    name: string;
+	    path: string;
+	    currentSize: number;
+	    change7d: number;
+	    change30d: number;
+	    bytesPerDay: number;
+	    samples: SizeSample[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SizeTrend(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.currentSize = source["currentSize"];
+	        this.change7d = source["change7d"];
+	        this.change30d = source["change30d"];
+	        this.bytesPerDay = source["bytesPerDay"];
+	        this.samples = this.convertValues(source["samples"], SizeSample);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StartupItem {
+	    id: string;
+	    label: string;
+	    kind: string;
+	    scope: string;
+	    path: string;
+	    program: string;
+	    arguments: string[];
+	    runAtLoad: boolean;
+	    keepAlive: boolean;
+	    enabled: boolean;
+	    programExists: boolean;
+	    ownerApp?: string;
+	    editable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new StartupItem(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.label = source["label"];
+	        this.kind = source["kind"];
+	        this.scope = source["scope"];
+	        this.path = source["path"];
+	        this.program = source["program"];
+	        this.arguments = source["arguments"];
+	        this.runAtLoad = source["runAtLoad"];
+	        this.keepAlive = source["keepAlive"];
+	        this.enabled = source["enabled"];
+	        this.programExists = source["programExists"];
+	        this.ownerApp = source["ownerApp"];
+	        this.editable = source["editable"];
+	    }
+	}
+	export class SunburstArc {
+	    name: string;
+	    path: string;
+	    size: number;
+	    depth: number;
+	    startAngle: number;
+	    endAngle: number;
+	    innerRadius: number;
+	    outerRadius: number;
+	    isDir: boolean;
+	    isOther: boolean;
+	    collapsed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new SunburstArc(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.depth = source["depth"];
+	        this.startAngle = source["startAngle"];
+	        this.endAngle = source["endAngle"];
+	        this.innerRadius = source["innerRadius"];
+	        this.outerRadius = source["outerRadius"];
+	        this.isDir = source["isDir"];
+	        this.isOther = source["isOther"];
+	        this.collapsed = source["collapsed"];
+	    }
+	}
+	export class TouchIDStatus {
+	    enabled: boolean;
+	    available: boolean;
+	    status: string;
+	    pamModulePath: string;
+	    configPath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new TouchIDStatus(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.enabled = source["enabled"];
+	        this.available = source["available"];
+	        this.status = source["status"];
+	        this.pamModulePath = source["pamModulePath"];
+	        this.configPath = source["configPath"];
+	    }
+	}
+	export class TreemapRect {
+	    name: string;
+	    path: string;
+	    size: number;
+	    depth: number;
+	    x: number;
+	    y: number;
+	    width: number;
+	    height: number;
+	    isDir: boolean;
+	    isOther: boolean;
+	    collapsed: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TreemapRect(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.path = source["path"];
+	        this.size = source["size"];
+	        this.depth = source["depth"];
+	        this.x = source["x"];
+	        this.y = source["y"];
+	        this.width = source["width"];
+	        this.height = source["height"];
+	        this.isDir = source["isDir"];
+	        this.isOther = source["isOther"];
+	        this.collapsed = source["collapsed"];
+	    }
+	}
+	export class UnusedApp {
+	    app: Application;
+	    // Go type: time
+	    lastUsed: any;
+	    useCount: number;
+	    usageKnown: boolean;
+	    monthsUnused: number;
+	    score: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new UnusedApp(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.app = this.convertValues(source["app"], Application);
+	        this.lastUsed = this.convertValues(source["lastUsed"], null);
+	        this.useCount = source["useCount"];
+	        this.usageKnown = source["usageKnown"];
+	        this.monthsUnused = source["monthsUnused"];
+	        this.score = source["score"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class VolumeSample {
+	    date: string;
+	    used: number;
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new VolumeSample(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.date = source["date"];
+	        this.used = source["used"];
+	        this.total = source["total"];
+	    }
+	}
+	export class VolumeForecast {
+	    mount: string;
+	    total: number;
+	    used: number;
+	    free: number;
+	    bytesPerDay: number;
+	    daysUntilFull: number;
+	    // Go type: time
+	    fullAt?: any;
+	    samples: VolumeSample[];
+	
+	    static createFrom(source: any = {}) {
+	        return new VolumeForecast(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.mount = source["mount"];
+	        this.total = source["total"];
+	        this.used = source["used"];
+	        this.free = source["free"];
+	        this.bytesPerDay = source["bytesPerDay"];
+	        this.daysUntilFull = source["daysUntilFull"];
+	        this.fullAt = this.convertValues(source["fullAt"], null);
+	        this.samples = this.convertValues(source["samples"], VolumeSample);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
 
 }
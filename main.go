@@ -2,16 +2,27 @@ package main
 
 import (
 	"embed"
+	"os"
 
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
+	"mole-wails/backend/privileged"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
 func main() {
+	// Started through sudo by privileged.Broker; serve root commands instead of the UI
+	if len(os.Args) == 3 && os.Args[1] == privileged.HelperFlag {
+		if err := privileged.Serve(os.Args[2], os.Stdin); err != nil {
+			println("Error:", err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create an instance of the app structure
 	app := NewApp()
 
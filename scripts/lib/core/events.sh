@@ -0,0 +1,92 @@
+#!/bin/bash
+# Mole - Structured Events
+# JSON-lines progress events for the desktop app
+#
+# Enabled when the caller sets MOLE_EVENTS=jsonl. Events are written one JSON
+# object per line to file descriptor MOLE_EVENT_FD (default 3), keeping stdout
+# free for the human-readable output.
+#
+# Event types:
+#   hello         {"type":"hello","version":1}
+#   task_start    {"type":"task_start","task":"<id>","name":"<label>"}
+#   task_finish   {"type":"task_finish","task":"<id>","status":"completed|failed|skipped","message":"..."}
+#   file_removed  {"type":"file_removed","path":"<path>","size":<bytes>}
+#   warning       {"type":"warning","message":"..."}
+#   error         {"type":"error","message":"..."}
+
+set -euo pipefail
+
+# Prevent multiple sourcing
+if [[ -n "${MOLE_EVENTS_LOADED:-}" ]]; then
+    return 0
+fi
+readonly MOLE_EVENTS_LOADED=1
+
+readonly MOLE_EVENT_PROTOCOL_VERSION=1
+
+# Check whether structured events are requested and the descriptor is open
+events_enabled() {
+    [[ "${MOLE_EVENTS:-}" == "jsonl" ]] || return 1
+    local fd="${MOLE_EVENT_FD:-3}"
+    [[ "$fd" =~ ^[0-9]+$ ]] || return 1
+    { true >&"$fd"; } 2> /dev/null
+}
+
+# Escape a string for use inside a JSON string literal
+json_escape() {
+    local s="$1"
+    s="${s//\\/\\\\}"
+    s="${s//\"/\\\"}"
+    s="${s//$'\n'/\\n}"
+    s="${s//$'\r'/\\r}"
+    s="${s//$'\t'/\\t}"
+    # Strip remaining control characters such as ANSI colour escapes
+    s="$(printf '%s' "$s" | sed $'s/\x1b\\[[0-9;]*[A-Za-z]//g' | tr -d '\000-\010\013\014\016-\037')"
+    printf '%s' "$s"
+}
+
+# Write one event: emit_event <type> [key value]...
+# Values that are plain integers are written as JSON numbers
+emit_event() {
+    events_enabled || return 0
+
+    local type="$1"
+    shift
+    local line="{\"type\":\"$(json_escape "$type")\""
+    while [[ $# -ge 2 ]]; do
+        local key="$1" value="$2"
+        shift 2
+        if [[ "$value" =~ ^-?[0-9]+$ ]]; then
+            line+=",\"$(json_escape "$key")\":$value"
+        else
+            line+=",\"$(json_escape "$key")\":\"$(json_escape "$value")\""
+        fi
+    done
+    line+="}"
+
+    printf '%s\n' "$line" >&"${MOLE_EVENT_FD:-3}" 2> /dev/null || true
+}
+
+event_task_start() {
+    emit_event task_start task "$1" name "${2:-$1}"
+}
+
+# event_task_finish <task> <completed|failed|skipped> [message]
+event_task_finish() {
+    emit_event task_finish task "$1" status "$2" message "${3:-}"
+}
+
+event_file_removed() {
+    emit_event file_removed path "$1" size "${2:-0}"
+}
+
+event_warning() {
+    emit_event warning message "$1"
+}
+
+event_error() {
+    emit_event error message "$1"
+}
+
+# Announce protocol support so readers can skip legacy text parsing
+emit_event hello version "$MOLE_EVENT_PROTOCOL_VERSION"
@@ -96,8 +96,13 @@ safe_remove() {
 
     debug_log "Removing: $path"
 
+    # Size is only measured when someone is listening for events
+    local size_kb=0
+    events_enabled && size_kb=$(get_path_size_kb "$path")
+
     # Perform the deletion
     if rm -rf "$path" 2> /dev/null; then # SAFE: safe_remove implementation
+        event_file_removed "$path" "$((size_kb * 1024))"
         return 0
     else
         [[ "$silent" != "true" ]] && log_error "Failed to remove: $path"
@@ -128,8 +133,12 @@ safe_sudo_remove() {
 
     debug_log "Removing (sudo): $path"
 
+    local size_kb=0
+    events_enabled && size_kb=$(sudo du -sk "$path" 2> /dev/null | awk '{print $1}')
+
     # Perform the deletion
     if sudo rm -rf "$path" 2> /dev/null; then # SAFE: safe_sudo_remove implementation
+        event_file_removed "$path" "$((${size_kb:-0} * 1024))"
         return 0
     else
         log_error "Failed to remove (sudo): $path"
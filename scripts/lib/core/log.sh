@@ -17,6 +17,12 @@ if [[ -z "${MOLE_BASE_LOADED:-}" ]]; then
     source "$_MOLE_CORE_DIR/base.sh"
 fi
 
+# Structured events mirror warnings and errors for the desktop app
+if [[ -z "${MOLE_EVENTS_LOADED:-}" ]]; then
+    # shellcheck source=lib/core/events.sh
+    source "$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)/events.sh"
+fi
+
 # ============================================================================
 # Logging Configuration
 # ============================================================================
@@ -72,6 +78,7 @@ log_success() {
 # Log warning message
 log_warning() {
     echo -e "${YELLOW}$1${NC}"
+    event_warning "$1"
     local timestamp=$(date '+%Y-%m-%d %H:%M:%S')
     echo "[$timestamp] WARNING: $1" >> "$LOG_FILE" 2> /dev/null || true
     if [[ "${MO_DEBUG:-}" == "1" ]]; then
@@ -82,6 +89,7 @@ log_warning() {
 # Log error message
 log_error() {
     echo -e "${RED}${ICON_ERROR}${NC} $1" >&2
+    event_error "$1"
     local timestamp=$(date '+%Y-%m-%d %H:%M:%S')
     echo "[$timestamp] ERROR: $1" >> "$LOG_FILE" 2> /dev/null || true
     if [[ "${MO_DEBUG:-}" == "1" ]]; then
@@ -353,6 +353,7 @@ batch_uninstall_applications() {
         local related_files=$(decode_file_list "$encoded_files" "$app_name")
         local system_files=$(decode_file_list "$encoded_system_files" "$app_name")
         local reason=""
+        event_task_start "${bundle_id:-$app_name}" "$app_name"
 
         # Note: needs_sudo is already calculated during scanning phase (performance optimization)
 
@@ -413,9 +414,11 @@ batch_uninstall_applications() {
             ((files_cleaned++))
             ((total_items++))
             success_items+=("$app_name")
+            event_task_finish "${bundle_id:-$app_name}" completed
         else
             ((failed_count++))
             failed_items+=("$app_name:$reason")
+            event_task_finish "${bundle_id:-$app_name}" failed "$reason"
         fi
     done
 